@@ -0,0 +1,150 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// accessList holds the devices that may use the server in ACL mode, and
+// the devices each of them may look up.
+type accessList struct {
+	lookups map[protocol.DeviceID][]protocol.DeviceID // device -> devices it may look up, nil for all
+}
+
+// parseAccessList reads an access list. Each line holds the ID of a device
+// that may announce and look up, optionally followed by the IDs of the
+// devices it may look up. Without those it may look up any device in the
+// list. Empty lines and anything after a # are ignored.
+func parseAccessList(r io.Reader) (*accessList, error) {
+	acl := &accessList{lookups: make(map[protocol.DeviceID][]protocol.DeviceID)}
+	sc := bufio.NewScanner(r)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		ids := make([]protocol.DeviceID, len(fields))
+		for i, field := range fields {
+			id, err := protocol.DeviceIDFromString(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			ids[i] = id
+		}
+		if _, ok := acl.lookups[ids[0]]; ok {
+			return nil, fmt.Errorf("line %d: duplicate device %s", lineNo, ids[0])
+		}
+		var targets []protocol.DeviceID
+		if len(ids) > 1 {
+			targets = ids[1:]
+		}
+		acl.lookups[ids[0]] = targets
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return acl, nil
+}
+
+// allows returns whether the device may announce and look up.
+func (a *accessList) allows(device protocol.DeviceID) bool {
+	_, ok := a.lookups[device]
+	return ok
+}
+
+// allowsLookup returns whether the requester may look up the target.
+func (a *accessList) allowsLookup(requester, target protocol.DeviceID) bool {
+	targets, ok := a.lookups[requester]
+	if !ok || !a.allows(target) {
+		return false
+	}
+	return targets == nil || slices.Contains(targets, target)
+}
+
+// accessControl keeps the access list loaded from a file, reloading it on
+// SIGHUP and on a POST to its HTTP handler. A list that fails to load
+// leaves the previous one in place.
+type accessControl struct {
+	path string
+	list atomic.Pointer[accessList]
+}
+
+func newAccessControl(path string) (*accessControl, error) {
+	ac := &accessControl{path: path}
+	if err := ac.reload(); err != nil {
+		return nil, err
+	}
+	return ac, nil
+}
+
+func (ac *accessControl) reload() error {
+	fd, err := os.Open(ac.path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	list, err := parseAccessList(fd)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ac.path, err)
+	}
+	ac.list.Store(list)
+	log.Printf("Loaded access list with %d devices", len(list.lookups))
+	return nil
+}
+
+func (ac *accessControl) allows(device protocol.DeviceID) bool {
+	return ac.list.Load().allows(device)
+}
+
+func (ac *accessControl) allowsLookup(requester, target protocol.DeviceID) bool {
+	return ac.list.Load().allowsLookup(requester, target)
+}
+
+func (ac *accessControl) Serve(ctx context.Context) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-hup:
+			if err := ac.reload(); err != nil {
+				log.Println("Reloading access list:", err)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// ServeHTTP reloads the access list on POST.
+func (ac *accessControl) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := ac.reload(); err != nil {
+		log.Println("Reloading access list:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/tlsutil"
+)
+
+func TestParseAccessList(t *testing.T) {
+	a := protocol.DeviceID{1}
+	b := protocol.DeviceID{2}
+	c := protocol.DeviceID{3}
+	d := protocol.DeviceID{4}
+
+	acl, err := parseAccessList(strings.NewReader(fmt.Sprintf("# devices\n%s\n\n%s %s # only a\n%s\n", a, b, a, c)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		requester, target protocol.DeviceID
+		allowed           bool
+	}{
+		{a, b, true},
+		{a, c, true},
+		{b, a, true},
+		{b, c, false},
+		{a, d, false},
+		{d, a, false},
+	}
+	for _, tc := range cases {
+		if allowed := acl.allowsLookup(tc.requester, tc.target); allowed != tc.allowed {
+			t.Errorf("%s looking up %s: got %v, expected %v", tc.requester.Short(), tc.target.Short(), allowed, tc.allowed)
+		}
+	}
+	if acl.allows(d) {
+		t.Error("unlisted device should not be allowed")
+	}
+
+	if _, err := parseAccessList(strings.NewReader("not-a-device-id\n")); err == nil {
+		t.Error("expected an error for an invalid device ID")
+	}
+	if _, err := parseAccessList(strings.NewReader(fmt.Sprintf("%s\n%s %s\n", a, a, b))); err == nil {
+		t.Error("expected an error for a duplicate device")
+	}
+}
+
+func TestAccessControlRequests(t *testing.T) {
+	dir := t.TempDir()
+	cert := func(name string) (tls.Certificate, string) {
+		crt, err := tlsutil.NewCertificate(filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key"), name, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crt, base64.StdEncoding.EncodeToString(crt.Certificate[0])
+	}
+	allowedCert, allowedHdr := cert("allowed")
+	_, strangerHdr := cert("stranger")
+	allowed := protocol.NewDeviceID(allowedCert.Certificate[0])
+
+	aclFile := filepath.Join(dir, "acl")
+	if err := os.WriteFile(aclFile, []byte(allowed.String()+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	acl, err := newAccessControl(aclFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := newInMemoryStore(t.TempDir(), 0, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go db.Serve(ctx)
+	api := newAPISrv("127.0.0.1:0", tls.Certificate{}, db, nil, acl, true, false)
+	srv := httptest.NewServer(http.HandlerFunc(api.handler))
+	defer srv.Close()
+
+	request := func(method, device, certHdr, body string) int {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+"/v2/?device="+device, strings.NewReader(body))
+		if certHdr != "" {
+			req.Header.Set("X-Tls-Client-Cert-Der-Base64", certHdr)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	const ann = `{"addresses":["tcp://10.10.10.10:42000"]}`
+	if code := request(http.MethodPost, "", strangerHdr, ann); code != http.StatusForbidden {
+		t.Error("announcement from unlisted device: got status", code)
+	}
+	if code := request(http.MethodPost, "", allowedHdr, ann); code != http.StatusNoContent {
+		t.Error("announcement from listed device: got status", code)
+	}
+	if code := request(http.MethodGet, allowed.String(), "", ""); code != http.StatusForbidden {
+		t.Error("lookup without certificate: got status", code)
+	}
+	if code := request(http.MethodGet, allowed.String(), strangerHdr, ""); code != http.StatusForbidden {
+		t.Error("lookup from unlisted device: got status", code)
+	}
+	if code := request(http.MethodGet, protocol.DeviceID{1}.String(), allowedHdr, ""); code != http.StatusNotFound {
+		t.Error("lookup of unlisted device: got status", code)
+	}
+	if code := request(http.MethodGet, allowed.String(), allowedHdr, ""); code != http.StatusOK {
+		t.Error("lookup from listed device: got status", code)
+	}
+
+	// Emptying the list and reloading it through the API locks everyone
+	// out.
+	if err := os.WriteFile(aclFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	acl.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/acl/reload", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatal("reload: got status", rec.Code)
+	}
+	if code := request(http.MethodGet, allowed.String(), allowedHdr, ""); code != http.StatusForbidden {
+		t.Error("lookup after removal from list: got status", code)
+	}
+}
@@ -44,7 +44,8 @@ type apiSrv struct {
 	cert           tls.Certificate
 	db             database
 	listener       net.Listener
-	repl           replicator // optional
+	repl           replicator     // optional
+	acl            *accessControl // optional
 	useHTTP        bool
 	compression    bool
 	gzipWriters    sync.Pool
@@ -66,12 +67,13 @@ type contextKey int
 
 const idKey contextKey = iota
 
-func newAPISrv(addr string, cert tls.Certificate, db database, repl replicator, useHTTP, compression bool) *apiSrv {
+func newAPISrv(addr string, cert tls.Certificate, db database, repl replicator, acl *accessControl, useHTTP, compression bool) *apiSrv {
 	return &apiSrv{
 		addr:        addr,
 		cert:        cert,
 		db:          db,
 		repl:        repl,
+		acl:         acl,
 		useHTTP:     useHTTP,
 		compression: compression,
 		seenTracker: &retryAfterTracker{
@@ -206,6 +208,10 @@ func (s *apiSrv) handleGET(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if s.acl != nil && !s.allowLookup(w, req, deviceID) {
+		return
+	}
+
 	rec, err := s.db.get(&deviceID)
 	if err != nil {
 		// some sort of internal error
@@ -281,6 +287,16 @@ func (s *apiSrv) handlePOST(remoteAddr *net.TCPAddr, w http.ResponseWriter, req
 
 	deviceID := protocol.NewDeviceID(rawCert)
 
+	if s.acl != nil && !s.acl.allows(deviceID) {
+		if debug {
+			log.Println(reqID, "not in access list:", deviceID)
+		}
+		announceRequestsTotal.WithLabelValues("forbidden").Inc()
+		w.Header().Set("Retry-After", errorRetryAfterString())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
 	addresses := fixupAddresses(remoteAddr, ann.Addresses)
 	if len(addresses) == 0 {
 		if debug {
@@ -311,6 +327,45 @@ func (s *apiSrv) handlePOST(remoteAddr *net.TCPAddr, w http.ResponseWriter, req
 	}
 }
 
+// allowLookup checks that the requesting device may look up the target,
+// answering the request if not. A target that may not be looked up is not
+// found, so as not to tell whether it exists.
+func (s *apiSrv) allowLookup(w http.ResponseWriter, req *http.Request, target protocol.DeviceID) bool {
+	reqID := req.Context().Value(idKey).(requestID)
+
+	rawCert, err := certificateBytes(req)
+	if err != nil {
+		if debug {
+			log.Println(reqID, "no certificates:", err)
+		}
+		lookupRequestsTotal.WithLabelValues("no_certificate").Inc()
+		w.Header().Set("Retry-After", errorRetryAfterString())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+
+	requester := protocol.NewDeviceID(rawCert)
+	switch {
+	case !s.acl.allows(requester):
+		if debug {
+			log.Println(reqID, "not in access list:", requester)
+		}
+		lookupRequestsTotal.WithLabelValues("forbidden").Inc()
+		w.Header().Set("Retry-After", errorRetryAfterString())
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	case !s.acl.allowsLookup(requester, target):
+		if debug {
+			log.Println(reqID, requester, "may not look up", target)
+		}
+		lookupRequestsTotal.WithLabelValues("forbidden").Inc()
+		w.Header().Set("Retry-After", strconv.Itoa(s.notSeenTracker.retryAfterS()))
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
 func (s *apiSrv) Stop() {
 	s.listener.Close()
 }
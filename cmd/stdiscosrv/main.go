@@ -76,6 +76,8 @@ type CLI struct {
 
 	AMQPAddress string `group:"AMQP replication" hidden:"true" help:"Address to AMQP broker" env:"DISCOVERY_AMQP_ADDRESS"`
 
+	ACLFile string `name:"acl-file" group:"Access control" help:"File listing the devices that may announce and look up; lookups then require a client certificate. Reloaded on SIGHUP and on POST to /acl/reload on the metrics listener" env:"DISCOVERY_ACL_FILE"`
+
 	Debug   bool `short:"d" help:"Print debug output" env:"DISCOVERY_DEBUG"`
 	Version bool `short:"v" help:"Print version and exit"`
 }
@@ -140,8 +142,19 @@ func main() {
 		repl = kr
 	}
 
+	// If we have an access list, only serve the devices on it.
+	var acl *accessControl
+	if cli.ACLFile != "" {
+		var err error
+		acl, err = newAccessControl(cli.ACLFile)
+		if err != nil {
+			log.Fatalln("Failed to load access list:", err)
+		}
+		main.Add(acl)
+	}
+
 	// Start the main API server.
-	qs := newAPISrv(cli.Listen, cert, db, repl, cli.HTTP, cli.Compression)
+	qs := newAPISrv(cli.Listen, cert, db, repl, acl, cli.HTTP, cli.Compression)
 	main.Add(qs)
 
 	// If we have a metrics port configured, start a metrics handler.
@@ -149,6 +162,9 @@ func main() {
 		go func() {
 			mux := http.NewServeMux()
 			mux.Handle("/metrics", promhttp.Handler())
+			if acl != nil {
+				mux.Handle("/acl/reload", acl)
+			}
 			log.Fatal(http.ListenAndServe(cli.MetricsListen, mux))
 		}()
 	}
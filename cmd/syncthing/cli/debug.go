@@ -39,7 +39,7 @@ func (p *profileCommand) Run(ctx Context) error {
 }
 
 type debugCommand struct {
-	File    fileCommand    `cmd:"" help:"Show information about a file (or directory/symlink)"`
+	File    fileCommand    `cmd:"" help:"Show information about a file (or directory/symlink), including its recent history"`
 	Profile profileCommand `cmd:"" help:"Save a profile to help figuring out what Syncthing does"`
 	Index   indexCommand   `cmd:"" help:"Show information about the index (database)"`
 }
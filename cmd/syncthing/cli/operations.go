@@ -31,6 +31,7 @@ type operationCommand struct {
 	Upgrade        struct{}              `cmd:"" help:"Upgrade syncthing (if a newer version is available)"`
 	FolderOverride folderOverrideCommand `cmd:"" help:"Override changes on folder (remote for sendonly, local for receiveonly). WARNING: Destructive - deletes/changes your data"`
 	DefaultIgnores defaultIgnoresCommand `cmd:"" help:"Set the default ignores (config) from a file"`
+	Snapshot       snapshotCommand       `cmd:"" help:"List, create, diff or restore point-in-time folder snapshots"`
 }
 
 func (*operationCommand) Run(ctx Context, kongCtx *kong.Context) error {
@@ -0,0 +1,62 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package cli
+
+import (
+	"net/url"
+	"strconv"
+)
+
+type searchCommand struct {
+	Name           string   `arg:"" optional:"" help:"Glob pattern matched against file names, e.g. \"*.psd\""`
+	Folder         []string `help:"Search only the given folder (repeatable)"`
+	Regex          string   `help:"Regular expression matched against the path within the folder"`
+	MinSize        int64    `help:"Minimum size in bytes"`
+	MaxSize        int64    `help:"Maximum size in bytes"`
+	ModifiedAfter  string   `placeholder:"TIME" help:"Only files modified at or after the given RFC 3339 time"`
+	ModifiedBefore string   `placeholder:"TIME" help:"Only files modified at or before the given RFC 3339 time"`
+	Type           []string `enum:"file,directory,symlink" help:"Only files of the given type (repeatable): file, directory or symlink"`
+	Have           string   `placeholder:"DEVICE" help:"Only files of which the given device has the latest version"`
+	Need           string   `placeholder:"DEVICE" help:"Only files that the given device needs"`
+	Local          bool     `help:"Search the files as they are on this device rather than the latest versions"`
+	Offset         int      `help:"Skip this many results"`
+	Limit          int      `default:"100" help:"Return at most this many results (0 for all)"`
+}
+
+func (s *searchCommand) Run(ctx Context) error {
+	indexDumpOutput := indexDumpOutputWrapper(ctx.clientFactory)
+
+	query := make(url.Values)
+	query["folder"] = s.Folder
+	query["type"] = s.Type
+	for key, val := range map[string]string{
+		"name":           s.Name,
+		"regex":          s.Regex,
+		"modifiedafter":  s.ModifiedAfter,
+		"modifiedbefore": s.ModifiedBefore,
+		"have":           s.Have,
+		"need":           s.Need,
+	} {
+		if val != "" {
+			query.Set(key, val)
+		}
+	}
+	if s.MinSize > 0 {
+		query.Set("minsize", strconv.FormatInt(s.MinSize, 10))
+	}
+	if s.MaxSize > 0 {
+		query.Set("maxsize", strconv.FormatInt(s.MaxSize, 10))
+	}
+	if s.Local {
+		query.Set("local", "true")
+	}
+	if s.Offset > 0 {
+		query.Set("offset", strconv.Itoa(s.Offset))
+	}
+	query.Set("limit", strconv.Itoa(s.Limit))
+	return indexDumpOutput("db/search?" + query.Encode())
+}
@@ -11,13 +11,14 @@ import (
 )
 
 type showCommand struct {
-	Version      struct{}       `cmd:"" help:"Show syncthing client version"`
-	ConfigStatus struct{}       `cmd:"" help:"Show configuration status, whether or not a restart is required for changes to take effect"`
-	System       struct{}       `cmd:"" help:"Show system status"`
-	Connections  struct{}       `cmd:"" help:"Report about connections to other devices"`
-	Discovery    struct{}       `cmd:"" help:"Show the discovered addresses of remote devices (from cache of the running syncthing instance)"`
-	Usage        struct{}       `cmd:"" help:"Show usage report"`
-	Pending      pendingCommand `cmd:"" help:"Pending subcommand group"`
+	Version      struct{}        `cmd:"" help:"Show syncthing client version"`
+	ConfigStatus struct{}        `cmd:"" help:"Show configuration status, whether or not a restart is required for changes to take effect"`
+	System       struct{}        `cmd:"" help:"Show system status"`
+	Connections  struct{}        `cmd:"" help:"Report about connections to other devices"`
+	Discovery    struct{}        `cmd:"" help:"Show the discovered addresses of remote devices (from cache of the running syncthing instance)"`
+	Usage        struct{}        `cmd:"" help:"Show usage report"`
+	Pending      pendingCommand  `cmd:"" help:"Pending subcommand group"`
+	Transfer     transferCommand `cmd:"" help:"Show the file data transferred per device and folder by hour, day or month"`
 }
 
 func (*showCommand) Run(ctx Context, kongCtx *kong.Context) error {
@@ -0,0 +1,84 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package cli
+
+import (
+	"net/url"
+)
+
+type snapshotFolderCommand struct {
+	FolderID string `arg:""`
+}
+
+type snapshotIDCommand struct {
+	FolderID   string `arg:""`
+	SnapshotID string `arg:""`
+}
+
+type snapshotListCommand struct {
+	snapshotFolderCommand
+}
+
+type snapshotCreateCommand struct {
+	snapshotFolderCommand
+}
+
+type snapshotDiffCommand struct {
+	snapshotIDCommand
+}
+
+type snapshotRestoreCommand struct {
+	snapshotIDCommand
+}
+
+type snapshotCommand struct {
+	List    snapshotListCommand    `cmd:"" help:"List the snapshots of a folder"`
+	Create  snapshotCreateCommand  `cmd:"" help:"Take a snapshot of a folder now"`
+	Diff    snapshotDiffCommand    `cmd:"" help:"Show the changes in a folder since a snapshot"`
+	Restore snapshotRestoreCommand `cmd:"" help:"Roll a folder back to a snapshot. Files added since are moved to the versioner"`
+}
+
+func (s *snapshotFolderCommand) query() string {
+	query := make(url.Values)
+	query.Set("folder", s.FolderID)
+	return query.Encode()
+}
+
+func (s *snapshotIDCommand) query() string {
+	query := make(url.Values)
+	query.Set("folder", s.FolderID)
+	query.Set("snapshot", s.SnapshotID)
+	return query.Encode()
+}
+
+func (s *snapshotListCommand) Run(ctx Context) error {
+	return indexDumpOutput("folder/snapshots?"+s.query(), ctx.clientFactory)
+}
+
+func (s *snapshotCreateCommand) Run(ctx Context) error {
+	return snapshotPost("folder/snapshots?"+s.query(), ctx.clientFactory)
+}
+
+func (s *snapshotDiffCommand) Run(ctx Context) error {
+	return indexDumpOutput("folder/snapshots/diff?"+s.query(), ctx.clientFactory)
+}
+
+func (s *snapshotRestoreCommand) Run(ctx Context) error {
+	return snapshotPost("folder/snapshots/restore?"+s.query(), ctx.clientFactory)
+}
+
+func snapshotPost(url string, apiClientFactory *apiClientFactory) error {
+	client, err := apiClientFactory.getClient()
+	if err != nil {
+		return err
+	}
+	response, err := client.Post(url, "")
+	if err != nil {
+		return err
+	}
+	return prettyPrintResponse(response)
+}
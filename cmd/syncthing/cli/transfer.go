@@ -0,0 +1,37 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package cli
+
+import (
+	"net/url"
+)
+
+type transferCommand struct {
+	Period string   `help:"Granularity of the history" enum:"hour,day,month" default:"day"`
+	Folder []string `help:"Only show the given folders"`
+	Device []string `help:"Only show the given devices"`
+	Since  string   `help:"Only show periods starting at or after the given time (RFC 3339)"`
+	Until  string   `help:"Only show periods starting before the given time (RFC 3339)"`
+}
+
+func (t *transferCommand) Run(ctx Context) error {
+	query := make(url.Values)
+	query.Set("period", t.Period)
+	for _, folder := range t.Folder {
+		query.Add("folder", folder)
+	}
+	for _, device := range t.Device {
+		query.Add("device", device)
+	}
+	if t.Since != "" {
+		query.Set("since", t.Since)
+	}
+	if t.Until != "" {
+		query.Set("until", t.Until)
+	}
+	return indexDumpOutputWrapper(ctx.clientFactory)("stats/transfer?" + query.Encode())
+}
@@ -18,7 +18,9 @@ import (
 
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/locations"
+	"github.com/syncthing/syncthing/lib/protocol"
 )
 
 func responseToBArray(response *http.Response) ([]byte, error) {
@@ -133,7 +135,18 @@ func prettyPrintResponse(response *http.Response) error {
 	return prettyPrintJSON(data)
 }
 
+// getDB opens the database of the backend selected in the configuration.
 func getDB() (backend.Backend, error) {
+	cfg, _, err := config.Load(locations.Get(locations.ConfigFile), protocol.EmptyDeviceID, events.NoopLogger)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if cfg.Options().DatabaseBackend == config.DatabaseBackendSQLite {
+		if _, err := os.Stat(locations.Get(locations.SQLiteDB)); err != nil {
+			return nil, err
+		}
+		return backend.OpenSQLite(locations.Get(locations.SQLiteDB))
+	}
 	return backend.OpenLevelDBRO(locations.Get(locations.Database))
 }
 
@@ -144,6 +144,7 @@ type serveOptions struct {
 	AuditFile        string `name:"auditfile" placeholder:"PATH" help:"Specify audit file (use \"-\" for stdout, \"--\" for stderr)"`
 	BrowserOnly      bool   `help:"Open GUI in browser"`
 	DataDir          string `name:"data" placeholder:"PATH" env:"STDATADIR" help:"Set data directory (database and logs)"`
+	DBBackend        string `name:"db-backend" placeholder:"BACKEND" env:"STDBBACKEND" help:"Override the configured database backend (\"leveldb\" or \"sqlite\")"`
 	DeviceID         bool   `help:"Show the device ID"`
 	GenerateDir      string `name:"generate" placeholder:"PATH" help:"Generate key and config in specified dir, then exit"` // DEPRECATED: replaced by subcommand!
 	GUIAddress       string `name:"gui-address" placeholder:"URL" help:"Override GUI address (e.g. \"http://192.0.2.42:8443\")"`
@@ -376,13 +377,13 @@ func (options serveOptions) Run() error {
 	if options.Upgrade {
 		release, err := checkUpgrade()
 		if err == nil {
-			// Use leveldb database locks to protect against concurrent upgrades
-			var ldb backend.Backend
-			ldb, err = syncthing.OpenDBBackend(locations.Get(locations.Database), config.TuningAuto)
+			// Use the database locks to protect against concurrent upgrades
+			var unlock func()
+			unlock, err = lockDatabases()
 			if err != nil {
 				err = upgradeViaRest()
 			} else {
-				_ = ldb.Close()
+				unlock()
 				err = upgrade.To(release)
 			}
 		}
@@ -580,8 +581,24 @@ func syncthingMain(options serveOptions) {
 		})
 	}
 
-	dbFile := locations.Get(locations.Database)
-	ldb, err := syncthing.OpenDBBackend(dbFile, cfgWrapper.Options().DatabaseTuning)
+	dbBackend := cfgWrapper.Options().DatabaseBackend
+	if options.DBBackend != "" {
+		_ = dbBackend.UnmarshalText([]byte(options.DBBackend))
+		if dbBackend.String() != options.DBBackend {
+			l.Warnf("Unknown database backend %q", options.DBBackend)
+			os.Exit(svcutil.ExitError.AsInt())
+		}
+	}
+	var ldb backend.Backend
+	switch dbBackend {
+	case config.DatabaseBackendSQLite:
+		if cfgWrapper.Options().DatabaseTuning != config.TuningAuto {
+			l.Infoln("Database tuning has no effect on the SQLite database")
+		}
+		ldb, err = syncthing.OpenSQLiteDBBackend(locations.Get(locations.SQLiteDB), locations.Get(locations.Database))
+	default:
+		ldb, err = syncthing.OpenDBBackend(locations.Get(locations.Database), cfgWrapper.Options().DatabaseTuning)
+	}
 	if err != nil {
 		l.Warnln("Error opening database:", err)
 		os.Exit(1)
@@ -749,8 +766,43 @@ func auditWriter(auditFile string) io.Writer {
 	return fd
 }
 
+// lockDatabases takes the locks of the existing databases, which fails
+// while Syncthing is running. Databases that don't exist are not created.
+func lockDatabases() (func(), error) {
+	var dbs []backend.Backend
+	unlock := func() {
+		for _, db := range dbs {
+			_ = db.Close()
+		}
+	}
+	if _, err := os.Stat(locations.Get(locations.SQLiteDB)); err == nil {
+		db, err := backend.OpenSQLite(locations.Get(locations.SQLiteDB))
+		if err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, db)
+	}
+	if _, err := os.Stat(locations.Get(locations.Database)); err == nil {
+		db, err := syncthing.OpenDBBackend(locations.Get(locations.Database), config.TuningAuto)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		dbs = append(dbs, db)
+	}
+	return unlock, nil
+}
+
 func resetDB() error {
-	return os.RemoveAll(locations.Get(locations.Database))
+	if err := os.RemoveAll(locations.Get(locations.Database)); err != nil {
+		return err
+	}
+	for _, suffix := range []string{"", "-wal", "-shm", backend.SQLiteLockSuffix} {
+		if err := os.Remove(locations.Get(locations.SQLiteDB) + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
 }
 
 func autoUpgradePossible(options serveOptions) bool {
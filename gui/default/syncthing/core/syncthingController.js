@@ -24,6 +24,9 @@ angular.module('syncthing.core')
                 }
                 // Get index.html again (likely cached) to retrieve the version header
                 $http.get('').success(setVersionFromHeader).error(setVersionFromHeader);
+                $http.get(authUrlbase + '/methods').success(function (data) {
+                    $scope.login.methods = data;
+                });
 
                 // Can't proceed yet - wait for the page reload after successful login.
                 return;
@@ -37,11 +40,14 @@ angular.module('syncthing.core')
 
         // window.metadata is set in /meta.js which requires authentication
         $scope.authenticated = window.metadata && window.metadata.authenticated;
+        // The user and role of the session, if logged in
+        $scope.guiUser = window.metadata && window.metadata.user;
 
         $scope.login = {
             username: '',
             password: '',
             errors: {},
+            methods: { password: true },
         };
         $scope.completion = {};
         $scope.config = {};
@@ -127,6 +133,14 @@ angular.module('syncthing.core')
             });
         };
 
+        $scope.authenticateOIDC = function () {
+            var url = authUrlbase + '/oidc/login';
+            if ($scope.login.stayLoggedIn) {
+                url += '?stayLoggedIn=true';
+            }
+            location.href = url;
+        };
+
         $scope.logout = function() {
             $http.post(authUrlbase + '/logout', {})
             .then(function () {
@@ -579,7 +593,7 @@ angular.module('syncthing.core')
             // This function should match IsAuthEnabled() in guiconfiguration.go
             var guiCfg = $scope.config && $scope.config.gui;
             if (guiCfg) {
-                return guiCfg.authMode === 'ldap' || (guiCfg.user && guiCfg.password);
+                return guiCfg.authMode === 'ldap' || guiCfg.authMode === 'oidc' || (guiCfg.user && guiCfg.password) || (guiCfg.users && guiCfg.users.length > 0);
             }
             return false;
         };
@@ -601,7 +615,7 @@ angular.module('syncthing.core')
                 && !$scope.isAuthEnabled()
                 && !guiCfg.insecureAdminAccess;
 
-            if ((guiCfg.user && guiCfg.password) || guiCfg.authMode === 'ldap') {
+            if ($scope.isAuthEnabled()) {
                 $scope.dismissNotification('authenticationUserAndPassword');
             }
         }
@@ -1384,6 +1398,7 @@ angular.module('syncthing.core')
             if (conn.type.indexOf('relay') === 0) type = "relay";
             else if (conn.type.indexOf('quic') === 0) type = "quic";
             else if (conn.type.indexOf('tcp') === 0) type = "tcp";
+            else if (conn.type.indexOf('websocket') === 0) type = "websocket";
             else return type;
 
             if (conn.isLocal) type += "lan";
@@ -1405,6 +1420,10 @@ angular.module('syncthing.core')
                     return $translate.instant('TCP WAN');
                 case "tcplan":
                     return $translate.instant('TCP LAN');
+                case "websocketwan":
+                    return $translate.instant('WebSocket WAN');
+                case "websocketlan":
+                    return $translate.instant('WebSocket LAN');
                 default:
                     return $translate.instant('Disconnected');
             }
@@ -1417,6 +1436,8 @@ angular.module('syncthing.core')
                 return "reception-4";
             case "tcpwan":
             case "quicwan":
+            case "websocketlan":
+            case "websocketwan":
                 return "reception-3";
             case "relaylan":
                 return "reception-2";
@@ -1440,6 +1461,10 @@ angular.module('syncthing.core')
                     return $translate.instant('Using a direct TCP connection over WAN');
                 case "tcplan":
                     return $translate.instant('Using a direct TCP connection over LAN');
+                case "websocketwan":
+                    return $translate.instant('Using a WebSocket connection over WAN');
+                case "websocketlan":
+                    return $translate.instant('Using a WebSocket connection over LAN');
                 default:
                     return $translate.instant('Unknown');
             }
@@ -2367,6 +2392,7 @@ angular.module('syncthing.core')
                 $scope.currentFolder._guiVersioning.trashcanClean = +currentVersioning.params.cleanoutDays;
                 break;
             case "simple":
+            case "dedup":
                 $scope.currentFolder._guiVersioning.simpleKeep = +currentVersioning.params.keep;
                 $scope.currentFolder._guiVersioning.trashcanClean = +currentVersioning.params.cleanoutDays;
                 break;
@@ -2552,6 +2578,7 @@ angular.module('syncthing.core')
                 folderCfg.versioning.params.cleanoutDays = '' + folderCfg._guiVersioning.trashcanClean;
                 break;
             case "simple":
+            case "dedup":
                 folderCfg.versioning.params.keep = '' + folderCfg._guiVersioning.simpleKeep,
                 folderCfg.versioning.params.cleanoutDays = '' + folderCfg._guiVersioning.trashcanClean;
                 break;
@@ -739,6 +739,161 @@ func (x *ObservedDevice) GetAddress() string {
 	return ""
 }
 
+// FileHistoryEntry records a device announcing a new version of a file
+type FileHistoryEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Device     []byte                 `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	Version    *bep.Vector            `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	ModifiedBy uint64                 `protobuf:"varint,4,opt,name=modified_by,json=modifiedBy,proto3" json:"modified_by,omitempty"`
+	Size       int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	ModifiedS  int64                  `protobuf:"varint,6,opt,name=modified_s,json=modifiedS,proto3" json:"modified_s,omitempty"`
+	ModifiedNs int32                  `protobuf:"varint,7,opt,name=modified_ns,json=modifiedNs,proto3" json:"modified_ns,omitempty"`
+	BlocksHash []byte                 `protobuf:"bytes,8,opt,name=blocks_hash,json=blocksHash,proto3" json:"blocks_hash,omitempty"`
+	Deleted    bool                   `protobuf:"varint,9,opt,name=deleted,proto3" json:"deleted,omitempty"`
+}
+
+func (x *FileHistoryEntry) Reset() {
+	*x = FileHistoryEntry{}
+	mi := &file_dbproto_structs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileHistoryEntry) ProtoMessage() {}
+
+func (x *FileHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_dbproto_structs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileHistoryEntry.ProtoReflect.Descriptor instead.
+func (*FileHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_dbproto_structs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FileHistoryEntry) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *FileHistoryEntry) GetDevice() []byte {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *FileHistoryEntry) GetVersion() *bep.Vector {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *FileHistoryEntry) GetModifiedBy() uint64 {
+	if x != nil {
+		return x.ModifiedBy
+	}
+	return 0
+}
+
+func (x *FileHistoryEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileHistoryEntry) GetModifiedS() int64 {
+	if x != nil {
+		return x.ModifiedS
+	}
+	return 0
+}
+
+func (x *FileHistoryEntry) GetModifiedNs() int32 {
+	if x != nil {
+		return x.ModifiedNs
+	}
+	return 0
+}
+
+func (x *FileHistoryEntry) GetBlocksHash() []byte {
+	if x != nil {
+		return x.BlocksHash
+	}
+	return nil
+}
+
+func (x *FileHistoryEntry) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type FileHistory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*FileHistoryEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"` // newest first
+}
+
+func (x *FileHistory) Reset() {
+	*x = FileHistory{}
+	mi := &file_dbproto_structs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileHistory) ProtoMessage() {}
+
+func (x *FileHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_dbproto_structs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileHistory.ProtoReflect.Descriptor instead.
+func (*FileHistory) Descriptor() ([]byte, []int) {
+	return file_dbproto_structs_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FileHistory) GetEntries() []*FileHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
 var File_dbproto_structs_proto protoreflect.FileDescriptor
 
 var file_dbproto_structs_proto_rawDesc = []byte{
@@ -853,17 +1008,40 @@ var file_dbproto_structs_proto_rawDesc = []byte{
 	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
 	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
 	0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x8c, 0x01, 0x0a, 0x0b, 0x63,
-	0x6f, 0x6d, 0x2e, 0x64, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x42, 0x0c, 0x53, 0x74, 0x72, 0x75,
-	0x63, 0x74, 0x73, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67,
-	0x2f, 0x73, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
-	0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x64, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0xa2,
-	0x02, 0x03, 0x44, 0x58, 0x58, 0xaa, 0x02, 0x07, 0x44, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0xca,
-	0x02, 0x07, 0x44, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0xe2, 0x02, 0x13, 0x44, 0x62, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea,
-	0x02, 0x07, 0x44, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0xb1, 0x02, 0x0a, 0x10, 0x46,
+	0x69, 0x6c, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x65, 0x70, 0x2e, 0x56,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x42, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73,
+	0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65,
+	0x64, 0x53, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x6e,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65,
+	0x64, 0x4e, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22, 0x42,
+	0x0a, 0x0b, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x33, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x64, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x42, 0x8c, 0x01, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x62, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x42, 0x0c, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x73, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x50, 0x01, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73,
+	0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69,
+	0x6e, 0x67, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x65, 0x6e, 0x2f,
+	0x64, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0xa2, 0x02, 0x03, 0x44, 0x58, 0x58, 0xaa, 0x02, 0x07,
+	0x44, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0xca, 0x02, 0x07, 0x44, 0x62, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0xe2, 0x02, 0x13, 0x44, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x5c, 0x47, 0x50, 0x42, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x07, 0x44, 0x62, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -878,7 +1056,7 @@ func file_dbproto_structs_proto_rawDescGZIP() []byte {
 	return file_dbproto_structs_proto_rawDescData
 }
 
-var file_dbproto_structs_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_dbproto_structs_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_dbproto_structs_proto_goTypes = []any{
 	(*FileInfoTruncated)(nil),     // 0: dbproto.FileInfoTruncated
 	(*FileVersion)(nil),           // 1: dbproto.FileVersion
@@ -889,27 +1067,32 @@ var file_dbproto_structs_proto_goTypes = []any{
 	(*CountsSet)(nil),             // 6: dbproto.CountsSet
 	(*ObservedFolder)(nil),        // 7: dbproto.ObservedFolder
 	(*ObservedDevice)(nil),        // 8: dbproto.ObservedDevice
-	(*bep.Vector)(nil),            // 9: bep.Vector
-	(bep.FileInfoType)(0),         // 10: bep.FileInfoType
-	(*bep.PlatformData)(nil),      // 11: bep.PlatformData
-	(*bep.BlockInfo)(nil),         // 12: bep.BlockInfo
-	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+	(*FileHistoryEntry)(nil),      // 9: dbproto.FileHistoryEntry
+	(*FileHistory)(nil),           // 10: dbproto.FileHistory
+	(*bep.Vector)(nil),            // 11: bep.Vector
+	(bep.FileInfoType)(0),         // 12: bep.FileInfoType
+	(*bep.PlatformData)(nil),      // 13: bep.PlatformData
+	(*bep.BlockInfo)(nil),         // 14: bep.BlockInfo
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
 }
 var file_dbproto_structs_proto_depIdxs = []int32{
-	9,  // 0: dbproto.FileInfoTruncated.version:type_name -> bep.Vector
-	10, // 1: dbproto.FileInfoTruncated.type:type_name -> bep.FileInfoType
-	11, // 2: dbproto.FileInfoTruncated.platform:type_name -> bep.PlatformData
-	9,  // 3: dbproto.FileVersion.version:type_name -> bep.Vector
+	11, // 0: dbproto.FileInfoTruncated.version:type_name -> bep.Vector
+	12, // 1: dbproto.FileInfoTruncated.type:type_name -> bep.FileInfoType
+	13, // 2: dbproto.FileInfoTruncated.platform:type_name -> bep.PlatformData
+	11, // 3: dbproto.FileVersion.version:type_name -> bep.Vector
 	1,  // 4: dbproto.VersionList.versions:type_name -> dbproto.FileVersion
-	12, // 5: dbproto.BlockList.blocks:type_name -> bep.BlockInfo
+	14, // 5: dbproto.BlockList.blocks:type_name -> bep.BlockInfo
 	5,  // 6: dbproto.CountsSet.counts:type_name -> dbproto.Counts
-	13, // 7: dbproto.ObservedFolder.time:type_name -> google.protobuf.Timestamp
-	13, // 8: dbproto.ObservedDevice.time:type_name -> google.protobuf.Timestamp
-	9,  // [9:9] is the sub-list for method output_type
-	9,  // [9:9] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	15, // 7: dbproto.ObservedFolder.time:type_name -> google.protobuf.Timestamp
+	15, // 8: dbproto.ObservedDevice.time:type_name -> google.protobuf.Timestamp
+	15, // 9: dbproto.FileHistoryEntry.time:type_name -> google.protobuf.Timestamp
+	11, // 10: dbproto.FileHistoryEntry.version:type_name -> bep.Vector
+	9,  // 11: dbproto.FileHistory.entries:type_name -> dbproto.FileHistoryEntry
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_dbproto_structs_proto_init() }
@@ -923,7 +1106,7 @@ func file_dbproto_structs_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_dbproto_structs_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -16,14 +16,18 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"runtime"
 	"runtime/pprof"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -31,7 +35,6 @@ import (
 	"unicode"
 
 	"github.com/calmh/incontainer"
-	"github.com/julienschmidt/httprouter"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rcrowley/go-metrics"
 	"github.com/thejerf/suture/v4"
@@ -40,6 +43,7 @@ import (
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 
+	"github.com/syncthing/syncthing/lib/auditlog"
 	"github.com/syncthing/syncthing/lib/build"
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/connections"
@@ -52,6 +56,7 @@ import (
 	"github.com/syncthing/syncthing/lib/model"
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/syncthing/syncthing/lib/rand"
+	"github.com/syncthing/syncthing/lib/stats"
 	"github.com/syncthing/syncthing/lib/svcutil"
 	"github.com/syncthing/syncthing/lib/sync"
 	"github.com/syncthing/syncthing/lib/tlsutil"
@@ -66,6 +71,7 @@ const (
 	DiskEventMask         = events.LocalChangeDetected | events.RemoteChangeDetected
 	EventSubBufferSize    = 1000
 	defaultEventTimeout   = time.Minute
+	eventStreamKeepalive  = 30 * time.Second
 	httpsCertLifetimeDays = 820
 )
 
@@ -92,6 +98,7 @@ type service struct {
 	listenerAddr         net.Addr
 	exitChan             chan *svcutil.FatalErr
 	miscDB               *db.NamespacedKV
+	auditLog             *auditlog.Log
 
 	guiErrors logger.Recorder
 	systemLog logger.Recorder
@@ -105,7 +112,7 @@ type Service interface {
 	WaitForStart() error
 }
 
-func New(id protocol.DeviceID, cfg config.Wrapper, assetDir, tlsDefaultCommonName string, m model.Model, defaultSub, diskSub events.BufferedSubscription, evLogger events.Logger, discoverer discover.Manager, connectionsService connections.Service, urService *ur.Service, fss model.FolderSummaryService, errors, systemLog logger.Recorder, noUpgrade bool, miscDB *db.NamespacedKV) Service {
+func New(id protocol.DeviceID, cfg config.Wrapper, assetDir, tlsDefaultCommonName string, m model.Model, defaultSub, diskSub events.BufferedSubscription, evLogger events.Logger, discoverer discover.Manager, connectionsService connections.Service, urService *ur.Service, fss model.FolderSummaryService, errors, systemLog logger.Recorder, noUpgrade bool, miscDB *db.NamespacedKV, auditLog *auditlog.Log) Service {
 	return &service{
 		id:      id,
 		cfg:     cfg,
@@ -129,6 +136,7 @@ func New(id protocol.DeviceID, cfg config.Wrapper, assetDir, tlsDefaultCommonNam
 		startedOnce:          make(chan struct{}),
 		exitChan:             make(chan *svcutil.FatalErr, 1),
 		miscDB:               miscDB,
+		auditLog:             auditLog,
 	}
 }
 
@@ -243,101 +251,8 @@ func (s *service) Serve(ctx context.Context) error {
 	s.cfg.Subscribe(s)
 	defer s.cfg.Unsubscribe(s)
 
-	restMux := httprouter.New()
-
-	// The GET handlers
-	restMux.HandlerFunc(http.MethodGet, "/rest/cluster/pending/devices", s.getPendingDevices) // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/cluster/pending/folders", s.getPendingFolders) // [device]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/completion", s.getDBCompletion)             // [device] [folder]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/file", s.getDBFile)                         // folder file
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/ignores", s.getDBIgnores)                   // folder
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/need", s.getDBNeed)                         // folder [perpage] [page]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/remoteneed", s.getDBRemoteNeed)             // device folder [perpage] [page]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/localchanged", s.getDBLocalChanged)         // folder [perpage] [page]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/status", s.getDBStatus)                     // folder
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/browse", s.getDBBrowse)                     // folder [prefix] [dirsonly] [levels]
-	restMux.HandlerFunc(http.MethodGet, "/rest/folder/versions", s.getFolderVersions)         // folder
-	restMux.HandlerFunc(http.MethodGet, "/rest/folder/errors", s.getFolderErrors)             // folder [perpage] [page]
-	restMux.HandlerFunc(http.MethodGet, "/rest/folder/pullerrors", s.getFolderErrors)         // folder (deprecated)
-	restMux.HandlerFunc(http.MethodGet, "/rest/events", s.getIndexEvents)                     // [since] [limit] [timeout] [events]
-	restMux.HandlerFunc(http.MethodGet, "/rest/events/disk", s.getDiskEvents)                 // [since] [limit] [timeout]
-	restMux.HandlerFunc(http.MethodGet, "/rest/noauth/health", s.getHealth)                   // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/stats/device", s.getDeviceStats)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/stats/folder", s.getFolderStats)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/svc/deviceid", s.getDeviceID)                  // id
-	restMux.HandlerFunc(http.MethodGet, "/rest/svc/lang", s.getLang)                          // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/svc/report", s.getReport)                      // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/svc/random/string", s.getRandomString)         // [length]
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/browse", s.getSystemBrowse)             // current
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/connections", s.getSystemConnections)   // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/discovery", s.getSystemDiscovery)       // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/error", s.getSystemError)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/paths", s.getSystemPaths)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/ping", s.restPing)                      // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/status", s.getSystemStatus)             // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/upgrade", s.getSystemUpgrade)           // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/version", s.getSystemVersion)           // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/debug", s.getSystemDebug)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/log", s.getSystemLog)                   // [since]
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/log.txt", s.getSystemLogTxt)            // [since]
-
-	// The POST handlers
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/prio", s.postDBPrio)                          // folder file
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/ignores", s.postDBIgnores)                    // folder
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/override", s.postDBOverride)                  // folder
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/revert", s.postDBRevert)                      // folder
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/scan", s.postDBScan)                          // folder [sub...] [delay]
-	restMux.HandlerFunc(http.MethodPost, "/rest/folder/versions", s.postFolderVersionsRestore)   // folder <body>
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/error", s.postSystemError)                // <body>
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/error/clear", s.postSystemErrorClear)     // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/ping", s.restPing)                        // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/reset", s.postSystemReset)                // [folder]
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/restart", s.postSystemRestart)            // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/shutdown", s.postSystemShutdown)          // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/upgrade", s.postSystemUpgrade)            // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/pause", s.makeDevicePauseHandler(true))   // [device]
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/resume", s.makeDevicePauseHandler(false)) // [device]
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/debug", s.postSystemDebug)                // [enable] [disable]
-
-	// The DELETE handlers
-	restMux.HandlerFunc(http.MethodDelete, "/rest/cluster/pending/devices", s.deletePendingDevices) // device
-	restMux.HandlerFunc(http.MethodDelete, "/rest/cluster/pending/folders", s.deletePendingFolders) // folder [device]
-
-	// Config endpoints
-
-	configBuilder := &configMuxBuilder{
-		Router: restMux,
-		id:     s.id,
-		cfg:    s.cfg,
-	}
-
-	configBuilder.registerConfig("/rest/config")
-	configBuilder.registerConfigInsync("/rest/config/insync") // deprecated
-	configBuilder.registerConfigRequiresRestart("/rest/config/restart-required")
-	configBuilder.registerFolders("/rest/config/folders")
-	configBuilder.registerDevices("/rest/config/devices")
-	configBuilder.registerFolder("/rest/config/folders/:id")
-	configBuilder.registerDevice("/rest/config/devices/:id")
-	configBuilder.registerDefaultFolder("/rest/config/defaults/folder")
-	configBuilder.registerDefaultDevice("/rest/config/defaults/device")
-	configBuilder.registerDefaultIgnores("/rest/config/defaults/ignores")
-	configBuilder.registerOptions("/rest/config/options")
-	configBuilder.registerLDAP("/rest/config/ldap")
-	configBuilder.registerGUI("/rest/config/gui")
-
-	// Deprecated config endpoints
-	configBuilder.registerConfigDeprecated("/rest/system/config") // POST instead of PUT
-	configBuilder.registerConfigInsync("/rest/system/config/insync")
-
-	// Debug endpoints, not for general use
-	debugMux := http.NewServeMux()
-	debugMux.HandleFunc("/rest/debug/peerCompletion", s.getPeerCompletion)
-	debugMux.HandleFunc("/rest/debug/httpmetrics", s.getSystemHTTPMetrics)
-	debugMux.HandleFunc("/rest/debug/cpuprof", s.getCPUProf) // duration
-	debugMux.HandleFunc("/rest/debug/heapprof", s.getHeapProf)
-	debugMux.HandleFunc("/rest/debug/support", s.getSupportBundle)
-	debugMux.HandleFunc("/rest/debug/file", s.getDebugFile)
-	restMux.Handler(http.MethodGet, "/rest/debug/*method", s.whenDebugging(debugMux))
+	restMux := newRestRouter()
+	s.registerRoutes(restMux)
 
 	// A handler that disables caching
 	noCacheRestMux := noCacheMiddleware(metricsMiddleware(restMux))
@@ -361,7 +276,7 @@ func (s *service) Serve(ctx context.Context) error {
 
 	// Wrap everything in CSRF protection. The /rest prefix should be
 	// protected, other requests will grant cookies.
-	var handler http.Handler = newCsrfManager(s.id.Short().String(), "/rest", guiCfg, mux, s.miscDB)
+	var handler http.Handler = newCsrfManager(s.id.Short().String(), "/rest", guiCfg, apiKeyScopeMiddleware(guiCfg, s.auditMiddleware(mux)), s.miscDB)
 
 	// Add our version and ID as a header to responses
 	handler = withDetailsMiddleware(s.id, handler)
@@ -372,10 +287,11 @@ func (s *service) Serve(ctx context.Context) error {
 		authMW := newBasicAuthAndSessionMiddleware(tokenCookieManager, guiCfg, s.cfg.LDAP(), handler, s.evLogger)
 		handler = authMW
 
-		restMux.Handler(http.MethodPost, "/rest/noauth/auth/password", http.HandlerFunc(authMW.passwordAuthHandler))
-
-		// Logout is a no-op without a valid session cookie, so /noauth/ is fine here
-		restMux.Handler(http.MethodPost, "/rest/noauth/auth/logout", http.HandlerFunc(authMW.handleLogout))
+		var oidcAuth *oidcAuthenticator
+		if guiCfg.AuthMode == config.AuthModeOIDC {
+			oidcAuth = newOIDCAuthenticator(s.cfg.OIDC(), tokenCookieManager, s.evLogger)
+		}
+		registerAuthRoutes(restMux, authMW, oidcAuth)
 	}
 
 	// Redirect to HTTPS if we are supposed to
@@ -460,6 +376,137 @@ func (s *service) Serve(ctx context.Context) error {
 	return err
 }
 
+// registerRoutes adds the REST API endpoints, other than those for
+// logging in, to the router.
+func (s *service) registerRoutes(restMux *restRouter) {
+	// The GET handlers
+	restMux.HandlerFunc(http.MethodGet, "/rest/cluster/pending/devices", s.getPendingDevices)   // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/cluster/pending/folders", s.getPendingFolders)   // [device]
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/completion", s.getDBCompletion)               // [device] [folder]
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/file", s.getDBFile)                           // folder file
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/file/history", s.getDBFileHistory)            // folder file
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/ignores", s.getDBIgnores)                     // folder
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/need", s.getDBNeed)                           // folder [perpage] [page]
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/pin", s.getDBPin)                             // folder
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/remoteneed", s.getDBRemoteNeed)               // device folder [perpage] [page]
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/localchanged", s.getDBLocalChanged)           // folder [perpage] [page]
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/status", s.getDBStatus)                       // folder
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/browse", s.getDBBrowse)                       // folder [prefix] [dirsonly] [levels]
+	restMux.HandlerFunc(http.MethodGet, "/rest/db/search", s.getDBSearch)                       // [folder...] [name] [regex] [minsize] [maxsize] [modifiedafter] [modifiedbefore] [type...] [have] [need] [local] [offset] [limit]
+	restMux.HandlerFunc(http.MethodGet, "/rest/folder/versions", s.getFolderVersions)           // folder
+	restMux.HandlerFunc(http.MethodGet, "/rest/folder/snapshots", s.getFolderSnapshots)         // folder
+	restMux.HandlerFunc(http.MethodGet, "/rest/folder/snapshots/diff", s.getFolderSnapshotDiff) // folder snapshot
+	restMux.HandlerFunc(http.MethodGet, "/rest/folder/errors", s.getFolderErrors)               // folder [perpage] [page]
+	restMux.HandlerFunc(http.MethodGet, "/rest/folder/pullerrors", s.getFolderErrors)           // folder (deprecated)
+	restMux.HandlerFunc(http.MethodGet, "/rest/events", s.getIndexEvents)                       // [since] [limit] [timeout] [events]
+	restMux.HandlerFunc(http.MethodGet, "/rest/events/disk", s.getDiskEvents)                   // [since] [limit] [timeout]
+	restMux.HandlerFunc(http.MethodGet, "/rest/events/stream", s.getIndexEventStream)           // [since] [events]
+	restMux.HandlerFunc(http.MethodGet, "/rest/events/disk/stream", s.getDiskEventStream)       // [since]
+	restMux.HandlerFunc(http.MethodGet, "/rest/noauth/health", s.getHealth)                     // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/openapi.json", restMux.getOpenAPI)               // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/stats/device", s.getDeviceStats)                 // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/stats/folder", s.getFolderStats)                 // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/stats/transfer", s.getTransferStats)             // [period] [folder...] [device...] [since] [until]
+	restMux.HandlerFunc(http.MethodGet, "/rest/svc/deviceid", s.getDeviceID)                    // id
+	restMux.HandlerFunc(http.MethodGet, "/rest/svc/lang", s.getLang)                            // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/svc/report", s.getReport)                        // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/svc/random/string", s.getRandomString)           // [length]
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/browse", s.getSystemBrowse)               // current
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/connections", s.getSystemConnections)     // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/dashboard", s.getSystemDashboard)         // [fields] [folder...] [device...]
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/discovery", s.getSystemDiscovery)         // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/error", s.getSystemError)                 // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/paths", s.getSystemPaths)                 // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/ping", s.restPing)                        // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/status", s.getSystemStatus)               // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/upgrade", s.getSystemUpgrade)             // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/version", s.getSystemVersion)             // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/debug", s.getSystemDebug)                 // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/log", s.getSystemLog)                     // [since]
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/log.txt", s.getSystemLogTxt)              // [since]
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/audit", s.getSystemAudit)                 // [since] [limit]
+
+	// The POST handlers
+	restMux.HandlerFunc(http.MethodPost, "/rest/db/prio", s.postDBPrio)                                 // folder file
+	restMux.HandlerFunc(http.MethodPost, "/rest/db/ignores", s.postDBIgnores)                           // folder
+	restMux.HandlerFunc(http.MethodPost, "/rest/db/approve", s.postDBApprove)                           // folder
+	restMux.HandlerFunc(http.MethodPost, "/rest/db/override", s.postDBOverride)                         // folder
+	restMux.HandlerFunc(http.MethodPost, "/rest/db/pin", s.postDBPin)                                   // folder file
+	restMux.HandlerFunc(http.MethodPost, "/rest/db/revert", s.postDBRevert)                             // folder
+	restMux.HandlerFunc(http.MethodPost, "/rest/db/scan", s.postDBScan)                                 // folder [sub...] [delay]
+	restMux.HandlerFunc(http.MethodPost, "/rest/folder/versions", s.postFolderVersionsRestore)          // folder <body>
+	restMux.HandlerFunc(http.MethodPost, "/rest/folder/snapshots", s.postFolderSnapshot)                // folder
+	restMux.HandlerFunc(http.MethodPost, "/rest/folder/snapshots/restore", s.postFolderSnapshotRestore) // folder snapshot
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/error", s.postSystemError)                       // <body>
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/error/clear", s.postSystemErrorClear)            // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/ping", s.restPing)                               // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/reset", s.postSystemReset)                       // [folder]
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/restart", s.postSystemRestart)                   // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/shutdown", s.postSystemShutdown)                 // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/upgrade", s.postSystemUpgrade)                   // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/pause", s.makeDevicePauseHandler(true))          // [device]
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/resume", s.makeDevicePauseHandler(false))        // [device]
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/debug", s.postSystemDebug)                       // [enable] [disable]
+
+	// The DELETE handlers
+	restMux.HandlerFunc(http.MethodDelete, "/rest/cluster/pending/devices", s.deletePendingDevices) // device
+	restMux.HandlerFunc(http.MethodDelete, "/rest/cluster/pending/folders", s.deletePendingFolders) // folder [device]
+	restMux.HandlerFunc(http.MethodDelete, "/rest/db/pin", s.deleteDBPin)                           // folder file
+
+	// Config endpoints
+
+	configBuilder := &configMuxBuilder{
+		restRouter: restMux,
+		id:         s.id,
+		cfg:        s.cfg,
+	}
+
+	configBuilder.registerConfig("/rest/config")
+	configBuilder.registerConfigInsync("/rest/config/insync") // deprecated
+	configBuilder.registerConfigRequiresRestart("/rest/config/restart-required")
+	configBuilder.registerFolders("/rest/config/folders")
+	configBuilder.registerDevices("/rest/config/devices")
+	configBuilder.registerFolder("/rest/config/folders/:id")
+	configBuilder.registerDevice("/rest/config/devices/:id")
+	configBuilder.registerDefaultFolder("/rest/config/defaults/folder")
+	configBuilder.registerDefaultDevice("/rest/config/defaults/device")
+	configBuilder.registerDefaultIgnores("/rest/config/defaults/ignores")
+	configBuilder.registerOptions("/rest/config/options")
+	configBuilder.registerLDAP("/rest/config/ldap")
+	configBuilder.registerOIDC("/rest/config/oidc")
+	configBuilder.registerGUI("/rest/config/gui")
+
+	// Deprecated config endpoints
+	configBuilder.registerConfigDeprecated("/rest/system/config") // POST instead of PUT
+	configBuilder.registerConfigInsync("/rest/system/config/insync")
+
+	// Debug endpoints, not for general use
+	debugMux := http.NewServeMux()
+	debugMux.HandleFunc("/rest/debug/peerCompletion", s.getPeerCompletion)
+	debugMux.HandleFunc("/rest/debug/httpmetrics", s.getSystemHTTPMetrics)
+	debugMux.HandleFunc("/rest/debug/cpuprof", s.getCPUProf) // duration
+	debugMux.HandleFunc("/rest/debug/heapprof", s.getHeapProf)
+	debugMux.HandleFunc("/rest/debug/support", s.getSupportBundle)
+	debugMux.HandleFunc("/rest/debug/file", s.getDebugFile)
+	restMux.Handler(http.MethodGet, "/rest/debug/*method", s.whenDebugging(debugMux))
+}
+
+// registerAuthRoutes adds the endpoints for logging in and out, which are
+// only available when authentication is enabled. The OIDC endpoints are
+// only added when oidcAuth is given.
+func registerAuthRoutes(restMux *restRouter, authMW *basicAuthAndSessionMiddleware, oidcAuth *oidcAuthenticator) {
+	restMux.Handler(http.MethodPost, "/rest/noauth/auth/password", http.HandlerFunc(authMW.passwordAuthHandler))
+	restMux.Handler(http.MethodGet, "/rest/noauth/auth/methods", http.HandlerFunc(authMW.authMethodsHandler))
+
+	if oidcAuth != nil {
+		restMux.Handler(http.MethodGet, "/rest/noauth/auth/oidc/login", http.HandlerFunc(oidcAuth.loginHandler))
+		restMux.Handler(http.MethodGet, oidcCallbackPath, http.HandlerFunc(oidcAuth.callbackHandler))
+	}
+
+	// Logout is a no-op without a valid session cookie, so /noauth/ is fine here
+	restMux.Handler(http.MethodPost, "/rest/noauth/auth/logout", http.HandlerFunc(authMW.handleLogout))
+}
+
 // Complete implements suture.IsCompletable, which signifies to the supervisor
 // whether to stop restarting the service.
 func (s *service) Complete() bool {
@@ -487,7 +534,7 @@ func (s *service) CommitConfiguration(from, to config.Configuration) bool {
 	// No action required when this changes, so mask the fact that it changed at all.
 	from.GUI.Debugging = to.GUI.Debugging
 
-	if to.GUI == from.GUI {
+	if to.GUI.Equal(from.GUI) && to.OIDC.Equal(from.OIDC) {
 		// No GUI changes, we're done here.
 		return true
 	}
@@ -716,12 +763,16 @@ func (*service) getSystemPaths(w http.ResponseWriter, _ *http.Request) {
 	sendJSON(w, locations.ListExpandedPaths())
 }
 
-func (s *service) getJSMetadata(w http.ResponseWriter, _ *http.Request) {
-	meta, _ := json.Marshal(map[string]interface{}{
+func (s *service) getJSMetadata(w http.ResponseWriter, r *http.Request) {
+	metadata := map[string]interface{}{
 		"deviceID":      s.id.String(),
 		"deviceIDShort": s.id.Short().String(),
 		"authenticated": true,
-	})
+	}
+	if user, ok := guiUserFromRequest(r); ok {
+		metadata["user"] = user
+	}
+	meta, _ := json.Marshal(metadata)
 	w.Header().Set("Content-Type", "application/javascript")
 	fmt.Fprintf(w, "var metadata = %s;\n", meta)
 }
@@ -793,6 +844,97 @@ func (s *service) getDBBrowse(w http.ResponseWriter, r *http.Request) {
 	sendJSON(w, result)
 }
 
+var searchFileTypes = map[string]protocol.FileInfoType{
+	"file":      protocol.FileInfoTypeFile,
+	"directory": protocol.FileInfoTypeDirectory,
+	"symlink":   protocol.FileInfoTypeSymlink,
+}
+
+func (s *service) getDBSearch(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSearchQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.model.Search(query)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if isFolderNotFound(err) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, path.ErrBadPattern) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	sendJSON(w, res)
+}
+
+func parseSearchQuery(qs url.Values) (model.SearchQuery, error) {
+	query := model.SearchQuery{
+		Folders: qs["folder"],
+		Glob:    qs.Get("name"),
+		Local:   qs.Get("local") == "true",
+	}
+
+	var err error
+	if re := qs.Get("regex"); re != "" {
+		if query.Regexp, err = regexp.Compile(re); err != nil {
+			return query, err
+		}
+	}
+	for _, p := range []struct {
+		key string
+		val *int64
+	}{{"minsize", &query.MinSize}, {"maxsize", &query.MaxSize}} {
+		if v := qs.Get(p.key); v != "" {
+			if *p.val, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return query, fmt.Errorf("%s: %w", p.key, err)
+			}
+		}
+	}
+	for _, p := range []struct {
+		key string
+		val *time.Time
+	}{{"modifiedafter", &query.ModifiedAfter}, {"modifiedbefore", &query.ModifiedBefore}} {
+		if v := qs.Get(p.key); v != "" {
+			if *p.val, err = time.Parse(time.RFC3339, v); err != nil {
+				return query, fmt.Errorf("%s: %w", p.key, err)
+			}
+		}
+	}
+	for _, v := range qs["type"] {
+		typ, ok := searchFileTypes[strings.ToLower(v)]
+		if !ok {
+			return query, fmt.Errorf("type: unknown file type %q", v)
+		}
+		query.Types = append(query.Types, typ)
+	}
+	for _, p := range []struct {
+		key string
+		val *protocol.DeviceID
+	}{{"have", &query.HaveDevice}, {"need", &query.NeedDevice}} {
+		if v := qs.Get(p.key); v != "" {
+			if *p.val, err = protocol.DeviceIDFromString(v); err != nil {
+				return query, fmt.Errorf("%s: %w", p.key, err)
+			}
+		}
+	}
+	for _, p := range []struct {
+		key string
+		val *int
+	}{{"offset", &query.Offset}, {"limit", &query.Limit}} {
+		if v := qs.Get(p.key); v != "" {
+			if *p.val, err = strconv.Atoi(v); err != nil || *p.val < 0 {
+				return query, fmt.Errorf("%s: not a non-negative integer: %q", p.key, v)
+			}
+		}
+	}
+	return query, nil
+}
+
 func (s *service) getDBCompletion(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")    // empty means all folders
@@ -838,6 +980,46 @@ func (s *service) postDBOverride(_ http.ResponseWriter, r *http.Request) {
 	go s.model.Override(folder)
 }
 
+func (s *service) postDBApprove(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	if err := s.model.ApproveMassChange(qs.Get("folder")); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+}
+
+func (s *service) getDBPin(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	pins, err := s.model.Pins(qs.Get("folder"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	sendJSON(w, pins)
+}
+
+func (s *service) postDBPin(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	s.modifyDBPins(w, r, s.model.Pin(qs.Get("folder"), qs.Get("file")))
+}
+
+func (s *service) deleteDBPin(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	s.modifyDBPins(w, r, s.model.Unpin(qs.Get("folder"), qs.Get("file")))
+}
+
+func (s *service) modifyDBPins(w http.ResponseWriter, r *http.Request, err error) {
+	if err != nil {
+		status := http.StatusBadRequest
+		if isFolderNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	s.getDBPin(w, r)
+}
+
 func (s *service) postDBRevert(_ http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
@@ -947,6 +1129,61 @@ func (s *service) getFolderStats(w http.ResponseWriter, _ *http.Request) {
 	sendJSON(w, stats)
 }
 
+func (s *service) getTransferStats(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	period := stats.TransferPeriod(qs.Get("period"))
+	if period == "" {
+		period = stats.TransferPeriodDay
+	}
+	if !slices.Contains(stats.TransferPeriods, period) {
+		http.Error(w, fmt.Sprintf("period: unknown period %q", period), http.StatusBadRequest)
+		return
+	}
+	var since, until time.Time
+	for _, p := range []struct {
+		key string
+		val *time.Time
+	}{{"since", &since}, {"until", &until}} {
+		if v := qs.Get(p.key); v != "" {
+			var err error
+			if *p.val, err = time.Parse(time.RFC3339, v); err != nil {
+				http.Error(w, fmt.Sprintf("%s: %v", p.key, err), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	devices := make(map[protocol.DeviceID]bool)
+	for _, v := range qs["device"] {
+		device, err := protocol.DeviceIDFromString(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("device: %v", err), http.StatusBadRequest)
+			return
+		}
+		devices[device] = true
+	}
+
+	hist, err := s.model.TransferStatistics(period, since, until)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if key, ok := scopedAPIKeyFromRequest(r); ok && len(key.Folders) > 0 {
+		// The device totals include the other folders.
+		clear(hist.Devices)
+	}
+	if len(devices) > 0 {
+		maps.DeleteFunc(hist.Devices, func(device protocol.DeviceID, _ []stats.TransferStatistics) bool {
+			return !devices[device]
+		})
+	}
+	if folders := qs["folder"]; len(folders) > 0 {
+		maps.DeleteFunc(hist.Folders, func(folder string, _ []stats.TransferStatistics) bool {
+			return !slices.Contains(folders, folder)
+		})
+	}
+	sendJSON(w, hist)
+}
+
 func (s *service) getDBFile(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
@@ -995,6 +1232,23 @@ func (s *service) getDBFile(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *service) getDBFileHistory(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	folder := qs.Get("folder")
+	file := qs.Get("file")
+
+	snap, err := s.model.DBSnapshot(folder)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer snap.Release()
+
+	sendJSON(w, map[string]interface{}{
+		"history": s.toJSONFileHistory(snap.FileHistory(file)),
+	})
+}
+
 func (s *service) getDebugFile(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
@@ -1012,12 +1266,14 @@ func (s *service) getDebugFile(w http.ResponseWriter, r *http.Request) {
 	gf, _ := snap.GetGlobal(file)
 	av := snap.Availability(file)
 	vl := snap.DebugGlobalVersions(file)
+	history := snap.FileHistory(file)
 
 	sendJSON(w, map[string]interface{}{
 		"global":         jsonFileInfo(gf),
 		"local":          jsonFileInfo(lf),
 		"availability":   av,
 		"globalVersions": vl.String(),
+		"history":        s.toJSONFileHistory(history),
 		"mtime": map[string]interface{}{
 			"err":   mtimeErr,
 			"value": mtimeMapping,
@@ -1409,15 +1665,15 @@ func (s *service) postDBIgnores(w http.ResponseWriter, r *http.Request) {
 func (s *service) getIndexEvents(w http.ResponseWriter, r *http.Request) {
 	mask := s.getEventMask(r.URL.Query().Get("events"))
 	sub := s.getEventSub(mask)
-	s.getEvents(w, r, sub)
+	getEvents(w, r, sub, requestEventFilter(r))
 }
 
 func (s *service) getDiskEvents(w http.ResponseWriter, r *http.Request) {
 	sub := s.getEventSub(DiskEventMask)
-	s.getEvents(w, r, sub)
+	getEvents(w, r, sub, requestEventFilter(r))
 }
 
-func (*service) getEvents(w http.ResponseWriter, r *http.Request, eventSub events.BufferedSubscription) {
+func getEvents(w http.ResponseWriter, r *http.Request, eventSub events.BufferedSubscription, filter eventFilter) {
 	qs := r.URL.Query()
 	sinceStr := qs.Get("since")
 	limitStr := qs.Get("limit")
@@ -1437,8 +1693,22 @@ func (*service) getEvents(w http.ResponseWriter, r *http.Request, eventSub event
 	f := w.(http.Flusher)
 	f.Flush()
 
-	// If there are no events available return an empty slice, as this gets serialized as `[]`
-	evs := eventSub.Since(since, []events.Event{}, timeout)
+	// If there are no events available return an empty slice, as this gets
+	// serialized as `[]`. Keep waiting while all the events are filtered
+	// out, rather than have the client come straight back for them.
+	deadline := time.Now().Add(timeout)
+	var evs []events.Event
+	for {
+		evs = eventSub.Since(since, []events.Event{}, max(time.Until(deadline), 0))
+		if len(evs) == 0 {
+			break
+		}
+		last := evs[len(evs)-1].SubscriptionID
+		if evs = filterEvents(evs, filter); len(evs) > 0 || time.Until(deadline) <= 0 {
+			break
+		}
+		since = last
+	}
 	if 0 < limit && limit < len(evs) {
 		evs = evs[len(evs)-limit:]
 	}
@@ -1446,6 +1716,77 @@ func (*service) getEvents(w http.ResponseWriter, r *http.Request, eventSub event
 	sendJSON(w, evs)
 }
 
+func (s *service) getIndexEventStream(w http.ResponseWriter, r *http.Request) {
+	mask := s.getEventMask(r.URL.Query().Get("events"))
+	sub := s.getEventSub(mask)
+	streamEvents(w, r, sub, requestEventFilter(r))
+}
+
+func (s *service) getDiskEventStream(w http.ResponseWriter, r *http.Request) {
+	sub := s.getEventSub(DiskEventMask)
+	streamEvents(w, r, sub, requestEventFilter(r))
+}
+
+// streamEvents sends the events as Server-Sent Events until the client
+// goes away. The ID of each is its subscription ID, so that a client
+// reconnecting with the Last-Event-ID header continues where it left off,
+// as long as the events are still buffered. Events the filter drops are
+// skipped.
+func streamEvents(w http.ResponseWriter, r *http.Request, eventSub events.BufferedSubscription, filter eventFilter) {
+	since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		if id, err := strconv.Atoi(lastID); err == nil {
+			since = id
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Keep reverse proxies like nginx from holding the events back.
+	w.Header().Set("X-Accel-Buffering", "no")
+	f := w.(http.Flusher)
+	f.Flush()
+
+	ctx := r.Context()
+	lastWrite := time.Now()
+	var evs []events.Event
+	for {
+		// Wait for events a short while at a time, to notice the client
+		// going away.
+		evs = eventSub.Since(since, evs[:0], time.Second)
+		if ctx.Err() != nil {
+			return
+		}
+		if len(evs) > 0 {
+			// Continue after the last event, whether or not it is sent.
+			since = evs[len(evs)-1].SubscriptionID
+		}
+		evs = filterEvents(evs, filter)
+		if len(evs) == 0 {
+			if time.Since(lastWrite) < eventStreamKeepalive {
+				continue
+			}
+			// A comment, which keeps the connection from being closed as
+			// idle along the way.
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		for _, ev := range evs {
+			data, err := json.Marshal(ev)
+			if err != nil {
+				l.Debugln("Marshalling event:", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.SubscriptionID, ev.Type, data); err != nil {
+				return
+			}
+		}
+		f.Flush()
+		lastWrite = time.Now()
+	}
+}
+
 func (*service) getEventMask(evs string) events.EventType {
 	eventMask := DefaultEventMask
 	if evs != "" {
@@ -1570,7 +1911,7 @@ func (s *service) makeDevicePauseHandler(paused bool) http.HandlerFunc {
 
 		var msg string
 		var status int
-		_, err := s.cfg.Modify(func(cfg *config.Configuration) {
+		_, err := modifyConfig(r, s.cfg, func(cfg *config.Configuration) {
 			if deviceStr == "" {
 				for i := range cfg.Devices {
 					cfg.Devices[i].Paused = paused
@@ -1717,6 +2058,52 @@ func (s *service) postFolderVersionsRestore(w http.ResponseWriter, r *http.Reque
 	sendJSON(w, errorStringMap(ferr))
 }
 
+func (s *service) getFolderSnapshots(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	snapshots, err := s.model.FolderSnapshots(qs.Get("folder"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if snapshots == nil {
+		snapshots = []model.SnapshotInfo{}
+	}
+	sendJSON(w, snapshots)
+}
+
+func (s *service) postFolderSnapshot(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	snapshot, err := s.model.CreateFolderSnapshot(qs.Get("folder"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, snapshot)
+}
+
+func (s *service) getFolderSnapshotDiff(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	diff, err := s.model.DiffFolderSnapshot(qs.Get("folder"), qs.Get("snapshot"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if diff == nil {
+		diff = []model.SnapshotDiffEntry{}
+	}
+	sendJSON(w, diff)
+}
+
+func (s *service) postFolderSnapshotRestore(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	ferr, err := s.model.RestoreFolderSnapshot(qs.Get("folder"), qs.Get("snapshot"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, errorStringMap(ferr))
+}
+
 func (s *service) getFolderErrors(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
@@ -1916,6 +2303,27 @@ func fileIntfJSONMap(f protocol.FileInfo) map[string]interface{} {
 	return out
 }
 
+type jsonFileHistoryEntry struct {
+	db.FileHistoryEntry
+	Version    jsonVersionVector `json:"version"`
+	ModifiedBy string            `json:"modifiedBy"`
+}
+
+func (s *service) toJSONFileHistory(entries []db.FileHistoryEntry) []jsonFileHistoryEntry {
+	res := make([]jsonFileHistoryEntry, len(entries))
+	for i, e := range entries {
+		if e.Device == protocol.LocalDeviceID {
+			e.Device = s.id
+		}
+		res[i] = jsonFileHistoryEntry{
+			FileHistoryEntry: e,
+			Version:          jsonVersionVector(e.Version),
+			ModifiedBy:       e.ModifiedBy.String(),
+		}
+	}
+	return res
+}
+
 type jsonVersionVector protocol.Vector
 
 func (v jsonVersionVector) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,184 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/auditlog"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+// The operations recorded in the audit log next to configuration changes,
+// by endpoint.
+var auditedOperations = map[string]string{
+	"/rest/db/ignores":               "ignores",
+	"/rest/db/override":              "override",
+	"/rest/db/revert":                "revert",
+	"/rest/folder/versions":          "restore-versions",
+	"/rest/folder/snapshots/restore": "restore-snapshot",
+	"/rest/system/reset":             "reset",
+	"/rest/system/restart":           "restart",
+	"/rest/system/shutdown":          "shutdown",
+	"/rest/system/upgrade":           "upgrade",
+	"/rest/system/pause":             "pause",
+	"/rest/system/resume":            "resume",
+}
+
+// auditAction returns the action recorded in the audit log for the
+// request, if it's one that is recorded.
+func auditAction(method, path string) (string, bool) {
+	if isReadMethod(method) {
+		return "", false
+	}
+	if path == "/rest/system/config" || path == "/rest/config" || strings.HasPrefix(path, "/rest/config/") {
+		return "config", true
+	}
+	action, ok := auditedOperations[path]
+	return action, ok
+}
+
+// auditMiddleware records the configuration changes and other operations
+// made through the REST API in the audit log, together with who made them.
+func (s *service) auditMiddleware(h http.Handler) http.Handler {
+	if s.auditLog == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		action, ok := auditAction(r.Method, r.URL.Path)
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// The configuration changes made while serving the request are
+		// recorded through its context, so that concurrent requests and
+		// changes made elsewhere don't end up in its diff.
+		rec := &auditRecorder{mut: sync.NewMutex()}
+		r = r.WithContext(context.WithValue(r.Context(), auditRecorderContextKey{}, rec))
+		rw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rw, r)
+		diff := rec.recorded()
+		if rw.status >= http.StatusBadRequest {
+			// Nothing happened.
+			return
+		}
+		if action == "config" && len(diff) == 0 {
+			return
+		}
+
+		entry := auditlog.Entry{
+			User:     auditUser(s.cfg.GUI().ScopedAPIKey, r),
+			Address:  r.RemoteAddr,
+			Method:   r.Method,
+			Endpoint: r.URL.Path,
+			Action:   action,
+			Device:   r.URL.Query().Get("device"),
+			Diff:     diff,
+		}
+		entry.Folder, _ = requestFolder(r)
+		if err := s.auditLog.Record(entry); err != nil {
+			l.Warnln("Audit log:", err)
+		}
+	})
+}
+
+type auditRecorderContextKey struct{}
+
+// auditRecorder collects the configuration changes made while serving an
+// audited request.
+type auditRecorder struct {
+	mut     sync.Mutex
+	changes []auditlog.Change
+}
+
+func (rec *auditRecorder) record(changes []auditlog.Change) {
+	rec.mut.Lock()
+	rec.changes = append(rec.changes, changes...)
+	rec.mut.Unlock()
+}
+
+func (rec *auditRecorder) recorded() []auditlog.Change {
+	rec.mut.Lock()
+	defer rec.mut.Unlock()
+	return rec.changes
+}
+
+// modifyConfig modifies the configuration like cfg.Modify, and records the
+// changes for the audit log if the request is audited.
+func modifyConfig(r *http.Request, cfg config.Wrapper, fn config.ModifyFunction) (config.Waiter, error) {
+	rec, ok := r.Context().Value(auditRecorderContextKey{}).(*auditRecorder)
+	if !ok {
+		return cfg.Modify(fn)
+	}
+	var diff []auditlog.Change
+	waiter, err := cfg.Modify(func(cfg *config.Configuration) {
+		before := cfg.Copy()
+		fn(cfg)
+		var err error
+		diff, err = auditlog.Diff(before, cfg)
+		if err != nil {
+			l.Warnln("Audit log: comparing configurations:", err)
+		}
+	})
+	if err != nil {
+		return waiter, err
+	}
+	rec.record(diff)
+	return waiter, nil
+}
+
+// auditUser returns who made the request, as recorded in the audit log.
+func auditUser(scopedAPIKey func(string) (config.APIKey, bool), r *http.Request) string {
+	if user, ok := guiUserFromRequest(r); ok {
+		return user.Name
+	}
+	key := apiKeyFromRequest(r)
+	if key == "" {
+		return ""
+	}
+	if scoped, ok := scopedAPIKey(key); ok {
+		return "apikey:" + scoped.Name
+	}
+	return "apikey"
+}
+
+func (s *service) getSystemAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	since, err := time.Parse(time.RFC3339, q.Get("since"))
+	if err != nil {
+		l.Debugln(err)
+	}
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil {
+		l.Debugln(err)
+	}
+	entries, err := s.auditLog.Entries(since, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, map[string][]auditlog.Entry{
+		"entries": entries,
+	})
+}
+
+// statusResponseWriter remembers the status of the response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
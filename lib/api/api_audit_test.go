@@ -0,0 +1,135 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/auditlog"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+
+	const testAPIKey = "foobarbaz"
+	dir := t.TempDir()
+	cfg := config.New(protocol.LocalDeviceID)
+	cfg.GUI.RawAddress = "127.0.0.1:0"
+	cfg.GUI.APIKey = testAPIKey
+	cfg.GUI.APIKeys = []config.APIKey{{Name: "script", Key: "scoped", Permissions: []config.APIPermission{config.APIPermissionRead, config.APIPermissionFolder}}}
+	w := config.Wrap(filepath.Join(dir, "config.xml"), cfg, protocol.LocalDeviceID, events.NoopLogger)
+	cfgCtx, cfgCancel := context.WithCancel(context.Background())
+	go w.Serve(cfgCtx)
+	defer cfgCancel()
+
+	auditLog, err := auditlog.Open(filepath.Join(dir, "audit.jsonl"), auditlog.DefaultMaxSize, auditlog.DefaultMaxFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL, cancel, err := startHTTPWithAuditLog(w, auditLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	cli := &http.Client{Timeout: time.Minute}
+	do := func(method, path, key string, body any, status int) *http.Response {
+		t.Helper()
+		bs, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, baseURL+path, bytes.NewReader(bs))
+		req.Header.Set("X-API-Key", key)
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != status {
+			t.Fatalf("%s %s: expected status %v, got %v", method, path, status, resp.StatusCode)
+		}
+		return resp
+	}
+
+	folderPath := filepath.Join(dir, "folder")
+	do(http.MethodPut, "/rest/config/folders/audited", testAPIKey, config.FolderConfiguration{ID: "audited", Path: folderPath}, http.StatusOK).Body.Close()
+	do(http.MethodGet, "/rest/config/folders/audited", testAPIKey, nil, http.StatusOK).Body.Close()
+	do(http.MethodPost, "/rest/db/override?folder=audited", "scoped", nil, http.StatusOK).Body.Close()
+	// Not recorded, as it fails
+	do(http.MethodPost, "/rest/config/folders", testAPIKey, "not a folder", http.StatusBadRequest).Body.Close()
+
+	// Only for those allowed everything
+	do(http.MethodGet, "/rest/system/audit", "scoped", nil, http.StatusForbidden).Body.Close()
+	resp := do(http.MethodGet, "/rest/system/audit", testAPIKey, nil, http.StatusOK)
+	var res struct {
+		Entries []auditlog.Entry `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(res.Entries) != 2 {
+		t.Fatalf("expected two entries, got %+v", res.Entries)
+	}
+	added := res.Entries[0]
+	if added.User != "apikey" || added.Action != "config" || added.Folder != "audited" || added.Method != http.MethodPut || added.Address == "" {
+		t.Errorf("unexpected entry for the added folder: %+v", added)
+	}
+	if len(added.Diff) != 1 || added.Diff[0].Path != "folders[audited]" || added.Diff[0].Before != nil {
+		t.Errorf("unexpected diff for the added folder: %+v", added.Diff)
+	}
+	override := res.Entries[1]
+	if override.User != "apikey:script" || override.Action != "override" || override.Folder != "audited" || len(override.Diff) != 0 {
+		t.Errorf("unexpected entry for the override: %+v", override)
+	}
+}
+
+func TestAuditModifyConfig(t *testing.T) {
+	t.Parallel()
+
+	w := config.Wrap("", config.New(protocol.LocalDeviceID), protocol.LocalDeviceID, events.NoopLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	go w.Serve(ctx)
+	defer cancel()
+
+	rec := &auditRecorder{mut: sync.NewMutex()}
+	audited := httptest.NewRequest(http.MethodPut, "/rest/config/gui", nil)
+	audited = audited.WithContext(context.WithValue(audited.Context(), auditRecorderContextKey{}, rec))
+	other := httptest.NewRequest(http.MethodPut, "/rest/config/gui", nil)
+
+	modify := func(r *http.Request, theme string) {
+		t.Helper()
+		waiter, err := modifyConfig(r, w, func(cfg *config.Configuration) {
+			cfg.GUI.Theme = theme
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		waiter.Wait()
+	}
+
+	// Only the changes made for the request itself are recorded, even when
+	// others are made in between.
+	modify(other, "before")
+	modify(audited, "recorded")
+	modify(other, "elsewhere")
+
+	expected := []auditlog.Change{{Path: "gui.theme", Before: "before", After: "recorded"}}
+	if diff := rec.recorded(); !reflect.DeepEqual(diff, expected) {
+		t.Errorf("unexpected diff\n got: %+v\nwant: %+v", diff, expected)
+	}
+}
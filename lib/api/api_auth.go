@@ -16,6 +16,7 @@ import (
 	"time"
 
 	ldap "github.com/go-ldap/ldap/v3"
+	"github.com/syncthing/syncthing/internal/gen/apiproto"
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/rand"
@@ -102,15 +103,17 @@ func (m *basicAuthAndSessionMiddleware) ServeHTTP(w http.ResponseWriter, r *http
 		return
 	}
 
-	if m.tokenCookieManager.hasValidSession(r) {
-		m.next.ServeHTTP(w, r)
-		return
+	if sessionUser, ok := m.tokenCookieManager.sessionUser(r); ok {
+		if user, ok := m.sessionGUIUser(sessionUser); ok {
+			m.serveAs(user, w, r)
+			return
+		}
 	}
 
 	// Fall back to Basic auth if provided
-	if username, ok := attemptBasicAuth(r, m.guiCfg, m.ldapCfg, m.evLogger); ok {
-		m.tokenCookieManager.createSession(username, false, w, r)
-		m.next.ServeHTTP(w, r)
+	if username, role, ok := attemptBasicAuth(r, m.guiCfg, m.ldapCfg, m.evLogger); ok {
+		m.tokenCookieManager.createSession(username, role, false, w, r)
+		m.serveAs(guiUser{Name: username, Role: role}, w, r)
 		return
 	}
 
@@ -130,6 +133,33 @@ func (m *basicAuthAndSessionMiddleware) ServeHTTP(w http.ResponseWriter, r *http
 	forbidden(w)
 }
 
+// serveAs handles the request of a logged in user, if their role allows it.
+func (m *basicAuthAndSessionMiddleware) serveAs(user guiUser, w http.ResponseWriter, r *http.Request) {
+	if !isNoAuthPath(r.URL.Path) && !roleAllows(user.Role, r) {
+		l.Debugf("GUI user %q with role %q is not allowed to %s %s", user.Name, user.Role, r.Method, r.URL.Path)
+		forbidden(w)
+		return
+	}
+	m.next.ServeHTTP(w, withGUIUser(r, user))
+}
+
+// sessionGUIUser returns the user of a session, or false if the session
+// isn't valid anymore.
+func (m *basicAuthAndSessionMiddleware) sessionGUIUser(user *apiproto.SessionUser) (guiUser, bool) {
+	if user == nil {
+		// The session is from before sessions had users, when everyone
+		// was an admin.
+		return guiUser{Role: config.GUIRoleAdmin}, true
+	}
+	if m.guiCfg.AuthMode == config.AuthModeStatic {
+		// Static users get their current role, so that changes take
+		// effect right away, and removed users are logged out.
+		role, ok := staticRole(user.Name, m.guiCfg)
+		return guiUser{Name: user.Name, Role: role}, ok
+	}
+	return guiUser{Name: user.Name, Role: config.GUIRole(user.Role)}, true
+}
+
 func (m *basicAuthAndSessionMiddleware) passwordAuthHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Username     string
@@ -142,8 +172,8 @@ func (m *basicAuthAndSessionMiddleware) passwordAuthHandler(w http.ResponseWrite
 		return
 	}
 
-	if auth(req.Username, req.Password, m.guiCfg, m.ldapCfg) {
-		m.tokenCookieManager.createSession(req.Username, req.StayLoggedIn, w, r)
+	if role, ok := auth(req.Username, req.Password, m.guiCfg, m.ldapCfg); ok {
+		m.tokenCookieManager.createSession(req.Username, role, req.StayLoggedIn, w, r)
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
@@ -153,27 +183,36 @@ func (m *basicAuthAndSessionMiddleware) passwordAuthHandler(w http.ResponseWrite
 	forbidden(w)
 }
 
-func attemptBasicAuth(r *http.Request, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration, evLogger events.Logger) (string, bool) {
+func attemptBasicAuth(r *http.Request, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration, evLogger events.Logger) (string, config.GUIRole, bool) {
 	username, password, ok := r.BasicAuth()
 	if !ok {
-		return "", false
+		return "", "", false
 	}
 
 	l.Debugln("Sessionless HTTP request with authentication; this is expensive.")
 
-	if auth(username, password, guiCfg, ldapCfg) {
-		return username, true
+	if role, ok := auth(username, password, guiCfg, ldapCfg); ok {
+		return username, role, true
 	}
 
 	usernameFromIso := string(iso88591ToUTF8([]byte(username)))
 	passwordFromIso := string(iso88591ToUTF8([]byte(password)))
-	if auth(usernameFromIso, passwordFromIso, guiCfg, ldapCfg) {
-		return usernameFromIso, true
+	if role, ok := auth(usernameFromIso, passwordFromIso, guiCfg, ldapCfg); ok {
+		return usernameFromIso, role, true
 	}
 
 	emitLoginAttempt(false, username, r.RemoteAddr, evLogger)
 	antiBruteForceSleep()
-	return "", false
+	return "", "", false
+}
+
+// authMethodsHandler tells the login page which ways of logging in are
+// available.
+func (m *basicAuthAndSessionMiddleware) authMethodsHandler(w http.ResponseWriter, _ *http.Request) {
+	sendJSON(w, map[string]bool{
+		"password": m.guiCfg.AuthMode != config.AuthModeOIDC || hasStaticCredentials(m.guiCfg),
+		"oidc":     m.guiCfg.AuthMode == config.AuthModeOIDC,
+	})
 }
 
 func (m *basicAuthAndSessionMiddleware) handleLogout(w http.ResponseWriter, r *http.Request) {
@@ -181,19 +220,51 @@ func (m *basicAuthAndSessionMiddleware) handleLogout(w http.ResponseWriter, r *h
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func auth(username string, password string, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration) bool {
-	if guiCfg.AuthMode == config.AuthModeLDAP {
+// auth checks the credentials, and returns the role of the user if they're
+// valid.
+func auth(username string, password string, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration) (config.GUIRole, bool) {
+	switch guiCfg.AuthMode {
+	case config.AuthModeLDAP:
 		return authLDAP(username, password, ldapCfg)
-	} else {
+	case config.AuthModeOIDC:
+		// Logins normally go through the identity provider, but static
+		// credentials remain usable for scripts, if set.
+		if !hasStaticCredentials(guiCfg) {
+			return "", false
+		}
+		return authStatic(username, password, guiCfg)
+	default:
 		return authStatic(username, password, guiCfg)
 	}
 }
 
-func authStatic(username string, password string, guiCfg config.GUIConfiguration) bool {
-	return guiCfg.CompareHashedPassword(password) == nil && username == guiCfg.User
+func hasStaticCredentials(guiCfg config.GUIConfiguration) bool {
+	return (guiCfg.User != "" && guiCfg.Password != "") || len(guiCfg.Users) > 0
+}
+
+func authStatic(username string, password string, guiCfg config.GUIConfiguration) (config.GUIRole, bool) {
+	if guiCfg.CompareHashedPassword(password) == nil && username == guiCfg.User {
+		return config.GUIRoleAdmin, true
+	}
+	if user, ok := guiCfg.GUIUser(username); ok && user.CompareHashedPassword(password) == nil {
+		return user.Role, true
+	}
+	return "", false
 }
 
-func authLDAP(username string, password string, cfg config.LDAPConfiguration) bool {
+// staticRole returns the current role of a user of the static
+// configuration.
+func staticRole(username string, guiCfg config.GUIConfiguration) (config.GUIRole, bool) {
+	if username == guiCfg.User {
+		return config.GUIRoleAdmin, true
+	}
+	if user, ok := guiCfg.GUIUser(username); ok {
+		return user.Role, true
+	}
+	return "", false
+}
+
+func authLDAP(username string, password string, cfg config.LDAPConfiguration) (config.GUIRole, bool) {
 	address := cfg.Address
 	hostname, _, err := net.SplitHostPort(address)
 	if err != nil {
@@ -211,14 +282,14 @@ func authLDAP(username string, password string, cfg config.LDAPConfiguration) bo
 
 	if err != nil {
 		l.Warnln("LDAP Dial:", err)
-		return false
+		return "", false
 	}
 
 	if cfg.Transport == config.LDAPTransportStartTLS {
 		err = connection.StartTLS(&tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify})
 		if err != nil {
 			l.Warnln("LDAP Start TLS:", err)
-			return false
+			return "", false
 		}
 	}
 
@@ -228,17 +299,21 @@ func authLDAP(username string, password string, cfg config.LDAPConfiguration) bo
 	err = connection.Bind(bindDN, password)
 	if err != nil {
 		l.Warnln("LDAP Bind:", err)
-		return false
+		return "", false
 	}
 
 	if cfg.SearchFilter == "" && cfg.SearchBaseDN == "" {
+		if len(cfg.GroupRoles) > 0 {
+			l.Warnln("LDAP configuration: searchFilter and searchBaseDN must be set to map groups to roles.")
+			return "", false
+		}
 		// We're done here.
-		return true
+		return config.GUIRoleAdmin, true
 	}
 
 	if cfg.SearchFilter == "" || cfg.SearchBaseDN == "" {
 		l.Warnln("LDAP configuration: both searchFilter and searchBaseDN must be set, or neither.")
-		return false
+		return "", false
 	}
 
 	// If a search filter and search base is set we do an LDAP search for
@@ -248,19 +323,24 @@ func authLDAP(username string, password string, cfg config.LDAPConfiguration) bo
 	searchString := formatOptionalPercentS(cfg.SearchFilter, escapeForLDAPFilter(username))
 	const sizeLimit = 2  // we search for up to two users -- we only want to match one, so getting any number >1 is a failure.
 	const timeLimit = 60 // Search for up to a minute...
-	searchReq := ldap.NewSearchRequest(cfg.SearchBaseDN, ldap.ScopeWholeSubtree, ldap.DerefFindingBaseObj, sizeLimit, timeLimit, false, searchString, nil, nil)
+	// The groups of the user are in the memberOf attribute.
+	searchReq := ldap.NewSearchRequest(cfg.SearchBaseDN, ldap.ScopeWholeSubtree, ldap.DerefFindingBaseObj, sizeLimit, timeLimit, false, searchString, []string{"memberOf"}, nil)
 
 	res, err := connection.Search(searchReq)
 	if err != nil {
 		l.Warnln("LDAP Search:", err)
-		return false
+		return "", false
 	}
 	if len(res.Entries) != 1 {
 		l.Infof("Wrong number of LDAP search results, %d != 1", len(res.Entries))
-		return false
+		return "", false
 	}
 
-	return true
+	role, ok := config.RoleForGroups(cfg.GroupRoles, res.Entries[0].GetAttributeValues("memberOf"))
+	if !ok {
+		l.Infof("LDAP user %q is in none of the groups mapped to roles", username)
+	}
+	return role, ok
 }
 
 // escapeForLDAPFilter escapes a value that will be used in a filter clause
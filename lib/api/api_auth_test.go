@@ -7,6 +7,9 @@
 package api
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -26,7 +29,7 @@ func init() {
 func TestStaticAuthOK(t *testing.T) {
 	t.Parallel()
 
-	ok := authStatic("user", "pass", guiCfg)
+	_, ok := authStatic("user", "pass", guiCfg)
 	if !ok {
 		t.Fatalf("should pass auth")
 	}
@@ -35,7 +38,7 @@ func TestStaticAuthOK(t *testing.T) {
 func TestSimpleAuthUsernameFail(t *testing.T) {
 	t.Parallel()
 
-	ok := authStatic("userWRONG", "pass", guiCfg)
+	_, ok := authStatic("userWRONG", "pass", guiCfg)
 	if ok {
 		t.Fatalf("should fail auth")
 	}
@@ -44,12 +47,36 @@ func TestSimpleAuthUsernameFail(t *testing.T) {
 func TestStaticAuthPasswordFail(t *testing.T) {
 	t.Parallel()
 
-	ok := authStatic("user", "passWRONG", guiCfg)
+	_, ok := authStatic("user", "passWRONG", guiCfg)
 	if ok {
 		t.Fatalf("should fail auth")
 	}
 }
 
+func TestStaticAuthUsers(t *testing.T) {
+	t.Parallel()
+
+	cfg := guiCfg
+	cfg.Users = []config.GUIUser{{Name: "viewer", Password: cfg.Password, Role: config.GUIRoleViewer}}
+
+	cases := []struct {
+		user, password string
+		role           config.GUIRole
+		ok             bool
+	}{
+		{"user", "pass", config.GUIRoleAdmin, true},
+		{"viewer", "pass", config.GUIRoleViewer, true},
+		{"viewer", "passWRONG", "", false},
+		{"other", "pass", "", false},
+	}
+	for _, tc := range cases {
+		role, ok := authStatic(tc.user, tc.password, cfg)
+		if role != tc.role || ok != tc.ok {
+			t.Errorf("authStatic(%q, %q) = %q, %v; expected %q, %v", tc.user, tc.password, role, ok, tc.role, tc.ok)
+		}
+	}
+}
+
 func TestFormatOptionalPercentS(t *testing.T) {
 	t.Parallel()
 
@@ -187,3 +214,104 @@ func TestTokenManager(t *testing.T) {
 		t.Errorf("token %q should be invalid", t3)
 	}
 }
+
+func TestScopedAPIKeyAllows(t *testing.T) {
+	t.Parallel()
+
+	monitor := config.APIKey{Name: "monitor", Permissions: []config.APIPermission{config.APIPermissionRead}}
+	docs := config.APIKey{
+		Name:        "docs",
+		Permissions: []config.APIPermission{config.APIPermissionRead, config.APIPermissionFolder},
+		Folders:     []string{"docs"},
+	}
+	admin := config.APIKey{
+		Name:        "admin",
+		Permissions: []config.APIPermission{config.APIPermissionConfig, config.APIPermissionSystem},
+	}
+
+	cases := []struct {
+		key    config.APIKey
+		method string
+		url    string
+		allow  bool
+	}{
+		{monitor, http.MethodGet, "/rest/system/status", true},
+		{monitor, http.MethodGet, "/rest/db/status?folder=media", true},
+		{monitor, http.MethodGet, "/rest/config/folders", true},
+		{monitor, http.MethodGet, "/rest/config", false},
+		{monitor, http.MethodGet, "/rest/config/gui", false},
+		{monitor, http.MethodPost, "/rest/db/scan?folder=media", false},
+		{monitor, http.MethodPost, "/rest/system/restart", false},
+		{monitor, http.MethodGet, "/rest/debug/support", false},
+		{docs, http.MethodPost, "/rest/db/scan?folder=docs", true},
+		{docs, http.MethodPost, "/rest/db/scan?folder=media", false},
+		{docs, http.MethodPost, "/rest/db/scan", false},
+		{docs, http.MethodGet, "/rest/db/status?folder=media", false},
+		{docs, http.MethodGet, "/rest/system/status", false},
+		{docs, http.MethodGet, "/rest/system/ping", true},
+		{docs, http.MethodGet, "/rest/db/completion", false},
+		{docs, http.MethodGet, "/rest/config/folders", true},
+		{docs, http.MethodPut, "/rest/config/folders", false},
+		{docs, http.MethodGet, "/rest/events", false},
+		{docs, http.MethodGet, "/rest/events?folder=docs", true},
+		{docs, http.MethodGet, "/rest/events/stream?folder=media", false},
+		{docs, http.MethodGet, "/rest/db/search?folder=docs&name=*.txt", true},
+		{docs, http.MethodGet, "/rest/db/search?folder=docs&folder=media", false},
+		{docs, http.MethodGet, "/rest/db/search?name=*.txt", false},
+		{docs, http.MethodGet, "/rest/system/dashboard?folder=docs", true},
+		{docs, http.MethodGet, "/rest/system/dashboard", false},
+		{docs, http.MethodGet, "/rest/stats/transfer?folder=docs&period=hour", true},
+		{docs, http.MethodGet, "/rest/stats/transfer", false},
+		{docs, http.MethodDelete, "/rest/config/folders/docs", false},
+		{admin, http.MethodDelete, "/rest/config/folders/docs", true},
+		{admin, http.MethodGet, "/rest/config", true},
+		{admin, http.MethodPost, "/rest/system/shutdown", true},
+		{admin, http.MethodGet, "/rest/system/status", false},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(tc.method, tc.url, nil)
+		if allow := apiKeyAllows(tc.key, r); allow != tc.allow {
+			t.Errorf("key %s: %s %s allowed = %v, expected %v", tc.key.Name, tc.method, tc.url, allow, tc.allow)
+		}
+	}
+}
+
+func TestScopedAPIKeyFolderFilters(t *testing.T) {
+	t.Parallel()
+
+	docs := config.APIKey{
+		Name:        "docs",
+		Permissions: []config.APIPermission{config.APIPermissionRead},
+		Folders:     []string{"docs"},
+	}
+	r := httptest.NewRequest(http.MethodGet, "/rest/events?folder=docs", nil)
+	r = r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, docs))
+
+	folders := allowedFolders(r, []config.FolderConfiguration{{ID: "docs"}, {ID: "media"}})
+	if len(folders) != 1 || folders[0].ID != "docs" {
+		t.Error("unexpected folders", folders)
+	}
+
+	if mayChangeConfig(r) {
+		t.Error("a folder restricted key may not see the credentials in the configuration")
+	}
+	filter := requestEventFilter(r)
+	cases := []struct {
+		ev   events.Event
+		keep bool
+	}{
+		{events.Event{Type: events.StateChanged, Data: map[string]interface{}{"folder": "docs"}}, true},
+		{events.Event{Type: events.LocalChangeDetected, Data: map[string]string{"folder": "docs"}}, true},
+		{events.Event{Type: events.FolderSummary, Data: struct {
+			Folder string `json:"folder"`
+		}{"docs"}}, true},
+		{events.Event{Type: events.StateChanged, Data: map[string]interface{}{"folder": "media"}}, false},
+		{events.Event{Type: events.ConfigSaved, Data: config.Configuration{GUI: config.GUIConfiguration{APIKey: "secret"}}}, false},
+		{events.Event{Type: events.DeviceConnected, Data: map[string]string{"id": "device"}}, false},
+	}
+	for _, tc := range cases {
+		if _, keep := filter(tc.ev); keep != tc.keep {
+			t.Errorf("%v event: kept = %v, expected %v", tc.ev.Type, keep, tc.keep)
+		}
+	}
+}
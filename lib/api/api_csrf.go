@@ -96,12 +96,5 @@ func (m *csrfManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func hasValidAPIKeyHeader(r *http.Request, validator apiKeyValidator) bool {
-	if key := r.Header.Get("X-API-Key"); validator.IsValidAPIKey(key) {
-		return true
-	}
-	if auth := r.Header.Get("Authorization"); strings.HasPrefix(strings.ToLower(auth), "bearer ") {
-		bearerToken := auth[len("bearer "):]
-		return validator.IsValidAPIKey(bearerToken)
-	}
-	return false
+	return validator.IsValidAPIKey(apiKeyFromRequest(r))
 }
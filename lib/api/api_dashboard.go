@@ -0,0 +1,194 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/model"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/stats"
+)
+
+// The parts of the dashboard that can be selected with the fields
+// parameter. Each corresponds to an existing endpoint.
+const (
+	dashboardSummary      = "summary"      // /rest/db/status
+	dashboardCompletion   = "completion"   // /rest/db/completion
+	dashboardFolderErrors = "folderErrors" // /rest/folder/errors
+	dashboardFolderStats  = "folderStats"  // /rest/stats/folder
+	dashboardConnections  = "connections"  // /rest/system/connections
+	dashboardDeviceStats  = "deviceStats"  // /rest/stats/device
+	dashboardErrors       = "errors"       // /rest/system/error
+)
+
+var dashboardFields = []string{
+	dashboardSummary, dashboardCompletion, dashboardFolderErrors, dashboardFolderStats,
+	dashboardConnections, dashboardDeviceStats, dashboardErrors,
+}
+
+// getSystemDashboard returns the status of several folders and devices in
+// one go, i.e. what would otherwise take a request per folder, device and
+// kind of information.
+func (s *service) getSystemDashboard(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	fields, err := parseDashboardFields(qs.Get("fields"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	folders := qs["folder"]
+	cfgFolders := s.cfg.Folders()
+	if len(folders) == 0 {
+		for id := range cfgFolders {
+			folders = append(folders, id)
+		}
+		slices.Sort(folders)
+	}
+	for _, folder := range folders {
+		if _, ok := cfgFolders[folder]; !ok {
+			http.Error(w, fmt.Sprintf("%s: %v", folder, model.ErrFolderMissing), http.StatusNotFound)
+			return
+		}
+	}
+
+	var devices []protocol.DeviceID
+	for _, dev := range qs["device"] {
+		id, err := protocol.DeviceIDFromString(dev)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		devices = append(devices, id)
+	}
+	if len(devices) == 0 {
+		for id := range s.cfg.Devices() {
+			if id != s.id {
+				devices = append(devices, id)
+			}
+		}
+	}
+
+	res := make(map[string]interface{})
+	if fields[dashboardSummary] || fields[dashboardCompletion] || fields[dashboardFolderErrors] || fields[dashboardFolderStats] {
+		folderRes, err := s.dashboardFolders(fields, folders, devices)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res["folders"] = folderRes
+	}
+	if fields[dashboardConnections] || fields[dashboardDeviceStats] {
+		deviceRes, err := s.dashboardDevices(fields, devices)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res["devices"] = deviceRes
+	}
+	if fields[dashboardErrors] {
+		res["errors"] = s.guiErrors.Since(time.Time{})
+	}
+	sendJSON(w, res)
+}
+
+func parseDashboardFields(param string) (map[string]bool, error) {
+	fields := make(map[string]bool)
+	if param == "" {
+		for _, field := range dashboardFields {
+			fields[field] = true
+		}
+		return fields, nil
+	}
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(dashboardFields, field) {
+			return nil, fmt.Errorf("unknown field %q (expected one of %s)", field, strings.Join(dashboardFields, ", "))
+		}
+		fields[field] = true
+	}
+	return fields, nil
+}
+
+func (s *service) dashboardFolders(fields map[string]bool, folders []string, devices []protocol.DeviceID) (map[string]interface{}, error) {
+	var folderStats map[string]stats.FolderStatistics
+	if fields[dashboardFolderStats] {
+		var err error
+		if folderStats, err = s.model.FolderStatistics(); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make(map[string]interface{}, len(folders))
+	for _, folder := range folders {
+		folderRes := make(map[string]interface{})
+		if fields[dashboardSummary] {
+			// Paused folders have no summary.
+			sum, _ := s.fss.Summary(folder)
+			folderRes[dashboardSummary] = sum
+		}
+		if fields[dashboardCompletion] {
+			comps, err := s.fss.Completions(folder)
+			if err != nil {
+				return nil, err
+			}
+			compRes := make(map[string]interface{})
+			for dev, comp := range comps {
+				if slices.Contains(devices, dev) {
+					compRes[dev.String()] = comp.Map()
+				}
+			}
+			folderRes[dashboardCompletion] = compRes
+		}
+		if fields[dashboardFolderErrors] {
+			// Errors are only kept while the folder is running.
+			errors, _ := s.model.FolderErrors(folder)
+			if errors == nil {
+				errors = []model.FileError{}
+			}
+			folderRes[dashboardFolderErrors] = errors
+		}
+		if fields[dashboardFolderStats] {
+			folderRes[dashboardFolderStats] = folderStats[folder]
+		}
+		res[folder] = folderRes
+	}
+	return res, nil
+}
+
+func (s *service) dashboardDevices(fields map[string]bool, devices []protocol.DeviceID) (map[string]interface{}, error) {
+	var conns map[string]model.ConnectionStats
+	if fields[dashboardConnections] {
+		conns, _ = s.model.ConnectionStats()["connections"].(map[string]model.ConnectionStats)
+	}
+	var deviceStats map[protocol.DeviceID]stats.DeviceStatistics
+	if fields[dashboardDeviceStats] {
+		var err error
+		if deviceStats, err = s.model.DeviceStatistics(); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make(map[string]interface{}, len(devices))
+	for _, dev := range devices {
+		devRes := make(map[string]interface{})
+		if fields[dashboardConnections] {
+			devRes[dashboardConnections] = conns[dev.String()]
+		}
+		if fields[dashboardDeviceStats] {
+			devRes[dashboardDeviceStats] = deviceStats[dev]
+		}
+		res[dev.String()] = devRes
+	}
+	return res, nil
+}
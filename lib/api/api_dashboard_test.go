@@ -0,0 +1,103 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+	configmocks "github.com/syncthing/syncthing/lib/config/mocks"
+	"github.com/syncthing/syncthing/lib/logger"
+	loggermocks "github.com/syncthing/syncthing/lib/logger/mocks"
+	"github.com/syncthing/syncthing/lib/model"
+	modelmocks "github.com/syncthing/syncthing/lib/model/mocks"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestSystemDashboard(t *testing.T) {
+	t.Parallel()
+
+	device1 := protocol.DeviceID{1}
+	device2 := protocol.DeviceID{2}
+	cfg := new(configmocks.Wrapper)
+	cfg.FoldersReturns(map[string]config.FolderConfiguration{"a": {ID: "a"}, "b": {ID: "b"}})
+	cfg.DevicesReturns(map[protocol.DeviceID]config.DeviceConfiguration{
+		protocol.LocalDeviceID: {}, device1: {}, device2: {},
+	})
+	m := new(modelmocks.Model)
+	m.ConnectionStatsReturns(map[string]interface{}{
+		"connections": map[string]model.ConnectionStats{device1.String(): {Connected: true}},
+	})
+	m.FolderErrorsReturns([]model.FileError{{Path: "x", Err: "broken"}}, nil)
+	fss := new(modelmocks.FolderSummaryService)
+	fss.SummaryReturns(&model.FolderSummary{NeedFiles: 3}, nil)
+	fss.CompletionsReturns(map[protocol.DeviceID]model.FolderCompletion{
+		device1: {CompletionPct: 50},
+		device2: {CompletionPct: 100},
+	}, nil)
+	errs := new(loggermocks.Recorder)
+	errs.SinceReturns([]logger.Line{{Message: "oops"}})
+	s := &service{id: protocol.LocalDeviceID, cfg: cfg, model: m, fss: fss, guiErrors: errs}
+
+	get := func(query string) (int, map[string]any) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.getSystemDashboard(rec, httptest.NewRequest(http.MethodGet, "/rest/system/dashboard?"+query, nil))
+		var res map[string]any
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rec.Code, res
+	}
+
+	code, res := get("")
+	if code != http.StatusOK {
+		t.Fatal("unexpected status", code)
+	}
+	folders := res["folders"].(map[string]any)
+	if len(folders) != 2 {
+		t.Fatal("expected all folders, got", folders)
+	}
+	a := folders["a"].(map[string]any)
+	if a["summary"].(map[string]any)["needFiles"] != 3.0 || len(a["completion"].(map[string]any)) != 2 || len(a["folderErrors"].([]any)) != 1 {
+		t.Error("unexpected folder status", a)
+	}
+	devices := res["devices"].(map[string]any)
+	if len(devices) != 2 || devices[device1.String()].(map[string]any)["connections"].(map[string]any)["connected"] != true {
+		t.Error("unexpected device status", devices)
+	}
+	if len(res["errors"].([]any)) != 1 {
+		t.Error("unexpected errors", res["errors"])
+	}
+
+	code, res = get("fields=completion,connections&folder=b&device=" + device2.String())
+	if code != http.StatusOK {
+		t.Fatal("unexpected status", code)
+	}
+	if _, ok := res["errors"]; ok || len(res) != 2 {
+		t.Error("expected only the selected fields, got", res)
+	}
+	b := res["folders"].(map[string]any)["b"].(map[string]any)
+	if _, ok := b["summary"]; ok || len(b["completion"].(map[string]any)) != 1 {
+		t.Error("expected only completion for the selected device, got", b)
+	}
+	if len(res["folders"].(map[string]any)) != 1 || len(res["devices"].(map[string]any)) != 1 {
+		t.Error("expected only the selected folder and device, got", res)
+	}
+
+	if code, _ := get("fields=bogus"); code != http.StatusBadRequest {
+		t.Error("expected bad request for an unknown field, got", code)
+	}
+	if code, _ := get("folder=c"); code != http.StatusNotFound {
+		t.Error("expected not found for an unknown folder, got", code)
+	}
+}
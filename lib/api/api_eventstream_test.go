@@ -0,0 +1,141 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+)
+
+func TestStreamEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	evLogger := events.NewLogger()
+	go evLogger.Serve(ctx)
+	sub := events.NewBufferedSubscription(evLogger.Subscribe(events.StateChanged), EventSubBufferSize)
+
+	for i := 0; i < 3; i++ {
+		evLogger.Log(events.StateChanged, map[string]string{"folder": strconv.Itoa(i)})
+	}
+	// Not part of the subscription
+	evLogger.Log(events.ItemStarted, nil)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		streamEvents(w, r, sub, nil)
+	}))
+	defer srv.Close()
+
+	// Resume after the first event
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatal("unexpected content type", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	next := func() map[string]string {
+		t.Helper()
+		fields := make(map[string]string)
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatal("stream ended")
+				}
+				if line == "" {
+					return fields
+				}
+				k, v, _ := strings.Cut(line, ": ")
+				fields[k] = v
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for event")
+			}
+		}
+	}
+
+	expect := func(id int, folder string) {
+		t.Helper()
+		fields := next()
+		if fields["id"] != strconv.Itoa(id) || fields["event"] != "StateChanged" {
+			t.Fatalf("unexpected event %v", fields)
+		}
+		var ev events.Event
+		if err := json.Unmarshal([]byte(fields["data"]), &ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.SubscriptionID != id || ev.Data.(map[string]any)["folder"] != folder {
+			t.Fatalf("unexpected event data %v", fields["data"])
+		}
+	}
+	expect(2, "1")
+	expect(3, "2")
+
+	// New events follow as they happen
+	evLogger.Log(events.StateChanged, map[string]string{"folder": "3"})
+	expect(4, "3")
+}
+
+func TestEventsRedactedForViewer(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	evLogger := events.NewLogger()
+	go evLogger.Serve(ctx)
+	sub := events.NewBufferedSubscription(evLogger.Subscribe(events.ConfigSaved), EventSubBufferSize)
+
+	var cfg config.Configuration
+	cfg.GUI.APIKey = "the-api-key"
+	cfg.GUI.Password = "the-password-hash"
+	evLogger.Log(events.ConfigSaved, cfg)
+
+	get := func(role config.GUIRole) string {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, "/rest/events?since=0&timeout=1", nil)
+		r = withGUIUser(r, guiUser{Name: "user", Role: role})
+		rec := httptest.NewRecorder()
+		getEvents(rec, r, sub, requestEventFilter(r))
+		if !strings.Contains(rec.Body.String(), "ConfigSaved") {
+			t.Fatalf("%s: expected the ConfigSaved event, got %s", role, rec.Body.String())
+		}
+		return rec.Body.String()
+	}
+
+	for _, role := range []config.GUIRole{config.GUIRoleViewer, config.GUIRoleOperator} {
+		if body := get(role); strings.Contains(body, "the-api-key") || strings.Contains(body, "the-password-hash") {
+			t.Errorf("%s sees credentials in events: %s", role, body)
+		}
+	}
+	// Also makes sure the redaction didn't touch the buffered event.
+	if body := get(config.GUIRoleAdmin); !strings.Contains(body, "the-api-key") {
+		t.Errorf("admin should see the configuration as is: %s", body)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+)
+
+// apiKeyFromRequest returns the API key given in the X-API-Key header or
+// as a bearer token, if any.
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(strings.ToLower(auth), "bearer ") {
+		return auth[len("bearer "):]
+	}
+	return ""
+}
+
+// apiKeyScopeMiddleware restricts the requests made with one of the scoped
+// API keys to the permissions and folders of the key. Requests made in any
+// other way are passed through unchanged.
+func apiKeyScopeMiddleware(guiCfg config.GUIConfiguration, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := guiCfg.ScopedAPIKey(apiKeyFromRequest(r))
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if !apiKeyAllows(key, r) {
+			l.Debugf("API key %q is not allowed to %s %s", key.Name, r.Method, r.URL.Path)
+			forbidden(w)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+	})
+}
+
+type apiKeyContextKey struct{}
+
+// scopedAPIKeyFromRequest returns the scoped API key the request was made
+// with, if any.
+func scopedAPIKeyFromRequest(r *http.Request) (config.APIKey, bool) {
+	key, ok := r.Context().Value(apiKeyContextKey{}).(config.APIKey)
+	return key, ok
+}
+
+// multiFolderPaths are the endpoints that take several folder parameters,
+// and answer only about those folders.
+var multiFolderPaths = map[string]bool{
+	"/rest/db/search":          true,
+	"/rest/system/dashboard":   true,
+	"/rest/stats/transfer":     true,
+	"/rest/events":             true,
+	"/rest/events/stream":      true,
+	"/rest/events/disk":        true,
+	"/rest/events/disk/stream": true,
+}
+
+// folderFilteredPaths are the endpoints that answer about all folders, and
+// whose handlers leave out the folders a scoped key may not see.
+var folderFilteredPaths = map[string]bool{
+	"/rest/config/folders": true,
+}
+
+// folderlessPaths are the endpoints that tell nothing about any folder.
+var folderlessPaths = map[string]bool{
+	"/rest/noauth/health":  true,
+	"/rest/openapi.json":   true,
+	"/rest/svc/deviceid":   true,
+	"/rest/svc/lang":       true,
+	"/rest/system/ping":    true,
+	"/rest/system/version": true,
+}
+
+func apiKeyAllows(key config.APIKey, r *http.Request) bool {
+	perm := requiredAPIPermission(r.Method, r.URL.Path)
+	if !key.Has(perm) {
+		return false
+	}
+	if len(key.Folders) == 0 {
+		return true
+	}
+
+	if multiFolderPaths[r.URL.Path] {
+		// Without folders these cover all of them.
+		folders := r.URL.Query()["folder"]
+		for _, folder := range folders {
+			if !key.AllowsFolder(folder) {
+				return false
+			}
+		}
+		return len(folders) > 0
+	}
+
+	// Keys restricted to some folders may only use the endpoints that are
+	// about one of their folders, or about no folder at all.
+	if folder, ok := requestFolder(r); ok {
+		return key.AllowsFolder(folder)
+	}
+	return isReadMethod(r.Method) && (folderlessPaths[r.URL.Path] || folderFilteredPaths[r.URL.Path])
+}
+
+// allowedFolders returns the folders of those given that the request may
+// see.
+func allowedFolders(r *http.Request, folders []config.FolderConfiguration) []config.FolderConfiguration {
+	key, ok := scopedAPIKeyFromRequest(r)
+	if !ok || len(key.Folders) == 0 {
+		return folders
+	}
+	return slices.DeleteFunc(slices.Clone(folders), func(folder config.FolderConfiguration) bool {
+		return !key.AllowsFolder(folder.ID)
+	})
+}
+
+// folderEventFilter keeps the events about the given folders.
+func folderEventFilter(folders []string) eventFilter {
+	return func(ev events.Event) (events.Event, bool) {
+		folder, ok := eventFolder(ev)
+		return ev, ok && slices.Contains(folders, folder)
+	}
+}
+
+// eventFolder returns the folder the event is about, if any.
+func eventFolder(ev events.Event) (string, bool) {
+	switch data := ev.Data.(type) {
+	case map[string]string:
+		folder, ok := data["folder"]
+		return folder, ok
+	case map[string]interface{}:
+		folder, ok := data["folder"].(string)
+		return folder, ok
+	}
+	// Structs with a folder field, like the folder summary.
+	bs, err := json.Marshal(ev.Data)
+	if err != nil {
+		return "", false
+	}
+	var data struct {
+		Folder *string `json:"folder"`
+	}
+	if err := json.Unmarshal(bs, &data); err != nil || data.Folder == nil {
+		return "", false
+	}
+	return *data.Folder, true
+}
+
+// requiredAPIPermission returns the permission needed for the request.
+func requiredAPIPermission(method, path string) config.APIPermission {
+	switch {
+	case strings.HasPrefix(path, "/rest/debug"), path == "/rest/system/audit":
+		return config.APIPermissionSystem
+
+	case path == "/rest/config" || path == "/rest/system/config" ||
+		strings.HasPrefix(path, "/rest/config/gui") || strings.HasPrefix(path, "/rest/config/ldap") ||
+		strings.HasPrefix(path, "/rest/config/oidc"):
+		// These contain the API keys and other credentials.
+		return config.APIPermissionConfig
+
+	case isReadMethod(method):
+		return config.APIPermissionRead
+
+	case strings.HasPrefix(path, "/rest/config"), strings.HasPrefix(path, "/rest/cluster"):
+		return config.APIPermissionConfig
+
+	case strings.HasPrefix(path, "/rest/db"), strings.HasPrefix(path, "/rest/folder"):
+		return config.APIPermissionFolder
+
+	default:
+		return config.APIPermissionSystem
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// requestFolder returns the folder the request is about, if any.
+func requestFolder(r *http.Request) (string, bool) {
+	if folder := r.URL.Query().Get("folder"); folder != "" {
+		return folder, true
+	}
+	if id, ok := strings.CutPrefix(r.URL.Path, "/rest/config/folders/"); ok && id != "" {
+		return id, true
+	}
+	return "", false
+}
@@ -0,0 +1,583 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/rand"
+)
+
+const (
+	oidcCallbackPath     = "/rest/noauth/auth/oidc/callback"
+	oidcLoginTimeout     = 10 * time.Minute
+	maxPendingOIDCLogins = 100
+	oidcClockSkew        = time.Minute
+	maxOIDCResponseSize  = 1 << 20
+	// How long to wait after fetching the provider's keys before fetching
+	// them again for a token signed with a key we don't know.
+	minOIDCKeysInterval = time.Minute
+)
+
+// oidcAuthenticator logs users in to the GUI using the OpenID Connect
+// authorization code flow with PKCE. A successful login results in the same
+// session cookie as a password login.
+type oidcAuthenticator struct {
+	cfg                config.OIDCConfiguration
+	tokenCookieManager *tokenCookieManager
+	evLogger           events.Logger
+	client             *http.Client
+	stateCookieName    string
+
+	mut         sync.Mutex
+	provider    *oidcProvider
+	keys        map[string]crypto.PublicKey
+	keysFetched time.Time
+	pending     map[string]oidcPendingLogin // by state
+}
+
+// oidcProvider is the part of the provider metadata we need, as returned by
+// the discovery endpoint.
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcPendingLogin struct {
+	verifier     string
+	nonce        string
+	redirectURL  string
+	stayLoggedIn bool
+	expires      time.Time
+}
+
+func newOIDCAuthenticator(cfg config.OIDCConfiguration, tokenCookieManager *tokenCookieManager, evLogger events.Logger) *oidcAuthenticator {
+	return &oidcAuthenticator{
+		cfg:                cfg,
+		tokenCookieManager: tokenCookieManager,
+		evLogger:           evLogger,
+		client:             &http.Client{Timeout: 30 * time.Second},
+		stateCookieName:    "oidcstate-" + tokenCookieManager.shortID,
+		keys:               make(map[string]crypto.PublicKey),
+		pending:            make(map[string]oidcPendingLogin),
+	}
+}
+
+// loginHandler sends the browser to the identity provider.
+func (a *oidcAuthenticator) loginHandler(w http.ResponseWriter, r *http.Request) {
+	provider, err := a.discover(r.Context())
+	if err != nil {
+		l.Warnln("OpenID Connect discovery:", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+	authURL, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		l.Warnln("OpenID Connect authorization endpoint:", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	state := rand.String(randomTokenLength)
+	login := oidcPendingLogin{
+		verifier:     rand.String(randomTokenLength),
+		nonce:        rand.String(randomTokenLength),
+		redirectURL:  a.redirectURL(r),
+		stayLoggedIn: r.URL.Query().Get("stayLoggedIn") == "true",
+		expires:      time.Now().Add(oidcLoginTimeout),
+	}
+	a.addPending(state, login)
+
+	// The state is bound to the browser starting the login, so that nobody
+	// can have it complete a login of theirs.
+	http.SetCookie(w, &http.Cookie{
+		Name:     a.stateCookieName,
+		Value:    state,
+		MaxAge:   int(oidcLoginTimeout.Seconds()),
+		Path:     oidcCallbackPath,
+		Secure:   a.tokenCookieManager.useSecureCookie(r),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", a.cfg.ClientID)
+	query.Set("redirect_uri", login.redirectURL)
+	query.Set("scope", strings.Join(a.cfg.AllScopes(), " "))
+	query.Set("state", state)
+	query.Set("nonce", login.nonce)
+	query.Set("code_challenge", pkceChallenge(login.verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	http.Redirect(w, r, authURL.String(), http.StatusFound)
+}
+
+// callbackHandler completes the login when the identity provider sends the
+// browser back to us.
+func (a *oidcAuthenticator) callbackHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	state := query.Get("state")
+	login, ok := a.takePending(state)
+	cookie, err := r.Cookie(a.stateCookieName)
+	http.SetCookie(w, &http.Cookie{
+		Name:   a.stateCookieName,
+		MaxAge: -1,
+		Path:   oidcCallbackPath,
+	})
+	if !ok || err != nil || cookie.Value != state {
+		l.Infoln("OpenID Connect login with unknown or expired state from", r.RemoteAddr)
+		forbidden(w)
+		return
+	}
+	if e := query.Get("error"); e != "" {
+		l.Infof("OpenID Connect login from %s failed: %s %s", r.RemoteAddr, e, query.Get("error_description"))
+		forbidden(w)
+		return
+	}
+
+	claims, err := a.exchange(r.Context(), query.Get("code"), login)
+	if err != nil {
+		l.Warnln("OpenID Connect login:", err)
+		emitLoginAttempt(false, "", r.RemoteAddr, a.evLogger)
+		antiBruteForceSleep()
+		forbidden(w)
+		return
+	}
+
+	username, _ := claims[a.cfg.UsernameClaim].(string)
+	if username == "" {
+		l.Warnf("OpenID Connect login: ID token has no %q claim", a.cfg.UsernameClaim)
+		emitLoginAttempt(false, "", r.RemoteAddr, a.evLogger)
+		forbidden(w)
+		return
+	}
+	groups := claimStrings(claims[a.cfg.GroupsClaim])
+	if !a.cfg.AllowsGroups(groups) {
+		l.Infof("OpenID Connect login: user %q is not in any of the allowed groups", username)
+		emitLoginAttempt(false, username, r.RemoteAddr, a.evLogger)
+		forbidden(w)
+		return
+	}
+	role, ok := config.RoleForGroups(a.cfg.GroupRoles, groups)
+	if !ok {
+		l.Infof("OpenID Connect login: user %q is in none of the groups mapped to roles", username)
+		emitLoginAttempt(false, username, r.RemoteAddr, a.evLogger)
+		forbidden(w)
+		return
+	}
+
+	a.tokenCookieManager.createSession(username, role, login.stayLoggedIn, w, r)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (a *oidcAuthenticator) redirectURL(r *http.Request) string {
+	if a.cfg.RedirectURL != "" {
+		return a.cfg.RedirectURL
+	}
+	scheme := "http"
+	if a.tokenCookieManager.useSecureCookie(r) {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + oidcCallbackPath
+}
+
+func (a *oidcAuthenticator) addPending(state string, login oidcPendingLogin) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	now := time.Now()
+	for s, p := range a.pending {
+		if now.After(p.expires) {
+			delete(a.pending, s)
+		}
+	}
+	for len(a.pending) >= maxPendingOIDCLogins {
+		// Forget the login started the longest time ago.
+		var oldest string
+		for s, p := range a.pending {
+			if oldest == "" || p.expires.Before(a.pending[oldest].expires) {
+				oldest = s
+			}
+		}
+		delete(a.pending, oldest)
+	}
+	a.pending[state] = login
+}
+
+func (a *oidcAuthenticator) takePending(state string) (oidcPendingLogin, bool) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	login, ok := a.pending[state]
+	if !ok {
+		return oidcPendingLogin{}, false
+	}
+	delete(a.pending, state)
+	return login, time.Now().Before(login.expires)
+}
+
+// discover returns the provider metadata, fetching it the first time.
+func (a *oidcAuthenticator) discover(ctx context.Context) (*oidcProvider, error) {
+	a.mut.Lock()
+	provider := a.provider
+	a.mut.Unlock()
+	if provider != nil {
+		return provider, nil
+	}
+
+	provider = new(oidcProvider)
+	issuer := strings.TrimSuffix(a.cfg.Issuer, "/")
+	if err := a.getJSON(ctx, issuer+"/.well-known/openid-configuration", provider); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(provider.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("provider claims to be issuer %q instead of %q", provider.Issuer, a.cfg.Issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, errors.New("incomplete provider metadata")
+	}
+
+	a.mut.Lock()
+	a.provider = provider
+	a.mut.Unlock()
+	return provider, nil
+}
+
+// exchange trades the authorization code for an ID token, and returns its
+// verified claims.
+func (a *oidcAuthenticator) exchange(ctx context.Context, code string, login oidcPendingLogin) (map[string]any, error) {
+	provider, err := a.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {login.redirectURL},
+		"code_verifier": {login.verifier},
+	}
+	if a.cfg.ClientSecret == "" {
+		form.Set("client_id", a.cfg.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if a.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(a.cfg.ClientID), url.QueryEscape(a.cfg.ClientSecret))
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := a.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no ID token")
+	}
+	return a.verifyIDToken(ctx, provider, token.IDToken, login.nonce)
+}
+
+func (a *oidcAuthenticator) verifyIDToken(ctx context.Context, provider *oidcProvider, token, nonce string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed ID token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("ID token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("ID token signature: %w", err)
+	}
+	key, err := a.key(ctx, provider, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("ID token claims: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); iss != provider.Issuer {
+		return nil, fmt.Errorf("ID token issued by %q", iss)
+	}
+	aud := claimStrings(claims["aud"])
+	if !slices.Contains(aud, a.cfg.ClientID) {
+		return nil, errors.New("ID token is not intended for us")
+	}
+	// With several audiences, the party it was issued to must be us.
+	if azp, ok := claims["azp"].(string); (ok || len(aud) > 1) && azp != a.cfg.ClientID {
+		return nil, fmt.Errorf("ID token issued to %q", azp)
+	}
+	now := time.Now()
+	exp, _ := claimTime(claims, "exp")
+	if now.Add(-oidcClockSkew).After(exp) {
+		return nil, errors.New("ID token has expired")
+	}
+	if nbf, ok := claimTime(claims, "nbf"); ok && now.Add(oidcClockSkew).Before(nbf) {
+		return nil, errors.New("ID token is not valid yet")
+	}
+	iat, ok := claimTime(claims, "iat")
+	if !ok {
+		return nil, errors.New("ID token has no issue time")
+	}
+	if now.Add(oidcClockSkew).Before(iat) {
+		return nil, errors.New("ID token issued in the future")
+	}
+	if n, _ := claims["nonce"].(string); n != nonce {
+		return nil, errors.New("ID token nonce mismatch")
+	}
+	return claims, nil
+}
+
+// key returns the provider's signing key with the given ID, refreshing the
+// key set when the key is unknown, as happens when keys are rotated. The key
+// set is fetched at most once every minOIDCKeysInterval, so that tokens
+// with made up key IDs can't have us hammer the provider.
+func (a *oidcAuthenticator) key(ctx context.Context, provider *oidcProvider, kid string) (crypto.PublicKey, error) {
+	a.mut.Lock()
+	key, ok := lookupJWK(a.keys, kid)
+	if !ok && time.Since(a.keysFetched) < minOIDCKeysInterval {
+		a.mut.Unlock()
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if !ok {
+		a.keysFetched = time.Now()
+	}
+	a.mut.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := a.getJSON(ctx, provider.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("fetching keys: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			l.Debugf("Skipping OpenID Connect key %q: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	a.keys = keys
+	key, ok = lookupJWK(a.keys, kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func lookupJWK(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	key, ok := keys[kid]
+	return key, ok
+}
+
+func (a *oidcAuthenticator) getJSON(ctx context.Context, url string, into any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	return a.doJSON(req, into)
+}
+
+func (a *oidcAuthenticator) doJSON(req *http.Request, into any) error {
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bs, err := io.ReadAll(io.LimitReader(resp.Body, maxOIDCResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(bs)))
+	}
+	return json.Unmarshal(bs, into)
+}
+
+// A jsonWebKey is an RSA or elliptic curve public key as published by the
+// provider (RFC 7517).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("point not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	hash := sha256.Sum256([]byte(signed))
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("RS256 token with a non-RSA key")
+		}
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], signature); err != nil {
+			return fmt.Errorf("ID token signature: %w", err)
+		}
+		return nil
+
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return errors.New("ES256 token with a non-EC key or malformed signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, hash[:], r, s) {
+			return errors.New("ID token signature: verification error")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", alg)
+	}
+}
+
+func decodeJWTPart(part string, into any) error {
+	bs, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, into)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	bs, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bs), nil
+}
+
+// claimTime returns the time in a numeric date claim, like the expiry.
+func claimTime(claims map[string]any, name string) (time.Time, bool) {
+	secs, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(secs), 0), true
+}
+
+// claimStrings returns the value of a claim that may be either a single
+// string or a list of them, like the audience or the groups.
+func claimStrings(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []any:
+		strs := make([]string, 0, len(claim))
+		for _, v := range claim {
+			if s, ok := v.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	default:
+		return nil
+	}
+}
+
+func pkceChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
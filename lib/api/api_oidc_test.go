@@ -0,0 +1,416 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	t.Parallel()
+
+	idp := newStubIdP(t)
+	cfg := config.OIDCConfiguration{
+		Issuer:        idp.srv.URL,
+		ClientID:      "syncthing",
+		ClientSecret:  "secret",
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+		AllowedGroups: []string{"admins"},
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		a := newTestOIDCAuthenticator(cfg)
+		idp.setUser("alice", "users", "admins")
+		resp := oidcLogin(t, a, "")
+		if resp.Code != http.StatusSeeOther || resp.Header().Get("Location") != "/" {
+			t.Fatal("expected redirect to the GUI, got", resp.Code, resp.Header())
+		}
+		sessionCookie := findCookie(resp.Result().Cookies(), a.tokenCookieManager.cookieName)
+		if sessionCookie == nil {
+			t.Fatal("expected a session cookie")
+		}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(sessionCookie)
+		if !a.tokenCookieManager.hasValidSession(req) {
+			t.Error("expected the session to be valid")
+		}
+	})
+
+	t.Run("wrong group", func(t *testing.T) {
+		a := newTestOIDCAuthenticator(cfg)
+		idp.setUser("bob", "users")
+		if resp := oidcLogin(t, a, ""); resp.Code != http.StatusForbidden {
+			t.Fatal("expected login to be refused, got", resp.Code)
+		}
+	})
+
+	t.Run("no username", func(t *testing.T) {
+		a := newTestOIDCAuthenticator(cfg)
+		idp.setUser("", "admins")
+		if resp := oidcLogin(t, a, ""); resp.Code != http.StatusForbidden {
+			t.Fatal("expected login to be refused, got", resp.Code)
+		}
+	})
+
+	t.Run("state of another browser", func(t *testing.T) {
+		a := newTestOIDCAuthenticator(cfg)
+		idp.setUser("alice", "admins")
+		if resp := oidcLogin(t, a, "other"); resp.Code != http.StatusForbidden {
+			t.Fatal("expected login to be refused, got", resp.Code)
+		}
+	})
+}
+
+func TestOIDCVerifyIDToken(t *testing.T) {
+	t.Parallel()
+
+	idp := newStubIdP(t)
+	a := newTestOIDCAuthenticator(config.OIDCConfiguration{Issuer: idp.srv.URL, ClientID: "syncthing"})
+	provider, err := a.discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() map[string]any {
+		return map[string]any{
+			"iss":   idp.srv.URL,
+			"aud":   []string{"other", "syncthing"},
+			"azp":   "syncthing",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": "nonce",
+		}
+	}
+	if _, err := a.verifyIDToken(context.Background(), provider, idp.sign(valid()), "nonce"); err != nil {
+		t.Fatal("expected valid token, got", err)
+	}
+
+	cases := map[string]func(claims map[string]any){
+		"issuer":   func(claims map[string]any) { claims["iss"] = "https://evil.example.com" },
+		"audience": func(claims map[string]any) { claims["aud"] = "other" },
+		"expired":  func(claims map[string]any) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"nonce":    func(claims map[string]any) { claims["nonce"] = "other" },
+		"not yet":  func(claims map[string]any) { claims["nbf"] = time.Now().Add(time.Hour).Unix() },
+		"no iat":   func(claims map[string]any) { delete(claims, "iat") },
+		"future":   func(claims map[string]any) { claims["iat"] = time.Now().Add(time.Hour).Unix() },
+		"azp":      func(claims map[string]any) { claims["azp"] = "other" },
+		"no azp":   func(claims map[string]any) { delete(claims, "azp") },
+	}
+	for name, modify := range cases {
+		claims := valid()
+		modify(claims)
+		if _, err := a.verifyIDToken(context.Background(), provider, idp.sign(claims), "nonce"); err == nil {
+			t.Errorf("%s: expected token to be rejected", name)
+		}
+	}
+
+	// A token signed by someone else
+	other := newStubIdP(t)
+	if _, err := a.verifyIDToken(context.Background(), provider, other.sign(valid()), "nonce"); err == nil {
+		t.Error("expected token with a foreign signature to be rejected")
+	}
+
+	// Unknown keys don't make us fetch the keys again right away
+	fetches := idp.jwksFetches.Load()
+	for i := 0; i < 3; i++ {
+		if _, err := a.verifyIDToken(context.Background(), provider, idp.signWithKey("unknown", valid()), "nonce"); err == nil {
+			t.Error("expected token signed with an unknown key to be rejected")
+		}
+	}
+	if n := idp.jwksFetches.Load() - fetches; n != 0 {
+		t.Error("expected no key fetches so soon after the last, got", n)
+	}
+	a.mut.Lock()
+	a.keysFetched = time.Time{}
+	a.mut.Unlock()
+	if _, err := a.verifyIDToken(context.Background(), provider, idp.signWithKey("unknown", valid()), "nonce"); err == nil {
+		t.Error("expected token signed with an unknown key to be rejected")
+	}
+	if n := idp.jwksFetches.Load() - fetches; n != 1 {
+		t.Error("expected the keys to be fetched again, got", n)
+	}
+}
+
+func TestOIDCConfigSecret(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.New(protocol.LocalDeviceID)
+	cfg.OIDC.ClientID = "syncthing"
+	cfg.OIDC.ClientSecret = "secret"
+	w := config.Wrap(filepath.Join(t.TempDir(), "config.xml"), cfg, protocol.LocalDeviceID, events.NoopLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	go w.Serve(ctx)
+	defer cancel()
+	mux := newRestRouter()
+	builder := &configMuxBuilder{restRouter: mux, id: protocol.LocalDeviceID, cfg: w}
+	builder.registerConfig("/rest/config")
+	builder.registerOIDC("/rest/config/oidc")
+
+	// The client secret is never sent out, not even to admins
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rest/config/oidc", nil))
+	var oidc config.OIDCConfiguration
+	if err := json.Unmarshal(rec.Body.Bytes(), &oidc); err != nil {
+		t.Fatal(err)
+	}
+	if oidc.ClientSecret != "REDACTED" {
+		t.Fatal("expected the client secret to be redacted, got", oidc.ClientSecret)
+	}
+
+	// and is kept when the settings are sent back as read
+	oidc.ClientID = "other"
+	bs, _ := json.Marshal(oidc)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/rest/config/oidc", bytes.NewReader(bs)))
+	if rec.Code != http.StatusOK {
+		t.Fatal("unexpected status", rec.Code, rec.Body)
+	}
+	if cur := w.OIDC(); cur.ClientID != "other" || cur.ClientSecret != "secret" {
+		t.Errorf("expected the client secret to be kept, got %+v", cur)
+	}
+
+	// The same goes for the whole configuration
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rest/config", nil))
+	var full config.Configuration
+	if err := json.Unmarshal(rec.Body.Bytes(), &full); err != nil {
+		t.Fatal(err)
+	}
+	if full.OIDC.ClientSecret != "REDACTED" {
+		t.Fatal("expected the client secret to be redacted in the configuration, got", full.OIDC.ClientSecret)
+	}
+	full.OIDC.ClientID = "third"
+	bs, _ = json.Marshal(full)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/rest/config", bytes.NewReader(bs)))
+	if rec.Code != http.StatusOK {
+		t.Fatal("unexpected status", rec.Code, rec.Body)
+	}
+	if cur := w.OIDC(); cur.ClientID != "third" || cur.ClientSecret != "secret" {
+		t.Errorf("expected the client secret to be kept, got %+v", cur)
+	}
+
+	// and the configuration in events
+	filter := requestEventFilter(httptest.NewRequest(http.MethodGet, "/rest/events", nil))
+	ev, _ := filter(events.Event{Type: events.ConfigSaved, Data: w.RawCopy()})
+	if secret := ev.Data.(config.Configuration).OIDC.ClientSecret; secret != "REDACTED" {
+		t.Error("expected the client secret to be redacted in events, got", secret)
+	}
+}
+
+func newTestOIDCAuthenticator(cfg config.OIDCConfiguration) *oidcAuthenticator {
+	mdb, _ := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
+	kdb := db.NewNamespacedKV(mdb, "test")
+	tcm := newTokenCookieManager("ABCDEFG", config.GUIConfiguration{}, events.NoopLogger, kdb)
+	return newOIDCAuthenticator(cfg, tcm, events.NoopLogger)
+}
+
+// oidcLogin runs through the login as a browser would, and returns the
+// response to the callback. If state is given, it's the state cookie sent
+// to the callback.
+func oidcLogin(t *testing.T, a *oidcAuthenticator, state string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	resp := httptest.NewRecorder()
+	a.loginHandler(resp, httptest.NewRequest(http.MethodGet, "http://syncthing.local/rest/noauth/auth/oidc/login", nil))
+	if resp.Code != http.StatusFound {
+		t.Fatal("expected redirect to the identity provider, got", resp.Code, resp.Body)
+	}
+	stateCookie := findCookie(resp.Result().Cookies(), a.stateCookieName)
+	if stateCookie == nil {
+		t.Fatal("expected a state cookie")
+	}
+	if state != "" {
+		stateCookie.Value = state
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	authResp, err := client.Get(resp.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authResp.Body.Close()
+	callback := authResp.Header.Get("Location")
+	if authResp.StatusCode != http.StatusFound || callback == "" {
+		t.Fatal("expected redirect back from the identity provider, got", authResp.Status)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, callback, nil)
+	req.AddCookie(stateCookie)
+	resp = httptest.NewRecorder()
+	a.callbackHandler(resp, req)
+	return resp
+}
+
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// stubIdP is a minimal OpenID Connect identity provider, which logs in the
+// configured user without asking.
+type stubIdP struct {
+	t   *testing.T
+	srv *httptest.Server
+	key *rsa.PrivateKey
+
+	jwksFetches atomic.Int32
+
+	mut    sync.Mutex
+	user   string
+	groups []string
+	codes  map[string]url.Values // authorization request by code
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &stubIdP{t: t, key: key, codes: make(map[string]url.Values)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		sendJSON(w, map[string]string{
+			"issuer":                 idp.srv.URL,
+			"authorization_endpoint": idp.srv.URL + "/authorize",
+			"token_endpoint":         idp.srv.URL + "/token",
+			"jwks_uri":               idp.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, _ *http.Request) {
+		idp.jwksFetches.Add(1)
+		sendJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "stub",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("GET /authorize", idp.authorize)
+	mux.HandleFunc("POST /token", idp.token)
+	idp.srv = httptest.NewServer(mux)
+	t.Cleanup(idp.srv.Close)
+	return idp
+}
+
+func (idp *stubIdP) setUser(user string, groups ...string) {
+	idp.mut.Lock()
+	defer idp.mut.Unlock()
+	idp.user = user
+	idp.groups = groups
+}
+
+func (idp *stubIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "bad authorization request", http.StatusBadRequest)
+		return
+	}
+
+	idp.mut.Lock()
+	code := "code-" + query.Get("state")
+	query.Set("user", idp.user)
+	query["groups"] = idp.groups
+	idp.codes[code] = query
+	idp.mut.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (idp *stubIdP) token(w http.ResponseWriter, r *http.Request) {
+	clientID, secret, ok := r.BasicAuth()
+	if !ok || clientID != "syncthing" || secret != "secret" {
+		http.Error(w, "bad client credentials", http.StatusUnauthorized)
+		return
+	}
+
+	idp.mut.Lock()
+	auth, ok := idp.codes[r.FormValue("code")]
+	delete(idp.codes, r.FormValue("code"))
+	idp.mut.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+	switch {
+	case !ok:
+		http.Error(w, "unknown code", http.StatusBadRequest)
+		return
+	case base64.RawURLEncoding.EncodeToString(verifier[:]) != auth.Get("code_challenge"):
+		http.Error(w, "PKCE verification failed", http.StatusBadRequest)
+		return
+	case r.FormValue("redirect_uri") != auth.Get("redirect_uri"):
+		http.Error(w, "redirect URI mismatch", http.StatusBadRequest)
+		return
+	}
+
+	claims := map[string]any{
+		"iss":    idp.srv.URL,
+		"aud":    auth.Get("client_id"),
+		"exp":    time.Now().Add(time.Minute).Unix(),
+		"iat":    time.Now().Unix(),
+		"nonce":  auth.Get("nonce"),
+		"groups": auth["groups"],
+	}
+	if user := auth.Get("user"); user != "" {
+		claims["preferred_username"] = user
+	}
+	sendJSON(w, map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     idp.sign(claims),
+	})
+}
+
+func (idp *stubIdP) sign(claims map[string]any) string {
+	return idp.signWithKey("stub", claims)
+}
+
+// signWithKey signs the claims claiming to use the key with the given ID.
+func (idp *stubIdP) signWithKey(kid string, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, hash[:])
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
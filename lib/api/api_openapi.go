@@ -0,0 +1,506 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/syncthing/syncthing/lib/auditlog"
+	"github.com/syncthing/syncthing/lib/build"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/discover"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/logger"
+	"github.com/syncthing/syncthing/lib/model"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/stats"
+	"github.com/syncthing/syncthing/lib/ur/contract"
+	"github.com/syncthing/syncthing/lib/versioner"
+)
+
+// A restRouter is the router for the REST API. It remembers the routes
+// added to it, to describe them at /rest/openapi.json.
+type restRouter struct {
+	*httprouter.Router
+	routes []restRoute
+}
+
+type restRoute struct {
+	method string
+	path   string
+}
+
+func newRestRouter() *restRouter {
+	return &restRouter{Router: httprouter.New()}
+}
+
+func (r *restRouter) Handle(method, path string, handle httprouter.Handle) {
+	r.routes = append(r.routes, restRoute{method, path})
+	r.Router.Handle(method, path, handle)
+}
+
+func (r *restRouter) Handler(method, path string, handler http.Handler) {
+	r.routes = append(r.routes, restRoute{method, path})
+	r.Router.Handler(method, path, handler)
+}
+
+func (r *restRouter) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	r.routes = append(r.routes, restRoute{method, path})
+	r.Router.HandlerFunc(method, path, handler)
+}
+
+func (r *restRouter) getOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	spec, _ := openAPISpec(r.routes)
+	sendJSON(w, spec)
+}
+
+// An apiOperation describes a route for the OpenAPI specification.
+type apiOperation struct {
+	summary string
+	// The query parameters, in the syntax of the comments next to the
+	// routes: "folder [perpage] [page]" for a required and two optional
+	// parameters, "[sub...]" for a repeatable one. Parameters are strings
+	// unless their type is given, as in "[limit:integer]".
+	params     string
+	request    any    // a value of the JSON request body type, if any
+	response   any    // a value of the JSON response type, if any
+	produces   string // the content type, for responses that aren't JSON
+	deprecated bool
+}
+
+// plainText as the request body means text rather than JSON.
+type plainText string
+
+// jsonObject as the response means a JSON object of varying contents.
+type jsonObject map[string]any
+
+// apiOperations describes the routes of the REST API, by method and path.
+// Every route must have an entry; TestOpenAPICoversRoutes makes sure of
+// that.
+var apiOperations = map[string]apiOperation{
+	// Pending devices and folders
+	"GET /rest/cluster/pending/devices":    {summary: "List devices that tried to connect but aren't configured", response: map[protocol.DeviceID]db.ObservedDevice{}},
+	"GET /rest/cluster/pending/folders":    {summary: "List folders offered by devices but not shared with them", params: "[device]", response: map[string]db.PendingFolder{}},
+	"DELETE /rest/cluster/pending/devices": {summary: "Dismiss a pending device", params: "device"},
+	"DELETE /rest/cluster/pending/folders": {summary: "Dismiss a pending folder", params: "folder [device]"},
+
+	// The database
+	"GET /rest/db/completion": {summary: "Get the completion of a folder or all folders for a device", params: "[device] [folder]", response: jsonObject{}},
+	"GET /rest/db/file":       {summary: "Get the local and global versions of a file", params: "folder file", response: jsonObject{}},
+	"GET /rest/db/file/history": {summary: "Get the recent changes to a file on all devices, if recorded for the folder", params: "folder file", response: struct {
+		History []jsonFileHistoryEntry `json:"history"`
+	}{}},
+	"GET /rest/db/ignores":      {summary: "Get the ignore patterns of a folder", params: "folder", response: ignoresResponse{}},
+	"POST /rest/db/ignores":     {summary: "Set the ignore patterns of a folder", params: "folder", request: ignoresResponse{}, response: ignoresResponse{}},
+	"GET /rest/db/need":         {summary: "List the files a folder needs", params: "folder [perpage:integer] [page:integer]", response: jsonObject{}},
+	"GET /rest/db/remoteneed":   {summary: "List the files a remote device needs", params: "device folder [perpage:integer] [page:integer]", response: jsonObject{}},
+	"GET /rest/db/localchanged": {summary: "List the locally changed files of a receive only folder", params: "folder [perpage:integer] [page:integer]", response: jsonObject{}},
+	"GET /rest/db/status":       {summary: "Get the summary of a folder", params: "folder", response: model.FolderSummary{}},
+	"GET /rest/db/browse":       {summary: "Get the directory tree of a folder", params: "folder [prefix] [dirsonly:boolean] [levels:integer]", response: jsonObject{}},
+	"GET /rest/db/search":       {summary: "Search the files in the index", params: "[folder...] [name] [regex] [minsize:integer] [maxsize:integer] [modifiedafter] [modifiedbefore] [type...] [have] [need] [local:boolean] [offset:integer] [limit:integer]", response: model.SearchResults{}},
+	"GET /rest/db/pin":          {summary: "List the pinned files of a folder", params: "folder", response: []string{}},
+	"POST /rest/db/pin":         {summary: "Pin a file, keeping it synced", params: "folder file", response: []string{}},
+	"DELETE /rest/db/pin":       {summary: "Unpin a file", params: "folder file", response: []string{}},
+	"POST /rest/db/prio":        {summary: "Pull a file before the others", params: "folder file", response: jsonObject{}},
+	"POST /rest/db/approve":     {summary: "Approve a mass change held back by the safety threshold", params: "folder"},
+	"POST /rest/db/override":    {summary: "Override remote changes of a send only folder", params: "folder"},
+	"POST /rest/db/revert":      {summary: "Revert local changes of a receive only folder", params: "folder"},
+	"POST /rest/db/scan":        {summary: "Scan a folder or all folders", params: "[folder] [sub...] [delay:integer]"},
+
+	// Folders
+	"GET /rest/folder/versions":           {summary: "List the archived versions of files", params: "folder", response: map[string][]versioner.FileVersion{}},
+	"POST /rest/folder/versions":          {summary: "Restore archived versions of files", params: "folder", request: map[string]time.Time{}, response: map[string]string{}},
+	"GET /rest/folder/snapshots":          {summary: "List the snapshots of a folder", params: "folder", response: []model.SnapshotInfo{}},
+	"POST /rest/folder/snapshots":         {summary: "Take a snapshot of a folder", params: "folder", response: model.SnapshotInfo{}},
+	"GET /rest/folder/snapshots/diff":     {summary: "Compare a folder to a snapshot", params: "folder snapshot", response: []model.SnapshotDiffEntry{}},
+	"POST /rest/folder/snapshots/restore": {summary: "Restore a folder to a snapshot", params: "folder snapshot", response: map[string]string{}},
+	"GET /rest/folder/errors":             {summary: "List the errors of a folder", params: "folder [perpage:integer] [page:integer]", response: folderErrorsResponse{}},
+	"GET /rest/folder/pullerrors":         {summary: "List the errors of a folder", params: "folder", response: folderErrorsResponse{}, deprecated: true},
+
+	// Events
+	"GET /rest/events":             {summary: "Wait for events", params: "[since:integer] [limit:integer] [timeout:integer] [events]", response: []events.Event{}},
+	"GET /rest/events/disk":        {summary: "Wait for local and remote changes", params: "[since:integer] [limit:integer] [timeout:integer]", response: []events.Event{}},
+	"GET /rest/events/stream":      {summary: "Stream events as server-sent events", params: "[since:integer] [events]", produces: "text/event-stream"},
+	"GET /rest/events/disk/stream": {summary: "Stream local and remote changes as server-sent events", params: "[since:integer]", produces: "text/event-stream"},
+
+	// Statistics and services
+	"GET /rest/noauth/health":     {summary: "Check that Syncthing is running", response: map[string]string{}},
+	"GET /rest/openapi.json":      {summary: "Get this description of the API", response: jsonObject{}},
+	"GET /rest/stats/device":      {summary: "Get statistics about the devices", response: map[protocol.DeviceID]stats.DeviceStatistics{}},
+	"GET /rest/stats/folder":      {summary: "Get statistics about the folders", response: map[string]stats.FolderStatistics{}},
+	"GET /rest/stats/transfer":    {summary: "Get the file data transferred per device and folder by hour, day or month", params: "[period] [folder...] [device...] [since] [until]", response: stats.TransferHistory{}},
+	"GET /rest/svc/deviceid":      {summary: "Validate and normalize a device ID", params: "id", response: map[string]string{}},
+	"GET /rest/svc/lang":          {summary: "Get the preferred languages of the browser", response: []string{}},
+	"GET /rest/svc/report":        {summary: "Preview the usage report", params: "[version:integer]", response: contract.Report{}},
+	"GET /rest/svc/random/string": {summary: "Generate a random string", params: "[length:integer]", response: map[string]string{}},
+
+	// The system
+	"GET /rest/system/browse":      {summary: "List directories matching a path prefix", params: "current", response: []string{}},
+	"GET /rest/system/connections": {summary: "Get the connection state of the devices", response: jsonObject{}},
+	"GET /rest/system/dashboard":   {summary: "Get the status of several folders and devices at once", params: "[fields] [folder...] [device...]", response: jsonObject{}},
+	"GET /rest/system/discovery":   {summary: "Get the cached discovery results", response: map[string]discover.CacheEntry{}},
+	"GET /rest/system/error": {summary: "List recent errors", response: struct {
+		Errors []logger.Line `json:"errors"`
+	}{}},
+	"POST /rest/system/error":       {summary: "Add an error to show in the GUI", request: plainText("")},
+	"POST /rest/system/error/clear": {summary: "Clear the list of recent errors"},
+	"GET /rest/system/log": {summary: "List recent log messages", params: "[since]", response: struct {
+		Messages []logger.Line `json:"messages"`
+	}{}},
+	"GET /rest/system/log.txt": {summary: "Get recent log messages as text", params: "[since]", produces: "text/plain"},
+	"GET /rest/system/audit": {summary: "List entries of the audit log", params: "[since] [limit:integer]", response: struct {
+		Entries []auditlog.Entry `json:"entries"`
+	}{}},
+	"GET /rest/system/paths":     {summary: "Get the paths used for configuration and data", response: map[string]string{}},
+	"GET /rest/system/ping":      {summary: "Check that the API is reachable", response: map[string]string{}},
+	"POST /rest/system/ping":     {summary: "Check that the API is reachable", response: map[string]string{}},
+	"GET /rest/system/status":    {summary: "Get information about the running instance", response: jsonObject{}},
+	"GET /rest/system/version":   {summary: "Get the version of Syncthing", response: jsonObject{}},
+	"GET /rest/system/upgrade":   {summary: "Check for a newer version", response: jsonObject{}},
+	"POST /rest/system/upgrade":  {summary: "Upgrade to the newest version and restart"},
+	"GET /rest/system/debug":     {summary: "List the debug facilities and which are enabled", response: jsonObject{}},
+	"POST /rest/system/debug":    {summary: "Enable or disable debug facilities", params: "[enable] [disable]"},
+	"POST /rest/system/reset":    {summary: "Reset the database of a folder or all folders and restart", params: "[folder]"},
+	"POST /rest/system/restart":  {summary: "Restart Syncthing"},
+	"POST /rest/system/shutdown": {summary: "Shut down Syncthing"},
+	"POST /rest/system/pause":    {summary: "Pause a device or all devices", params: "[device]"},
+	"POST /rest/system/resume":   {summary: "Resume a device or all devices", params: "[device]"},
+
+	// Debugging
+	"GET /rest/debug/*method": {summary: "Debugging information, when enabled: peerCompletion, httpmetrics, cpuprof, heapprof, support, or file (with folder and file)", params: "[folder] [file] [duration]", produces: "application/octet-stream"},
+
+	// Configuration
+	"GET /rest/config":                   {summary: "Get the configuration", response: config.Configuration{}},
+	"PUT /rest/config":                   {summary: "Replace the configuration", request: config.Configuration{}},
+	"GET /rest/config/insync":            {summary: "Check whether the configuration is in effect", response: map[string]bool{}, deprecated: true},
+	"GET /rest/config/restart-required":  {summary: "Check whether a restart is needed for configuration changes", response: map[string]bool{}},
+	"GET /rest/config/folders":           {summary: "List the folders", response: []config.FolderConfiguration{}},
+	"PUT /rest/config/folders":           {summary: "Replace the folders", request: []config.FolderConfiguration{}},
+	"POST /rest/config/folders":          {summary: "Add or replace a folder", request: config.FolderConfiguration{}},
+	"GET /rest/config/folders/:id":       {summary: "Get a folder", response: config.FolderConfiguration{}},
+	"PUT /rest/config/folders/:id":       {summary: "Replace a folder", request: config.FolderConfiguration{}},
+	"PATCH /rest/config/folders/:id":     {summary: "Change some settings of a folder", request: config.FolderConfiguration{}},
+	"DELETE /rest/config/folders/:id":    {summary: "Remove a folder"},
+	"GET /rest/config/devices":           {summary: "List the devices", response: []config.DeviceConfiguration{}},
+	"PUT /rest/config/devices":           {summary: "Replace the devices", request: []config.DeviceConfiguration{}},
+	"POST /rest/config/devices":          {summary: "Add or replace a device", request: config.DeviceConfiguration{}},
+	"GET /rest/config/devices/:id":       {summary: "Get a device", response: config.DeviceConfiguration{}},
+	"PUT /rest/config/devices/:id":       {summary: "Replace a device", request: config.DeviceConfiguration{}},
+	"PATCH /rest/config/devices/:id":     {summary: "Change some settings of a device", request: config.DeviceConfiguration{}},
+	"DELETE /rest/config/devices/:id":    {summary: "Remove a device"},
+	"GET /rest/config/defaults/folder":   {summary: "Get the defaults for new folders", response: config.FolderConfiguration{}},
+	"PUT /rest/config/defaults/folder":   {summary: "Replace the defaults for new folders", request: config.FolderConfiguration{}},
+	"PATCH /rest/config/defaults/folder": {summary: "Change some defaults for new folders", request: config.FolderConfiguration{}},
+	"GET /rest/config/defaults/device":   {summary: "Get the defaults for new devices", response: config.DeviceConfiguration{}},
+	"PUT /rest/config/defaults/device":   {summary: "Replace the defaults for new devices", request: config.DeviceConfiguration{}},
+	"PATCH /rest/config/defaults/device": {summary: "Change some defaults for new devices", request: config.DeviceConfiguration{}},
+	"GET /rest/config/defaults/ignores":  {summary: "Get the default ignore patterns", response: config.Ignores{}},
+	"PUT /rest/config/defaults/ignores":  {summary: "Replace the default ignore patterns", request: config.Ignores{}},
+	"GET /rest/config/options":           {summary: "Get the options", response: config.OptionsConfiguration{}},
+	"PUT /rest/config/options":           {summary: "Replace the options", request: config.OptionsConfiguration{}},
+	"PATCH /rest/config/options":         {summary: "Change some options", request: config.OptionsConfiguration{}},
+	"GET /rest/config/ldap":              {summary: "Get the LDAP settings", response: config.LDAPConfiguration{}},
+	"PUT /rest/config/ldap":              {summary: "Replace the LDAP settings", request: config.LDAPConfiguration{}},
+	"PATCH /rest/config/ldap":            {summary: "Change some LDAP settings", request: config.LDAPConfiguration{}},
+	"GET /rest/config/oidc":              {summary: "Get the OpenID Connect settings", response: config.OIDCConfiguration{}},
+	"PUT /rest/config/oidc":              {summary: "Replace the OpenID Connect settings", request: config.OIDCConfiguration{}},
+	"PATCH /rest/config/oidc":            {summary: "Change some OpenID Connect settings", request: config.OIDCConfiguration{}},
+	"GET /rest/config/gui":               {summary: "Get the GUI settings", response: config.GUIConfiguration{}},
+	"PUT /rest/config/gui":               {summary: "Replace the GUI settings", request: config.GUIConfiguration{}},
+	"PATCH /rest/config/gui":             {summary: "Change some GUI settings", request: config.GUIConfiguration{}},
+	"GET /rest/system/config":            {summary: "Get the configuration", response: config.Configuration{}, deprecated: true},
+	"POST /rest/system/config":           {summary: "Replace the configuration", request: config.Configuration{}, deprecated: true},
+	"GET /rest/system/config/insync":     {summary: "Check whether the configuration is in effect", response: map[string]bool{}, deprecated: true},
+
+	// Logging in, when authentication is enabled
+	"POST /rest/noauth/auth/password": {summary: "Log in with a user name and password", request: struct {
+		Username     string `json:"username"`
+		Password     string `json:"password"`
+		StayLoggedIn bool   `json:"stayLoggedIn"`
+	}{}},
+	"GET /rest/noauth/auth/methods":    {summary: "List the available ways of logging in", response: map[string]bool{}},
+	"GET /rest/noauth/auth/oidc/login": {summary: "Log in through the OpenID Connect provider"},
+	"GET " + oidcCallbackPath:          {summary: "Complete a login through the OpenID Connect provider", params: "code state"},
+	"POST /rest/noauth/auth/logout":    {summary: "Log out"},
+}
+
+type ignoresResponse struct {
+	Ignore   []string `json:"ignore"`
+	Expanded []string `json:"expanded,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+type folderErrorsResponse struct {
+	Folder  string            `json:"folder"`
+	Errors  []model.FileError `json:"errors"`
+	Page    int               `json:"page"`
+	PerPage int               `json:"perpage"`
+}
+
+// openAPISpec returns the OpenAPI description of the routes, and the routes
+// that have no entry in apiOperations.
+func openAPISpec(routes []restRoute) (map[string]any, []restRoute) {
+	gen := &schemaGenerator{schemas: make(map[string]any)}
+	paths := make(map[string]map[string]any)
+	var missing []restRoute
+
+	for _, route := range routes {
+		op, ok := apiOperations[route.method+" "+route.path]
+		if !ok {
+			missing = append(missing, route)
+		}
+
+		path, pathParams := openAPIPath(route.path)
+		var params []any
+		for _, name := range pathParams {
+			params = append(params, map[string]any{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]any{"type": "string"},
+			})
+		}
+		params = append(params, queryParams(op.params)...)
+
+		opSpec := map[string]any{
+			"operationId": operationID(route.method, route.path),
+			"summary":     op.summary,
+			"tags":        []string{strings.Split(strings.TrimPrefix(route.path, "/rest/"), "/")[0]},
+			"responses":   map[string]any{"200": gen.response(op)},
+		}
+		if len(params) > 0 {
+			opSpec["parameters"] = params
+		}
+		if op.request != nil {
+			contentType, schema := "application/json", gen.schema(reflect.TypeOf(op.request))
+			if _, ok := op.request.(plainText); ok {
+				contentType = "text/plain"
+			}
+			opSpec["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{contentType: map[string]any{"schema": schema}},
+			}
+		}
+		if op.deprecated {
+			opSpec["deprecated"] = true
+		}
+		if strings.HasPrefix(route.path, "/rest/noauth/") {
+			opSpec["security"] = []any{}
+		}
+
+		if paths[path] == nil {
+			paths[path] = make(map[string]any)
+		}
+		paths[path][strings.ToLower(route.method)] = opSpec
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Syncthing REST API",
+			"version": build.Version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": gen.schemas,
+			"securitySchemes": map[string]any{
+				"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+		"security": []any{map[string]any{"apiKey": []string{}}},
+	}, missing
+}
+
+// openAPIPath converts a route with parameters like /rest/config/folders/:id
+// to the OpenAPI syntax, /rest/config/folders/{id}.
+func openAPIPath(path string) (string, []string) {
+	var params []string
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			params = append(params, part[1:])
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/"), params
+}
+
+// operationID returns a name for the route, e.g. getConfigFoldersByID for
+// GET /rest/config/folders/:id.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.Split(strings.TrimPrefix(path, "/rest/"), "/") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			part = "by-" + part[1:]
+		}
+		for _, word := range strings.FieldsFunc(part, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			if word == "id" {
+				id += "ID"
+				continue
+			}
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return id
+}
+
+func queryParams(params string) []any {
+	var res []any
+	for _, param := range strings.Fields(params) {
+		optional := strings.HasPrefix(param, "[")
+		param = strings.Trim(param, "[]")
+		name, typ, ok := strings.Cut(param, ":")
+		if !ok {
+			typ = "string"
+		}
+		schema := map[string]any{"type": typ}
+		if name, ok = strings.CutSuffix(name, "..."); ok {
+			schema = map[string]any{"type": "array", "items": schema}
+		}
+		res = append(res, map[string]any{
+			"name":     name,
+			"in":       "query",
+			"required": !optional,
+			"schema":   schema,
+		})
+	}
+	return res
+}
+
+// A schemaGenerator makes JSON schemas of Go types, as they are marshalled
+// by encoding/json. Named struct types go in schemas, to be referenced.
+type schemaGenerator struct {
+	schemas map[string]any
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// Types that marshal themselves, but whose schema is known.
+	knownSchemas = map[reflect.Type]map[string]any{
+		reflect.TypeOf(time.Time{}):          {"type": "string", "format": "date-time"},
+		reflect.TypeOf(jsonVersionVector{}):  {"type": "array", "items": map[string]any{"type": "string"}},
+		reflect.TypeOf(json.RawMessage(nil)): {},
+		reflect.TypeOf(protocol.DeviceID{}):  {"type": "string", "example": "MFZWI3D-BONSGYC-YLTMRWG-C43ENR5-QXGZDMM-FZWI3DP-BONSGYY-LTMRWAD"},
+		reflect.TypeOf(jsonObject(nil)):      {"type": "object"},
+		reflect.TypeOf(config.Size{}):        {"type": "string", "example": "1%"},
+		reflect.TypeOf(protocol.ShortID(0)):  {"type": "string"},
+	}
+)
+
+func (g *schemaGenerator) response(op apiOperation) map[string]any {
+	res := map[string]any{"description": "OK"}
+	switch {
+	case op.response != nil:
+		res["content"] = map[string]any{"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(op.response))}}
+	case op.produces != "":
+		res["content"] = map[string]any{op.produces: map[string]any{"schema": map[string]any{"type": "string"}}}
+	}
+	return res
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	if schema, ok := knownSchemas[t]; ok {
+		return schema
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return map[string]any{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := strings.ReplaceAll(t.String(), "/", ".")
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = nil // in case the type refers to itself
+			g.schemas[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	g.addFields(t, props)
+	return map[string]any{"type": "object", "properties": props}
+}
+
+func (g *schemaGenerator) addFields(t reflect.Type, props map[string]any) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// Embedded fields are promoted, unless the outer type has
+				// a field of the same name.
+				embedded := make(map[string]any)
+				g.addFields(ft, embedded)
+				for k, v := range embedded {
+					if _, ok := props[k]; !ok {
+						props[k] = v
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(opts, "string") {
+			props[name] = map[string]any{"type": "string"}
+			continue
+		}
+		props[name] = g.schema(field.Type)
+	}
+}
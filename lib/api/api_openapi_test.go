@@ -0,0 +1,85 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configmocks "github.com/syncthing/syncthing/lib/config/mocks"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	t.Parallel()
+
+	r := newRestRouter()
+	s := &service{cfg: new(configmocks.Wrapper), id: protocol.LocalDeviceID}
+	s.registerRoutes(r)
+	registerAuthRoutes(r, &basicAuthAndSessionMiddleware{}, &oidcAuthenticator{})
+
+	spec, missing := openAPISpec(r.routes)
+	for _, route := range missing {
+		t.Errorf("route %s %s has no entry in apiOperations", route.method, route.path)
+	}
+
+	registered := make(map[string]bool, len(r.routes))
+	for _, route := range r.routes {
+		registered[route.method+" "+route.path] = true
+	}
+	for key := range apiOperations {
+		if !registered[key] {
+			t.Errorf("apiOperations entry %q is not a route", key)
+		}
+	}
+
+	bs, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(bs, &res); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/rest/config/folders/{id}", "/rest/db/search", "/rest/system/dashboard", "/rest/openapi.json"} {
+		if _, ok := res.Paths[path]["get"]; !ok {
+			t.Errorf("missing GET %s in the specification", path)
+		}
+	}
+	for _, schema := range []string{"config.FolderConfiguration", "config.Configuration", "model.FolderSummary"} {
+		if _, ok := res.Components.Schemas[schema]; !ok {
+			t.Errorf("missing schema %s", schema)
+		}
+	}
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	t.Parallel()
+
+	r := newRestRouter()
+	r.HandlerFunc(http.MethodGet, "/rest/openapi.json", r.getOpenAPI)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rest/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatal("unexpected status", rec.Code)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec["openapi"] != "3.0.3" {
+		t.Error("unexpected specification", spec)
+	}
+}
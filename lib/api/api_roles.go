@@ -0,0 +1,175 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+)
+
+// guiUser is the user logged in to the GUI making a request.
+type guiUser struct {
+	Name string         `json:"name"`
+	Role config.GUIRole `json:"role"`
+}
+
+type guiUserContextKey struct{}
+
+func withGUIUser(r *http.Request, user guiUser) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), guiUserContextKey{}, user))
+}
+
+// guiUserFromRequest returns the user logged in to the GUI making the
+// request. There is none when authentication is disabled or an API key is
+// used.
+func guiUserFromRequest(r *http.Request) (guiUser, bool) {
+	user, ok := r.Context().Value(guiUserContextKey{}).(guiUser)
+	return user, ok
+}
+
+// mayChangeConfig returns whether the request may see and change the whole
+// configuration, including the credentials in it.
+func mayChangeConfig(r *http.Request) bool {
+	if key, ok := scopedAPIKeyFromRequest(r); ok {
+		return key.Has(config.APIPermissionConfig) && len(key.Folders) == 0
+	}
+	user, ok := guiUserFromRequest(r)
+	return !ok || user.Role.Allows(config.APIPermissionConfig)
+}
+
+// roleAllows returns whether the role permits the request.
+func roleAllows(role config.GUIRole, r *http.Request) bool {
+	perm := requiredAPIPermission(r.Method, r.URL.Path)
+	if perm == config.APIPermissionConfig && isReadMethod(r.Method) {
+		// Everyone may look at the configuration, as the credentials in
+		// it are redacted for those who may not change it.
+		perm = config.APIPermissionRead
+	}
+	return role.Allows(perm)
+}
+
+// An eventFilter returns the event as the request may see it, and false if
+// it may not see it at all.
+type eventFilter func(ev events.Event) (events.Event, bool)
+
+// requestEventFilter returns the filter for the events sent in answer to
+// the request.
+func requestEventFilter(r *http.Request) eventFilter {
+	if key, ok := scopedAPIKeyFromRequest(r); ok && len(key.Folders) > 0 {
+		// The folders asked for are all allowed, or we wouldn't be here.
+		return folderEventFilter(r.URL.Query()["folder"])
+	}
+	if mayChangeConfig(r) {
+		return redactEventOIDCSecrets
+	}
+	return redactEventSecrets
+}
+
+// redactEventSecrets hides the credentials in the configuration that comes
+// with ConfigSaved.
+func redactEventSecrets(ev events.Event) (events.Event, bool) {
+	if cfg, ok := ev.Data.(config.Configuration); ok {
+		redactSecrets(&cfg)
+		ev.Data = cfg
+	}
+	return ev, true
+}
+
+// redactEventOIDCSecrets hides the OIDC client secret, which is never sent
+// out, in the configuration that comes with ConfigSaved.
+func redactEventOIDCSecrets(ev events.Event) (events.Event, bool) {
+	if cfg, ok := ev.Data.(config.Configuration); ok {
+		redactOIDCSecrets(&cfg.OIDC)
+		ev.Data = cfg
+	}
+	return ev, true
+}
+
+// filterEvents applies the filter, if any, to the events in place.
+func filterEvents(evs []events.Event, filter eventFilter) []events.Event {
+	if filter == nil {
+		return evs
+	}
+	res := evs[:0]
+	for _, ev := range evs {
+		if ev, ok := filter(ev); ok {
+			res = append(res, ev)
+		}
+	}
+	return res
+}
+
+// redactSecrets hides the credentials in the configuration, which would
+// otherwise let users gain more rights than their role has.
+func redactSecrets(cfg *config.Configuration) {
+	cfg.Folders = redactFoldersSecrets(cfg.Folders)
+	redactFolderSecrets(&cfg.Defaults.Folder)
+	redactGUISecrets(&cfg.GUI)
+	redactOIDCSecrets(&cfg.OIDC)
+	redactOptionsSecrets(&cfg.Options)
+}
+
+func redactFoldersSecrets(folders []config.FolderConfiguration) []config.FolderConfiguration {
+	res := make([]config.FolderConfiguration, len(folders))
+	for i, folder := range folders {
+		res[i] = folder
+		redactFolderSecrets(&res[i])
+	}
+	return res
+}
+
+func redactFolderSecrets(folder *config.FolderConfiguration) {
+	*folder = folder.Copy()
+	for i := range folder.Devices {
+		if folder.Devices[i].EncryptionPassword != "" {
+			folder.Devices[i].EncryptionPassword = "REDACTED"
+		}
+	}
+}
+
+func redactGUISecrets(gui *config.GUIConfiguration) {
+	// Don't touch the lists the configuration may share with us.
+	*gui = gui.Copy()
+	gui.APIKey = "REDACTED"
+	for i := range gui.APIKeys {
+		gui.APIKeys[i].Key = "REDACTED"
+	}
+	if gui.Password != "" {
+		gui.Password = "REDACTED"
+	}
+	for i := range gui.Users {
+		if gui.Users[i].Password != "" {
+			gui.Users[i].Password = "REDACTED"
+		}
+	}
+}
+
+func redactOIDCSecrets(oidc *config.OIDCConfiguration) {
+	if oidc.ClientSecret != "" {
+		oidc.ClientSecret = "REDACTED"
+	}
+}
+
+// keepOIDCSecrets keeps the current client secret when the new settings
+// have it redacted, as they do when they were read from the API.
+func keepOIDCSecrets(oidc *config.OIDCConfiguration, current config.OIDCConfiguration) {
+	if oidc.ClientSecret == "REDACTED" {
+		oidc.ClientSecret = current.ClientSecret
+	}
+}
+
+func redactOptionsSecrets(opts *config.OptionsConfiguration) {
+	*opts = opts.Copy()
+	for i := range opts.Webhooks {
+		if opts.Webhooks[i].Secret != "" {
+			opts.Webhooks[i].Secret = "REDACTED"
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"slices"
@@ -28,6 +29,7 @@ import (
 	"github.com/thejerf/suture/v4"
 
 	"github.com/syncthing/syncthing/lib/assets"
+	"github.com/syncthing/syncthing/lib/auditlog"
 	"github.com/syncthing/syncthing/lib/build"
 	"github.com/syncthing/syncthing/lib/config"
 	connmocks "github.com/syncthing/syncthing/lib/connections/mocks"
@@ -86,7 +88,7 @@ func TestStopAfterBrokenConfig(t *testing.T) {
 
 	mdb, _ := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
 	kdb := db.NewMiscDataNamespace(mdb)
-	srv := New(protocol.LocalDeviceID, w, "", "syncthing", nil, nil, nil, events.NoopLogger, nil, nil, nil, nil, nil, nil, false, kdb).(*service)
+	srv := New(protocol.LocalDeviceID, w, "", "syncthing", nil, nil, nil, events.NoopLogger, nil, nil, nil, nil, nil, nil, false, kdb, nil).(*service)
 
 	srv.started = make(chan string)
 
@@ -280,6 +282,18 @@ func TestAPIServiceRequests(t *testing.T) {
 			Type:   "application/json",
 			Prefix: "null",
 		},
+		{
+			URL:    "/rest/stats/transfer?period=month&since=2025-01-01T00:00:00Z",
+			Code:   200,
+			Type:   "application/json",
+			Prefix: "",
+		},
+		{
+			URL:    "/rest/stats/transfer?period=week",
+			Code:   400,
+			Type:   "text/plain",
+			Prefix: "",
+		},
 
 		// /rest/svc
 		{
@@ -922,7 +936,181 @@ func TestApiCache(t *testing.T) {
 	})
 }
 
+func TestScopedAPIKeys(t *testing.T) {
+	t.Parallel()
+
+	const readKey = "readonlykey"
+	cfg := newMockedConfig()
+	cfg.GUIReturns(config.GUIConfiguration{
+		RawAddress: "127.0.0.1:0",
+		APIKey:     testAPIKey,
+		APIKeys: []config.APIKey{
+			{Name: "monitoring", Key: readKey, Permissions: []config.APIPermission{config.APIPermissionRead}},
+		},
+	})
+	encrypted := config.FolderConfiguration{
+		ID:      "encrypted",
+		Devices: []config.FolderDeviceConfiguration{{DeviceID: dev1, EncryptionPassword: "secret"}},
+	}
+	cfg.FolderListReturns([]config.FolderConfiguration{encrypted})
+	cfg.FolderReturns(encrypted, true)
+	cfg.DefaultFolderReturns(encrypted)
+	baseURL, cancel, err := startHTTP(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cancel)
+
+	cases := []struct {
+		method, path, key string
+		status            int
+	}{
+		{http.MethodGet, "/rest/system/version", readKey, http.StatusOK},
+		{http.MethodGet, "/rest/system/config", readKey, http.StatusForbidden},
+		{http.MethodPost, "/rest/system/ping", readKey, http.StatusForbidden},
+		{http.MethodPost, "/rest/system/ping", testAPIKey, http.StatusOK},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(tc.method, baseURL+tc.path, nil)
+		req.Header.Set("X-API-Key", tc.key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %s with key %s: got %s, expected %d", tc.method, tc.path, tc.key, resp.Status, tc.status)
+		}
+	}
+
+	// Folder encryption passwords are hidden from those who may not change
+	// the configuration.
+	for key, password := range map[string]string{readKey: "REDACTED", testAPIKey: "secret"} {
+		for _, path := range []string{"/rest/config/folders", "/rest/config/folders/encrypted", "/rest/config/defaults/folder"} {
+			req, _ := http.NewRequest(http.MethodGet, baseURL+path, nil)
+			req.Header.Set("X-API-Key", key)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bs, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("GET %s with key %s: got %s", path, key, resp.Status)
+			}
+			var folders []config.FolderConfiguration
+			if err := json.Unmarshal(bs, &folders); err != nil {
+				var folder config.FolderConfiguration
+				if err := json.Unmarshal(bs, &folder); err != nil {
+					t.Fatal(err)
+				}
+				folders = append(folders, folder)
+			}
+			if len(folders) != 1 || len(folders[0].Devices) != 1 || folders[0].Devices[0].EncryptionPassword != password {
+				t.Errorf("GET %s with key %s: expected encryption password %q, got %+v", path, key, password, folders)
+			}
+		}
+	}
+	if cfg.FolderList()[0].Devices[0].EncryptionPassword != "secret" {
+		t.Error("redacting modified the configuration")
+	}
+}
+
+func TestGUIUserRoles(t *testing.T) {
+	t.Parallel()
+
+	const hash = "$2a$10$IdIZTxTg/dCNuNEGlmLynOjqg4B1FvDKuIV5e0BB3pnWVHNb8.GSq" // bcrypt of "räksmörgås"
+	cfg := newMockedConfig()
+	cfg.GUIReturns(config.GUIConfiguration{
+		User:       "admin",
+		Password:   hash,
+		RawAddress: "127.0.0.1:0",
+		APIKey:     testAPIKey,
+		Users: []config.GUIUser{
+			{Name: "operator", Password: hash, Role: config.GUIRoleOperator},
+			{Name: "viewer", Password: hash, Role: config.GUIRoleViewer},
+		},
+	})
+	baseURL, cancel, err := startHTTP(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cancel)
+
+	// Getting the base URL gives us the CSRF token.
+	resp, err := http.Get(baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	var csrfHeader, csrfToken string
+	for _, cookie := range resp.Cookies() {
+		if strings.HasPrefix(cookie.Name, "CSRF-Token") {
+			csrfHeader, csrfToken = "X-"+cookie.Name, cookie.Value
+		}
+	}
+
+	do := func(method, path, user string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, baseURL+path, nil)
+		req.SetBasicAuth(user, "räksmörgås")
+		req.Header.Set(csrfHeader, csrfToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	cases := []struct {
+		method, path, user string
+		status             int
+	}{
+		{http.MethodGet, "/rest/system/version", "viewer", http.StatusOK},
+		{http.MethodGet, "/rest/config", "viewer", http.StatusOK},
+		{http.MethodPost, "/rest/db/scan?folder=default", "viewer", http.StatusForbidden},
+		{http.MethodPost, "/rest/db/scan?folder=default", "operator", http.StatusOK},
+		{http.MethodPut, "/rest/config/options", "operator", http.StatusForbidden},
+		{http.MethodPost, "/rest/system/ping", "operator", http.StatusForbidden},
+		{http.MethodPost, "/rest/system/ping", "admin", http.StatusOK},
+		{http.MethodPost, "/rest/noauth/auth/logout", "viewer", http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		resp := do(tc.method, tc.path, tc.user)
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %s as %s: got %s, expected %d", tc.method, tc.path, tc.user, resp.Status, tc.status)
+		}
+	}
+
+	// The credentials in the configuration are hidden from those who may
+	// not change it.
+	for user, apiKey := range map[string]string{"viewer": "REDACTED", "admin": testAPIKey} {
+		resp := do(http.MethodGet, "/rest/config/gui", user)
+		var gui config.GUIConfiguration
+		if err := json.NewDecoder(resp.Body).Decode(&gui); err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if gui.APIKey != apiKey {
+			t.Errorf("%s got API key %q, expected %q", user, gui.APIKey, apiKey)
+		}
+	}
+
+	// The user and role are shown to the GUI.
+	resp = do(http.MethodGet, "/meta.js", "operator")
+	bs, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(bs), `"user":{"name":"operator","role":"operator"}`) {
+		t.Errorf("expected user in metadata, got %s", bs)
+	}
+}
+
 func startHTTP(cfg config.Wrapper) (string, context.CancelFunc, error) {
+	return startHTTPWithAuditLog(cfg, nil)
+}
+
+func startHTTPWithAuditLog(cfg config.Wrapper, auditLog *auditlog.Log) (string, context.CancelFunc, error) {
 	m := new(modelmocks.Model)
 	assetDir := "../../gui"
 	eventSub := new(eventmocks.BufferedSubscription)
@@ -947,7 +1135,7 @@ func startHTTP(cfg config.Wrapper) (string, context.CancelFunc, error) {
 	urService := ur.New(cfg, m, connections, false)
 	mdb, _ := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
 	kdb := db.NewMiscDataNamespace(mdb)
-	svc := New(protocol.LocalDeviceID, cfg, assetDir, "syncthing", m, eventSub, diskEventSub, events.NoopLogger, discoverer, connections, urService, mockedSummary, errorLog, systemLog, false, kdb).(*service)
+	svc := New(protocol.LocalDeviceID, cfg, assetDir, "syncthing", m, eventSub, diskEventSub, events.NoopLogger, discoverer, connections, urService, mockedSummary, errorLog, systemLog, false, kdb, auditLog).(*service)
 	svc.started = addrChan
 
 	// Actually start the API service
@@ -1489,7 +1677,7 @@ func TestEventMasks(t *testing.T) {
 	diskSub := new(eventmocks.BufferedSubscription)
 	mdb, _ := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
 	kdb := db.NewMiscDataNamespace(mdb)
-	svc := New(protocol.LocalDeviceID, cfg, "", "syncthing", nil, defSub, diskSub, events.NoopLogger, nil, nil, nil, nil, nil, nil, false, kdb).(*service)
+	svc := New(protocol.LocalDeviceID, cfg, "", "syncthing", nil, defSub, diskSub, events.NoopLogger, nil, nil, nil, nil, nil, nil, false, kdb, nil).(*service)
 
 	if mask := svc.getEventMask(""); mask != DefaultEventMask {
 		t.Errorf("incorrect default mask %x != %x", int64(mask), int64(DefaultEventMask))
@@ -1779,3 +1967,63 @@ func runningInContainer() bool {
 	}
 	return false
 }
+
+func TestParseSearchQuery(t *testing.T) {
+	t.Parallel()
+
+	qs, _ := url.ParseQuery("folder=a&folder=b&name=*.psd&regex=^photos/&minsize=1073741824&modifiedafter=2025-01-02T03:04:05Z&type=file&type=Directory&need=" + protocol.LocalDeviceID.String() + "&offset=10&limit=5")
+	query, err := parseSearchQuery(qs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(query.Folders, []string{"a", "b"}) || query.Glob != "*.psd" || query.Regexp.String() != "^photos/" {
+		t.Error("unexpected name filters", query)
+	}
+	if query.MinSize != 1<<30 || query.MaxSize != 0 || query.ModifiedAfter.Unix() != 1735787045 || !query.ModifiedBefore.IsZero() {
+		t.Error("unexpected size or time filters", query)
+	}
+	if !slices.Equal(query.Types, []protocol.FileInfoType{protocol.FileInfoTypeFile, protocol.FileInfoTypeDirectory}) {
+		t.Error("unexpected types", query.Types)
+	}
+	if query.NeedDevice != protocol.LocalDeviceID || query.HaveDevice != protocol.EmptyDeviceID {
+		t.Error("unexpected devices", query)
+	}
+	if query.Offset != 10 || query.Limit != 5 {
+		t.Error("unexpected page", query.Offset, query.Limit)
+	}
+
+	for _, bad := range []string{"regex=(", "minsize=big", "modifiedbefore=yesterday", "type=socket", "have=nope", "limit=-1"} {
+		qs, _ := url.ParseQuery(bad)
+		if _, err := parseSearchQuery(qs); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
+
+func TestFileHistoryJSON(t *testing.T) {
+	t.Parallel()
+
+	s := &service{id: protocol.DeviceID{1}}
+	bs, err := json.Marshal(s.toJSONFileHistory([]db.FileHistoryEntry{{
+		Device:     protocol.LocalDeviceID,
+		Local:      true,
+		Version:    protocol.Vector{Counters: []protocol.Counter{{ID: 42, Value: 7}}},
+		ModifiedBy: 42,
+	}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res []map[string]any
+	if err := json.Unmarshal(bs, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res[0]["device"] != s.id.String() || res[0]["local"] != true {
+		t.Error("expected the local device ID, got", res[0]["device"])
+	}
+	if v := res[0]["version"].([]any); len(v) != 1 || v[0] != protocol.ShortID(42).String()+":7" {
+		t.Error("unexpected version", v)
+	}
+	if res[0]["modifiedBy"] != protocol.ShortID(42).String() {
+		t.Error("unexpected modifier", res[0]["modifiedBy"])
+	}
+}
@@ -19,15 +19,13 @@ import (
 )
 
 type configMuxBuilder struct {
-	*httprouter.Router
+	*restRouter
 	id  protocol.DeviceID
 	cfg config.Wrapper
 }
 
 func (c *configMuxBuilder) registerConfig(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.RawCopy())
-	})
+	c.HandlerFunc(http.MethodGet, path, c.getConfig)
 
 	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
 		c.adjustConfig(w, r)
@@ -35,9 +33,7 @@ func (c *configMuxBuilder) registerConfig(path string) {
 }
 
 func (c *configMuxBuilder) registerConfigDeprecated(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.RawCopy())
-	})
+	c.HandlerFunc(http.MethodGet, path, c.getConfig)
 
 	c.HandlerFunc(http.MethodPost, path, func(w http.ResponseWriter, r *http.Request) {
 		c.adjustConfig(w, r)
@@ -57,8 +53,12 @@ func (c *configMuxBuilder) registerConfigRequiresRestart(path string) {
 }
 
 func (c *configMuxBuilder) registerFolders(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.FolderList())
+	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		folders := allowedFolders(r, c.cfg.FolderList())
+		if !mayChangeConfig(r) {
+			folders = redactFoldersSecrets(folders)
+		}
+		sendJSON(w, folders)
 	})
 
 	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
@@ -76,7 +76,7 @@ func (c *configMuxBuilder) registerFolders(path string) {
 				return
 			}
 		}
-		waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+		waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 			cfg.SetFolders(folders)
 		})
 		if err != nil {
@@ -111,7 +111,7 @@ func (c *configMuxBuilder) registerDevices(path string) {
 				return
 			}
 		}
-		waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+		waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 			cfg.SetDevices(devices)
 		})
 		if err != nil {
@@ -127,12 +127,15 @@ func (c *configMuxBuilder) registerDevices(path string) {
 }
 
 func (c *configMuxBuilder) registerFolder(path string) {
-	c.Handle(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request, p httprouter.Params) {
+	c.Handle(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		folder, ok := c.cfg.Folder(p.ByName("id"))
 		if !ok {
 			http.Error(w, "No folder with given ID", http.StatusNotFound)
 			return
 		}
+		if !mayChangeConfig(r) {
+			redactFolderSecrets(&folder)
+		}
 		sendJSON(w, folder)
 	})
 
@@ -149,8 +152,12 @@ func (c *configMuxBuilder) registerFolder(path string) {
 		c.adjustFolder(w, r, folder, false)
 	})
 
-	c.Handle(http.MethodDelete, path, func(w http.ResponseWriter, _ *http.Request, p httprouter.Params) {
-		waiter, err := c.cfg.RemoveFolder(p.ByName("id"))
+	c.Handle(http.MethodDelete, path, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
+			if _, i, ok := cfg.Folder(p.ByName("id")); ok {
+				cfg.Folders = append(cfg.Folders[:i], cfg.Folders[i+1:]...)
+			}
+		})
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -190,13 +197,17 @@ func (c *configMuxBuilder) registerDevice(path string) {
 		}
 	})
 
-	c.Handle(http.MethodDelete, path, func(w http.ResponseWriter, _ *http.Request, p httprouter.Params) {
+	c.Handle(http.MethodDelete, path, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		id, err := protocol.DeviceIDFromString(p.ByName("id"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		waiter, err := c.cfg.RemoveDevice(id)
+		waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
+			if _, i, ok := cfg.Device(id); ok {
+				cfg.Devices = append(cfg.Devices[:i], cfg.Devices[i+1:]...)
+			}
+		})
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -206,8 +217,12 @@ func (c *configMuxBuilder) registerDevice(path string) {
 }
 
 func (c *configMuxBuilder) registerDefaultFolder(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.DefaultFolder())
+	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		folder := c.cfg.DefaultFolder()
+		if !mayChangeConfig(r) {
+			redactFolderSecrets(&folder)
+		}
+		sendJSON(w, folder)
 	})
 
 	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
@@ -248,7 +263,7 @@ func (c *configMuxBuilder) registerDefaultIgnores(path string) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+		waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 			cfg.Defaults.Ignores = ignores
 		})
 		if err != nil {
@@ -260,8 +275,12 @@ func (c *configMuxBuilder) registerDefaultIgnores(path string) {
 }
 
 func (c *configMuxBuilder) registerOptions(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.Options())
+	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		opts := c.cfg.Options()
+		if !mayChangeConfig(r) {
+			redactOptionsSecrets(&opts)
+		}
+		sendJSON(w, opts)
 	})
 
 	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
@@ -291,9 +310,32 @@ func (c *configMuxBuilder) registerLDAP(path string) {
 	})
 }
 
+func (c *configMuxBuilder) registerOIDC(path string) {
+	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		// The client secret is only ever needed by us.
+		oidc := c.cfg.OIDC()
+		redactOIDCSecrets(&oidc)
+		sendJSON(w, oidc)
+	})
+
+	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
+		var cfg config.OIDCConfiguration
+		structutil.SetDefaults(&cfg)
+		c.adjustOIDC(w, r, cfg)
+	})
+
+	c.HandlerFunc(http.MethodPatch, path, func(w http.ResponseWriter, r *http.Request) {
+		c.adjustOIDC(w, r, c.cfg.OIDC())
+	})
+}
+
 func (c *configMuxBuilder) registerGUI(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.GUI())
+	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		gui := c.cfg.GUI()
+		if !mayChangeConfig(r) {
+			redactGUISecrets(&gui)
+		}
+		sendJSON(w, gui)
 	})
 
 	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
@@ -307,6 +349,16 @@ func (c *configMuxBuilder) registerGUI(path string) {
 	})
 }
 
+func (c *configMuxBuilder) getConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := c.cfg.RawCopy()
+	if !mayChangeConfig(r) {
+		redactSecrets(&cfg)
+	}
+	// The OIDC client secret is only ever needed by us.
+	redactOIDCSecrets(&cfg.OIDC)
+	sendJSON(w, cfg)
+}
+
 func (c *configMuxBuilder) adjustConfig(w http.ResponseWriter, r *http.Request) {
 	to, err := config.ReadJSON(r.Body, c.id)
 	r.Body.Close()
@@ -317,7 +369,7 @@ func (c *configMuxBuilder) adjustConfig(w http.ResponseWriter, r *http.Request)
 	}
 	var errMsg string
 	var status int
-	waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+	waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 		if to.GUI.Password != cfg.GUI.Password {
 			if err := to.GUI.SetPassword(to.GUI.Password); err != nil {
 				l.Warnln("hashing password:", err)
@@ -326,6 +378,7 @@ func (c *configMuxBuilder) adjustConfig(w http.ResponseWriter, r *http.Request)
 				return
 			}
 		}
+		keepOIDCSecrets(&to.OIDC, cfg.OIDC)
 		*cfg = to
 	})
 	if errMsg != "" {
@@ -342,7 +395,7 @@ func (c *configMuxBuilder) adjustFolder(w http.ResponseWriter, r *http.Request,
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+	waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 		if defaults {
 			cfg.Defaults.Folder = folder
 		} else {
@@ -361,7 +414,7 @@ func (c *configMuxBuilder) adjustDevice(w http.ResponseWriter, r *http.Request,
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+	waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 		if defaults {
 			cfg.Defaults.Device = device
 		} else {
@@ -380,7 +433,7 @@ func (c *configMuxBuilder) adjustOptions(w http.ResponseWriter, r *http.Request,
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+	waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 		cfg.Options = opts
 	})
 	if err != nil {
@@ -399,7 +452,7 @@ func (c *configMuxBuilder) adjustGUI(w http.ResponseWriter, r *http.Request, gui
 	}
 	var errMsg string
 	var status int
-	waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+	waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 		if gui.Password != oldPassword {
 			if err := gui.SetPassword(gui.Password); err != nil {
 				l.Warnln("hashing password:", err)
@@ -424,7 +477,7 @@ func (c *configMuxBuilder) adjustLDAP(w http.ResponseWriter, r *http.Request, ld
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	waiter, err := c.cfg.Modify(func(cfg *config.Configuration) {
+	waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
 		cfg.LDAP = ldap
 	})
 	if err != nil {
@@ -434,6 +487,22 @@ func (c *configMuxBuilder) adjustLDAP(w http.ResponseWriter, r *http.Request, ld
 	c.finish(w, waiter)
 }
 
+func (c *configMuxBuilder) adjustOIDC(w http.ResponseWriter, r *http.Request, oidc config.OIDCConfiguration) {
+	if err := unmarshalTo(r.Body, &oidc); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	waiter, err := modifyConfig(r, c.cfg, func(cfg *config.Configuration) {
+		keepOIDCSecrets(&oidc, cfg.OIDC)
+		cfg.OIDC = oidc
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	c.finish(w, waiter)
+}
+
 // Unmarshals the content of the given body and stores it in to (i.e. to must be a pointer).
 func unmarshalTo(body io.ReadCloser, to interface{}) error {
 	bs, err := io.ReadAll(body)
@@ -16,10 +16,7 @@ import (
 // getRedactedConfig redacting some parts of config
 func getRedactedConfig(s *service) config.Configuration {
 	rawConf := s.cfg.RawCopy()
-	rawConf.GUI.APIKey = "REDACTED"
-	if rawConf.GUI.Password != "" {
-		rawConf.GUI.Password = "REDACTED"
-	}
+	redactSecrets(&rawConf)
 	if rawConf.GUI.User != "" {
 		rawConf.GUI.User = "REDACTED"
 	}
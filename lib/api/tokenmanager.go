@@ -43,6 +43,9 @@ func newTokenManager(key string, miscDB *db.NamespacedKV, lifetime time.Duration
 	if tokens.Tokens == nil {
 		tokens.Tokens = make(map[string]int64)
 	}
+	if tokens.Users == nil {
+		tokens.Users = make(map[string]*apiproto.SessionUser)
+	}
 	return &tokenManager{
 		key:      key,
 		miscDB:   miscDB,
@@ -77,23 +80,42 @@ func (m *tokenManager) Check(token string) bool {
 
 // New creates a new token and returns it.
 func (m *tokenManager) New() string {
+	return m.NewForUser(nil)
+}
+
+// NewForUser creates a new token belonging to the given user and returns
+// it.
+func (m *tokenManager) NewForUser(user *apiproto.SessionUser) string {
 	token := rand.String(randomTokenLength)
 
 	m.mut.Lock()
 	defer m.mut.Unlock()
 
 	m.tokens.Tokens[token] = m.timeNow().Add(m.lifetime).UnixNano()
+	if user != nil {
+		m.tokens.Users[token] = user
+	}
 	m.saveLocked()
 
 	return token
 }
 
+// User returns the user the token belongs to, if it was created for one.
+func (m *tokenManager) User(token string) (*apiproto.SessionUser, bool) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	user, ok := m.tokens.Users[token]
+	return user, ok
+}
+
 // Delete removes a token.
 func (m *tokenManager) Delete(token string) {
 	m.mut.Lock()
 	defer m.mut.Unlock()
 
 	delete(m.tokens.Tokens, token)
+	delete(m.tokens.Users, token)
 	m.saveLocked()
 }
 
@@ -126,6 +148,13 @@ func (m *tokenManager) saveLocked() {
 		}
 	}
 
+	// Forget the users of removed tokens.
+	for token := range m.tokens.Users {
+		if _, ok := m.tokens.Tokens[token]; !ok {
+			delete(m.tokens.Users, token)
+		}
+	}
+
 	// Postpone saving until one second of inactivity.
 	if m.saveTimer == nil {
 		m.saveTimer = time.AfterFunc(time.Second, m.scheduledSave)
@@ -162,18 +191,8 @@ func newTokenCookieManager(shortID string, guiCfg config.GUIConfiguration, evLog
 	}
 }
 
-func (m *tokenCookieManager) createSession(username string, persistent bool, w http.ResponseWriter, r *http.Request) {
-	sessionid := m.tokens.New()
-
-	// Best effort detection of whether the connection is HTTPS --
-	// either directly to us, or as used by the client towards a reverse
-	// proxy who sends us headers.
-	connectionIsHTTPS := r.TLS != nil ||
-		strings.ToLower(r.Header.Get("x-forwarded-proto")) == "https" ||
-		strings.Contains(strings.ToLower(r.Header.Get("forwarded")), "proto=https")
-	// If the connection is HTTPS, or *should* be HTTPS, set the Secure
-	// bit in cookies.
-	useSecureCookie := connectionIsHTTPS || m.guiCfg.UseTLS()
+func (m *tokenCookieManager) createSession(username string, role config.GUIRole, persistent bool, w http.ResponseWriter, r *http.Request) {
+	sessionid := m.tokens.NewForUser(&apiproto.SessionUser{Name: username, Role: string(role)})
 
 	maxAge := 0
 	if persistent {
@@ -185,14 +204,34 @@ func (m *tokenCookieManager) createSession(username string, persistent bool, w h
 		// In HTTP spec Max-Age <= 0 means delete immediately,
 		// but in http.Cookie MaxAge = 0 means unspecified (session) and MaxAge < 0 means delete immediately
 		MaxAge: maxAge,
-		Secure: useSecureCookie,
+		Secure: m.useSecureCookie(r),
 		Path:   "/",
 	})
 
 	emitLoginAttempt(true, username, r.RemoteAddr, m.evLogger)
 }
 
+func (m *tokenCookieManager) useSecureCookie(r *http.Request) bool {
+	// Best effort detection of whether the connection is HTTPS --
+	// either directly to us, or as used by the client towards a reverse
+	// proxy who sends us headers.
+	connectionIsHTTPS := r.TLS != nil ||
+		strings.ToLower(r.Header.Get("x-forwarded-proto")) == "https" ||
+		strings.Contains(strings.ToLower(r.Header.Get("forwarded")), "proto=https")
+	// If the connection is HTTPS, or *should* be HTTPS, set the Secure
+	// bit in cookies.
+	return connectionIsHTTPS || m.guiCfg.UseTLS()
+}
+
 func (m *tokenCookieManager) hasValidSession(r *http.Request) bool {
+	_, ok := m.sessionUser(r)
+	return ok
+}
+
+// sessionUser returns the user of the valid session of the request, if
+// there is one. The user is nil for sessions created before sessions had
+// users.
+func (m *tokenCookieManager) sessionUser(r *http.Request) (*apiproto.SessionUser, bool) {
 	for _, cookie := range r.Cookies() {
 		// We iterate here since there may, historically, be multiple
 		// cookies with the same name but different path. Any "old" ones
@@ -200,11 +239,12 @@ func (m *tokenCookieManager) hasValidSession(r *http.Request) bool {
 		// later removed on logout or when timing out.
 		if cookie.Name == m.cookieName {
 			if m.tokens.Check(cookie.Value) {
-				return true
+				user, _ := m.tokens.User(cookie.Value)
+				return user, true
 			}
 		}
 	}
-	return false
+	return nil, false
 }
 
 func (m *tokenCookieManager) destroySession(w http.ResponseWriter, r *http.Request) {
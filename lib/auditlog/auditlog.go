@@ -0,0 +1,173 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+// Package auditlog keeps a record of who changed what through the REST API,
+// in a rotating file of JSON lines.
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxSize  = 10 << 20
+	DefaultMaxFiles = 5
+)
+
+// An Entry records one change made through the REST API.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// The user logged in to the GUI, or the API key used, if any.
+	User     string `json:"user,omitempty"`
+	Address  string `json:"address"`
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	// What was done, like "config", "override" or "shutdown".
+	Action string `json:"action"`
+	Folder string `json:"folder,omitempty"`
+	Device string `json:"device,omitempty"`
+	// The changes to the configuration caused by the request, if any.
+	Diff []Change `json:"diff,omitempty"`
+}
+
+// A Log appends entries to a file, which is rotated once it grows beyond
+// the maximum size. The rotated files are kept as name.1.ext, name.2.ext
+// and so on, up to the maximum number of files. A nil *Log records nothing.
+type Log struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mut sync.Mutex
+}
+
+// Open returns a Log writing to the given path, creating the file if it
+// doesn't exist.
+func Open(path string, maxSize int64, maxFiles int) (*Log, error) {
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := fd.Close(); err != nil {
+		return nil, err
+	}
+	return &Log{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}, nil
+}
+
+// Record appends the entry to the log. Entries are written right away, so
+// that they survive the process exiting, as it does after a shutdown.
+func (l *Log) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	bs, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	bs = append(bs, '\n')
+
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	if info, err := os.Stat(l.path); err == nil && info.Size() > 0 && info.Size()+int64(len(bs)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("rotating audit log: %w", err)
+		}
+	}
+
+	fd, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(bs); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
+
+// rotate moves every file one number up, dropping the oldest.
+func (l *Log) rotate() error {
+	for i := l.maxFiles - 1; i > 0; i-- {
+		from := l.numberedPath(i - 1)
+		if err := os.Rename(from, l.numberedPath(i)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if l.maxFiles < 2 {
+		return os.Remove(l.path)
+	}
+	return nil
+}
+
+// numberedPath returns the path of the nth file, where zero is the current
+// one.
+func (l *Log) numberedPath(n int) string {
+	if n == 0 {
+		return l.path
+	}
+	ext := filepath.Ext(l.path)
+	return fmt.Sprintf("%s.%d%s", strings.TrimSuffix(l.path, ext), n, ext)
+}
+
+// Entries returns the entries recorded after the given time, oldest first.
+// At most limit of the newest entries are returned, unless limit is zero.
+func (l *Log) Entries(since time.Time, limit int) ([]Entry, error) {
+	entries := []Entry{}
+	if l == nil {
+		return entries, nil
+	}
+
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	for i := max(l.maxFiles-1, 0); i >= 0; i-- {
+		fd, err := os.Open(l.numberedPath(i))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(fd)
+		scanner.Buffer(nil, 16<<20)
+		for scanner.Scan() {
+			var e Entry
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				// A line cut short by a crash; skip it.
+				continue
+			}
+			if e.Time.After(since) {
+				entries = append(entries, e)
+			}
+		}
+		err = scanner.Err()
+		fd.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
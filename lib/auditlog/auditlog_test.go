@@ -0,0 +1,197 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package auditlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRotation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	log, err := Open(filepath.Join(dir, "audit.jsonl"), 512, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 50; i++ {
+		e := Entry{
+			Time:     start.Add(time.Duration(i) * time.Second),
+			User:     "alice",
+			Endpoint: "/rest/config/folders/default",
+			Action:   fmt.Sprint("action", i),
+		}
+		if err := log.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"audit.jsonl", "audit.1.jsonl", "audit.2.jsonl"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 512 {
+			t.Errorf("%s is %d bytes, larger than the maximum", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "audit.3.jsonl")); err == nil {
+		t.Error("expected no more than three files")
+	}
+
+	entries, err := log.Entries(time.Time{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || len(entries) >= 50 {
+		t.Fatal("expected the oldest entries to be dropped, got", len(entries))
+	}
+	if last := entries[len(entries)-1]; last.Action != "action49" {
+		t.Error("expected the newest entry last, got", last.Action)
+	}
+	for i := 1; i < len(entries); i++ {
+		if !entries[i].Time.After(entries[i-1].Time) {
+			t.Fatal("expected entries in order")
+		}
+	}
+
+	entries, err = log.Entries(start.Add(45*time.Second), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Error("expected four entries after the given time, got", len(entries))
+	}
+
+	entries, err = log.Entries(time.Time{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Action != "action49" {
+		t.Error("expected the two newest entries, got", entries)
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	t.Parallel()
+
+	var log *Log
+	if err := log.Record(Entry{}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := log.Entries(time.Time{}, 0)
+	if err != nil || len(entries) != 0 {
+		t.Fatal("expected no entries, got", entries, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	type device struct {
+		DeviceID string `json:"deviceID"`
+		Name     string `json:"name"`
+	}
+	type folder struct {
+		ID      string   `json:"id"`
+		Path    string   `json:"path"`
+		Devices []device `json:"devices"`
+	}
+	type gui struct {
+		Password string `json:"password"`
+		Theme    string `json:"theme"`
+	}
+	type config struct {
+		Folders []folder `json:"folders"`
+		GUI     gui      `json:"gui"`
+		Hosts   []string `json:"hosts"`
+	}
+
+	before := config{
+		Folders: []folder{
+			{ID: "a", Path: "/a", Devices: []device{{DeviceID: "D1"}}},
+			{ID: "b", Path: "/b"},
+		},
+		GUI:   gui{Password: "secret", Theme: "default"},
+		Hosts: []string{"one"},
+	}
+	after := config{
+		Folders: []folder{
+			{ID: "b", Path: "/b"},
+			{ID: "c", Path: "/c"},
+		},
+		GUI:   gui{Password: "other", Theme: "dark"},
+		Hosts: []string{"one", "two"},
+	}
+
+	changes, err := Diff(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{
+		{Path: "folders[a]", Before: map[string]any{"id": "a", "path": "/a", "devices": []any{map[string]any{"deviceID": "D1", "name": ""}}}},
+		{Path: "folders[c]", After: map[string]any{"id": "c", "path": "/c", "devices": nil}},
+		{Path: "gui.password", Before: "REDACTED", After: "REDACTED"},
+		{Path: "gui.theme", Before: "default", After: "dark"},
+		{Path: "hosts", Before: []any{"one"}, After: []any{"one", "two"}},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected diff\n got: %#v\nwant: %#v", changes, expected)
+	}
+
+	if changes, _ := Diff(before, before); len(changes) != 0 {
+		t.Error("expected no changes, got", changes)
+	}
+}
+
+func TestDiffRedactsNested(t *testing.T) {
+	t.Parallel()
+
+	before := map[string]any{"users": []any{}}
+	after := map[string]any{"users": []any{map[string]any{"name": "bob", "password": "hunter2"}}}
+	changes, err := Diff(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{{
+		Path:  "users",
+		After: []any{map[string]any{"name": "bob", "password": "REDACTED"}},
+	}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected diff\n got: %#v\nwant: %#v", changes, expected)
+	}
+}
+
+func TestDiffRedactsPathCredentials(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"/home/user/Sync": "/home/user/Sync",
+		"s3://bucket/prefix?region=eu&accessKeyID=id&secretKey=key": "s3://bucket/prefix?accessKeyID=REDACTED&region=eu&secretKey=REDACTED",
+		"s3://id:key@bucket":   "s3://REDACTED@bucket",
+		"s3://bucket?region=x": "s3://bucket?region=x",
+	}
+	for path, expected := range cases {
+		changes, err := Diff(map[string]any{"folders": []any{}}, map[string]any{"folders": []any{map[string]any{"id": "a", "path": path}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 1 {
+			t.Fatal("expected one change, got", changes)
+		}
+		if res := changes[0].After.(map[string]any)["path"]; res != expected {
+			t.Errorf("path %q redacted to %q, expected %q", path, res, expected)
+		}
+	}
+}
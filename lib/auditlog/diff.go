@@ -0,0 +1,245 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package auditlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// A Change is a value that differs between two versions of the
+// configuration. Before or After is missing when the value was added or
+// removed.
+type Change struct {
+	// Like "folders[default].path" or "gui.theme".
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// Names of values that are never written to the log as they are.
+var secretKeys = []string{"password", "apiKey", "key", "clientSecret", "encryptionPassword", "secret"}
+
+const redacted = "REDACTED"
+
+// Query parameters of paths, such as those of older S3 folders, that hold
+// credentials.
+var secretParams = []string{"accessKeyID", "secretKey"}
+
+// Diff returns the differences between the JSON representations of before
+// and after. Lists of objects with an "id" or "deviceID", like folders and
+// devices, are compared by that rather than by position, so that removing
+// one doesn't show up as a change to all the following. Secrets are
+// redacted.
+func Diff(before, after any) ([]Change, error) {
+	b, err := toJSONValue(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toJSONValue(after)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	diffValues("", "", b, a, &changes)
+	return changes, nil
+}
+
+func toJSONValue(v any) (any, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var res any
+	if err := json.Unmarshal(bs, &res); err != nil {
+		return nil, err
+	}
+	return normalize(res), nil
+}
+
+func diffValues(path, key string, before, after any, changes *[]Change) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+
+	bm, bok := before.(map[string]any)
+	am, aok := after.(map[string]any)
+	if bok && aok {
+		keys := make([]string, 0, len(bm)+len(am))
+		for k := range bm {
+			keys = append(keys, k)
+		}
+		for k := range am {
+			if _, ok := bm[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			diffValues(joinPath(path, k), k, bm[k], am[k], changes)
+		}
+		return
+	}
+
+	bl, bok := keyedList(before)
+	al, aok := keyedList(after)
+	if bok && aok {
+		ids := make([]string, 0, len(bl)+len(al))
+		for _, e := range bl {
+			ids = append(ids, e.id)
+		}
+		for _, e := range al {
+			if !slices.ContainsFunc(bl, func(b keyedElem) bool { return b.id == e.id }) {
+				ids = append(ids, e.id)
+			}
+		}
+		for _, id := range ids {
+			diffValues(fmt.Sprintf("%s[%s]", path, id), "", findElem(bl, id), findElem(al, id), changes)
+		}
+		return
+	}
+
+	*changes = append(*changes, Change{
+		Path:   path,
+		Before: redact(key, before),
+		After:  redact(key, after),
+	})
+}
+
+// redact returns the value with all secrets in it replaced.
+func redact(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, e := range v {
+			res[k] = redact(k, e)
+		}
+		return res
+	case []any:
+		res := make([]any, len(v))
+		for i, e := range v {
+			res[i] = redact(key, e)
+		}
+		return res
+	case nil:
+		return nil
+	case string:
+		if slices.Contains(secretKeys, key) && v != "" {
+			return redacted
+		}
+		if key == "path" {
+			return redactPath(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// redactPath returns the path with the credentials in its query parameters
+// and user info, if any, replaced.
+func redactPath(p string) string {
+	base, query, ok := strings.Cut(p, "?")
+	if u, err := url.Parse(base); err == nil && u.User != nil {
+		u.User = url.User(redacted)
+		base = u.String()
+	}
+	if !ok {
+		return base
+	}
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return base + "?" + redacted
+	}
+	for _, k := range secretParams {
+		if params.Has(k) {
+			params.Set(k, redacted)
+		}
+	}
+	return base + "?" + params.Encode()
+}
+
+// normalize replaces empty lists and objects by null, as they are all the
+// same as far as the configuration is concerned.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, e := range v {
+			v[i] = normalize(e)
+		}
+	case map[string]any:
+		if len(v) == 0 {
+			return nil
+		}
+		for k, e := range v {
+			v[k] = normalize(e)
+		}
+	}
+	return v
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+type keyedElem struct {
+	id  string
+	val any
+}
+
+// keyedList returns the elements of a list of objects by their ID, if they
+// all have one. Null is an empty list.
+func keyedList(v any) ([]keyedElem, bool) {
+	if v == nil {
+		return nil, true
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	elems := make([]keyedElem, 0, len(list))
+	for _, e := range list {
+		m, ok := e.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		id, ok := elemID(m)
+		if !ok {
+			return nil, false
+		}
+		elems = append(elems, keyedElem{id, e})
+	}
+	return elems, true
+}
+
+func elemID(m map[string]any) (string, bool) {
+	for _, k := range []string{"id", "deviceID"} {
+		if id, ok := m[k].(string); ok && id != "" && !strings.ContainsAny(id, "[]") {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func findElem(elems []keyedElem, id string) any {
+	for _, e := range elems {
+		if e.id == id {
+			return e.val
+		}
+	}
+	return nil
+}
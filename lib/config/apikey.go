@@ -0,0 +1,70 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"slices"
+
+	"github.com/syncthing/syncthing/lib/rand"
+)
+
+// An APIPermission is a set of REST API operations that can be granted to
+// an API key.
+type APIPermission string
+
+const (
+	// Reading status, statistics, events and the configuration except
+	// for the GUI, LDAP and OpenID Connect settings.
+	APIPermissionRead APIPermission = "read"
+	// Scanning, overriding, reverting and the other folder operations.
+	APIPermissionFolder APIPermission = "folder"
+	// Reading and changing the whole configuration.
+	APIPermissionConfig APIPermission = "config"
+	// Restarting, shutting down, upgrading, pausing devices and the other
+	// system operations, including debugging.
+	APIPermissionSystem APIPermission = "system"
+)
+
+// An APIKey is an additional API key with limited permissions, in contrast
+// to the main API key of the GUI configuration which grants everything.
+type APIKey struct {
+	Name        string          `json:"name" xml:"name,attr"`
+	Key         string          `json:"key" xml:"key"`
+	Permissions []APIPermission `json:"permissions" xml:"permission"`
+	// If set, the key may only be used for these folders.
+	Folders []string `json:"folders" xml:"folder"`
+}
+
+func (k APIKey) Has(perm APIPermission) bool {
+	return slices.Contains(k.Permissions, perm)
+}
+
+// AllowsFolder returns whether the key may be used for the given folder.
+func (k APIKey) AllowsFolder(folder string) bool {
+	return len(k.Folders) == 0 || slices.Contains(k.Folders, folder)
+}
+
+func (k APIKey) Copy() APIKey {
+	c := k
+	c.Permissions = slices.Clone(k.Permissions)
+	c.Folders = slices.Clone(k.Folders)
+	return c
+}
+
+func (k *APIKey) prepare() {
+	if k.Key == "" {
+		k.Key = rand.String(32)
+	}
+	k.Permissions = slices.DeleteFunc(k.Permissions, func(perm APIPermission) bool {
+		switch perm {
+		case APIPermissionRead, APIPermissionFolder, APIPermissionConfig, APIPermissionSystem:
+			return false
+		}
+		l.Warnf("Ignoring unknown permission %q of API key %q", perm, k.Name)
+		return true
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A BandwidthWindow sets the rate limits to use during a time range on
+// some days of the week. The times are given as "HH:MM" in local time; a
+// window whose end is before its start extends over midnight into the
+// following day, and one whose start and end are equal covers the whole
+// day. Days are given as "mon" through "sun", no days meaning every day.
+type BandwidthWindow struct {
+	Days        []string `json:"days" xml:"day"`
+	Start       string   `json:"start" xml:"start,attr"`
+	End         string   `json:"end" xml:"end,attr"`
+	MaxSendKbps int      `json:"maxSendKbps" xml:"maxSendKbps"`
+	MaxRecvKbps int      `json:"maxRecvKbps" xml:"maxRecvKbps"`
+}
+
+// A BandwidthSchedule is a list of windows, of which the first one that
+// is active overrides the static rate limits.
+type BandwidthSchedule []BandwidthWindow
+
+// Active returns the first window of the schedule that is active at the
+// given time.
+func (s BandwidthSchedule) Active(t time.Time) (BandwidthWindow, bool) {
+	for _, w := range s {
+		if w.Active(t) {
+			return w, true
+		}
+	}
+	return BandwidthWindow{}, false
+}
+
+// Limits returns the send and receive limits in KiB/s at the given time,
+// given the static limits that apply outside of the scheduled windows.
+func (s BandwidthSchedule) Limits(t time.Time, maxSendKbps, maxRecvKbps int) (int, int) {
+	if w, ok := s.Active(t); ok {
+		return w.MaxSendKbps, w.MaxRecvKbps
+	}
+	return maxSendKbps, maxRecvKbps
+}
+
+func (s BandwidthSchedule) Copy() BandwidthSchedule {
+	if s == nil {
+		return nil
+	}
+	c := make(BandwidthSchedule, len(s))
+	for i, w := range s {
+		c[i] = w
+		c[i].Days = append([]string(nil), w.Days...)
+	}
+	return c
+}
+
+// prepare removes and warns about windows that can't be parsed.
+func (s BandwidthSchedule) prepare(owner string) BandwidthSchedule {
+	valid := s[:0]
+	for _, w := range s {
+		if err := w.validate(); err != nil {
+			l.Warnf("Ignoring bandwidth schedule window of %s: %v", owner, err)
+			continue
+		}
+		valid = append(valid, w)
+	}
+	return valid
+}
+
+func (w BandwidthWindow) validate() error {
+	if _, err := parseTimeOfDay(w.Start); err != nil {
+		return err
+	}
+	if _, err := parseTimeOfDay(w.End); err != nil {
+		return err
+	}
+	for _, day := range w.Days {
+		if _, err := parseWeekday(day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Active returns whether the window applies at the given time.
+func (w BandwidthWindow) Active(t time.Time) bool {
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+
+	switch {
+	case start == end:
+		return w.onDay(day)
+	case start < end:
+		return now >= start && now < end && w.onDay(day)
+	default:
+		// Spans midnight; the early morning part belongs to the window
+		// that started on the previous day.
+		if now >= start {
+			return w.onDay(day)
+		}
+		return now < end && w.onDay((day+6)%7)
+	}
+}
+
+func (w BandwidthWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if wd, err := parseWeekday(d); err == nil && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeOfDay returns the minutes since midnight for a "HH:MM" string.
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) >= 3 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), s) {
+				return d, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid day of week %q", s)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBandwidthWindowActive(t *testing.T) {
+	// 2025-03-03 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, time.Local)
+	}
+	cases := []struct {
+		w      BandwidthWindow
+		t      time.Time
+		active bool
+	}{
+		{BandwidthWindow{Start: "09:00", End: "17:00"}, at(3, 9, 0), true},
+		{BandwidthWindow{Start: "09:00", End: "17:00"}, at(3, 17, 0), false},
+		{BandwidthWindow{Start: "09:00", End: "17:00", Days: []string{"Tue"}}, at(3, 12, 0), false},
+		{BandwidthWindow{Start: "09:00", End: "17:00", Days: []string{"monday"}}, at(3, 12, 0), true},
+		{BandwidthWindow{Start: "23:00", End: "02:00", Days: []string{"sun"}}, at(3, 1, 59), true},
+		{BandwidthWindow{Start: "23:00", End: "02:00", Days: []string{"sun"}}, at(3, 23, 0), false},
+		{BandwidthWindow{Start: "00:00", End: "00:00", Days: []string{"sat", "sun"}}, at(8, 15, 0), true},
+		{BandwidthWindow{Start: "9", End: "17:00"}, at(3, 12, 0), false},
+	}
+	for _, tc := range cases {
+		if active := tc.w.Active(tc.t); active != tc.active {
+			t.Errorf("%+v active at %v = %v, expected %v", tc.w, tc.t, active, tc.active)
+		}
+	}
+}
+
+func TestBandwidthSchedulePrepare(t *testing.T) {
+	s := BandwidthSchedule{
+		{Start: "08:00", End: "17:00", MaxSendKbps: 1},
+		{Start: "8am", End: "17:00", MaxSendKbps: 2},
+		{Start: "08:00", End: "17:00", Days: []string{"someday"}, MaxSendKbps: 3},
+		{Start: "18:00", End: "19:00", Days: []string{"fri"}, MaxSendKbps: 4},
+	}.prepare("test")
+	if len(s) != 2 || s[0].MaxSendKbps != 1 || s[1].MaxSendKbps != 4 {
+		t.Error("expected invalid windows to be removed, got", s)
+	}
+}
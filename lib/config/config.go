@@ -105,6 +105,7 @@ type Configuration struct {
 	Devices                  []DeviceConfiguration `json:"devices" xml:"device"`
 	GUI                      GUIConfiguration      `json:"gui" xml:"gui"`
 	LDAP                     LDAPConfiguration     `json:"ldap" xml:"ldap"`
+	OIDC                     OIDCConfiguration     `json:"oidc" xml:"oidc"`
 	Options                  OptionsConfiguration  `json:"options" xml:"options"`
 	IgnoredDevices           []ObservedDevice      `json:"remoteIgnoredDevices" xml:"remoteIgnoredDevice"`
 	DeprecatedPendingDevices []ObservedDevice      `json:"-" xml:"pendingDevice,omitempty"` // Deprecated: Do not use.
@@ -256,6 +257,7 @@ func (cfg Configuration) Copy() Configuration {
 
 	newCfg.Options = cfg.Options.Copy()
 	newCfg.GUI = cfg.GUI.Copy()
+	newCfg.OIDC = cfg.OIDC.Copy()
 
 	// DeviceIDs are values
 	newCfg.IgnoredDevices = make([]ObservedDevice, len(cfg.IgnoredDevices))
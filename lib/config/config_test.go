@@ -54,49 +54,55 @@ func TestDefaultValues(t *testing.T) {
 		Version: CurrentVersion,
 		Folders: []FolderConfiguration{},
 		Options: OptionsConfiguration{
-			RawListenAddresses:        []string{"default"},
-			RawGlobalAnnServers:       []string{"default"},
-			GlobalAnnEnabled:          true,
-			LocalAnnEnabled:           true,
-			LocalAnnPort:              21027,
-			LocalAnnMCAddr:            "[ff12::8384]:21027",
-			MaxSendKbps:               0,
-			MaxRecvKbps:               0,
-			ReconnectIntervalS:        60,
-			RelaysEnabled:             true,
-			RelayReconnectIntervalM:   10,
-			StartBrowser:              true,
-			NATEnabled:                true,
-			NATLeaseM:                 60,
-			NATRenewalM:               30,
-			NATTimeoutS:               10,
-			AutoUpgradeIntervalH:      12,
-			KeepTemporariesH:          24,
-			CacheIgnoredFiles:         false,
-			ProgressUpdateIntervalS:   5,
-			LimitBandwidthInLan:       false,
-			MinHomeDiskFree:           Size{1, "%"},
-			URURL:                     "https://data.syncthing.net/newdata",
-			URInitialDelayS:           1800,
-			URPostInsecurely:          false,
-			ReleasesURL:               "https://upgrades.syncthing.net/meta.json",
-			AlwaysLocalNets:           []string{},
-			OverwriteRemoteDevNames:   false,
-			TempIndexMinBlocks:        10,
-			UnackedNotificationIDs:    []string{"authenticationUserAndPassword"},
-			SetLowPriority:            true,
-			CRURL:                     "https://crash.syncthing.net/newcrash",
-			CREnabled:                 true,
-			StunKeepaliveStartS:       180,
-			StunKeepaliveMinS:         20,
-			RawStunServers:            []string{"default"},
-			AnnounceLANAddresses:      true,
-			FeatureFlags:              []string{},
-			ConnectionPriorityTCPLAN:  10,
-			ConnectionPriorityQUICLAN: 20,
-			ConnectionPriorityTCPWAN:  30,
-			ConnectionPriorityQUICWAN: 40,
-			ConnectionPriorityRelay:   50,
+			RawListenAddresses:          []string{"default"},
+			RawGlobalAnnServers:         []string{"default"},
+			GlobalAnnEnabled:            true,
+			LocalAnnEnabled:             true,
+			LocalAnnPort:                21027,
+			LocalAnnMCAddr:              "[ff12::8384]:21027",
+			MaxSendKbps:                 0,
+			MaxRecvKbps:                 0,
+			ReconnectIntervalS:          60,
+			RelaysEnabled:               true,
+			RelayReconnectIntervalM:     10,
+			StartBrowser:                true,
+			NATEnabled:                  true,
+			NATLeaseM:                   60,
+			NATRenewalM:                 30,
+			NATTimeoutS:                 10,
+			AutoUpgradeIntervalH:        12,
+			KeepTemporariesH:            24,
+			CacheIgnoredFiles:           false,
+			ProgressUpdateIntervalS:     5,
+			LimitBandwidthInLan:         false,
+			MinHomeDiskFree:             Size{1, "%"},
+			URURL:                       "https://data.syncthing.net/newdata",
+			URInitialDelayS:             1800,
+			URPostInsecurely:            false,
+			ReleasesURL:                 "https://upgrades.syncthing.net/meta.json",
+			AlwaysLocalNets:             []string{},
+			OverwriteRemoteDevNames:     false,
+			TempIndexMinBlocks:          10,
+			UnackedNotificationIDs:      []string{"authenticationUserAndPassword"},
+			SetLowPriority:              true,
+			CRURL:                       "https://crash.syncthing.net/newcrash",
+			CREnabled:                   true,
+			StunKeepaliveStartS:         180,
+			StunKeepaliveMinS:           20,
+			RawStunServers:              []string{"default"},
+			AnnounceLANAddresses:        true,
+			FeatureFlags:                []string{},
+			BandwidthSchedule:           BandwidthSchedule{},
+			Webhooks:                    []Webhook{},
+			ConnectionPriorityTCPLAN:    10,
+			ConnectionPriorityQUICLAN:   20,
+			ConnectionPriorityTCPWAN:    30,
+			ConnectionPriorityQUICWAN:   40,
+			ConnectionPriorityRelay:     50,
+			ConnectionPriorityWebSocket: 45,
+			TransferHistoryHours:        168,
+			TransferHistoryDays:         90,
+			TransferHistoryMonths:       24,
 		},
 		Defaults: Defaults{
 			Folder: FolderConfiguration{
@@ -123,12 +129,15 @@ func TestDefaultValues(t *testing.T) {
 					MaxSingleEntrySize: 1024,
 					MaxTotalSize:       4096,
 				},
+				MaxSnapshots:       10,
+				MassChangeMinFiles: 100,
 			},
 			Device: DeviceConfiguration{
-				Addresses:       []string{"dynamic"},
-				AllowedNetworks: []string{},
-				Compression:     CompressionMetadata,
-				IgnoredFolders:  []ObservedFolder{},
+				Addresses:         []string{"dynamic"},
+				AllowedNetworks:   []string{},
+				Compression:       CompressionMetadata,
+				IgnoredFolders:    []ObservedFolder{},
+				BandwidthSchedule: BandwidthSchedule{},
 			},
 			Ignores: Ignores{
 				Lines: []string{},
@@ -143,6 +152,7 @@ func TestDefaultValues(t *testing.T) {
 	cfg := New(device1)
 	cfg.GUI = GUIConfiguration{}
 	cfg.LDAP = LDAPConfiguration{}
+	cfg.OIDC = OIDCConfiguration{}
 
 	if diff, equal := messagediff.PrettyDiff(expected, cfg); !equal {
 		t.Errorf("Default config differs. Diff:\n%s", diff)
@@ -202,20 +212,22 @@ func TestDeviceConfig(t *testing.T) {
 
 		expectedDevices := []DeviceConfiguration{
 			{
-				DeviceID:        device1,
-				Name:            "node one",
-				Addresses:       []string{"tcp://a"},
-				Compression:     CompressionMetadata,
-				AllowedNetworks: []string{},
-				IgnoredFolders:  []ObservedFolder{},
+				DeviceID:          device1,
+				Name:              "node one",
+				Addresses:         []string{"tcp://a"},
+				Compression:       CompressionMetadata,
+				AllowedNetworks:   []string{},
+				IgnoredFolders:    []ObservedFolder{},
+				BandwidthSchedule: BandwidthSchedule{},
 			},
 			{
-				DeviceID:        device4,
-				Name:            "node two",
-				Addresses:       []string{"tcp://b"},
-				Compression:     CompressionMetadata,
-				AllowedNetworks: []string{},
-				IgnoredFolders:  []ObservedFolder{},
+				DeviceID:          device4,
+				Name:              "node two",
+				Addresses:         []string{"tcp://b"},
+				Compression:       CompressionMetadata,
+				AllowedNetworks:   []string{},
+				IgnoredFolders:    []ObservedFolder{},
+				BandwidthSchedule: BandwidthSchedule{},
 			},
 		}
 		expectedDeviceIDs := []protocol.DeviceID{device1, device4}
@@ -251,50 +263,56 @@ func TestNoListenAddresses(t *testing.T) {
 
 func TestOverriddenValues(t *testing.T) {
 	expected := OptionsConfiguration{
-		RawListenAddresses:        []string{"tcp://:23000"},
-		RawGlobalAnnServers:       []string{"udp4://syncthing.nym.se:22026"},
-		GlobalAnnEnabled:          false,
-		LocalAnnEnabled:           false,
-		LocalAnnPort:              42123,
-		LocalAnnMCAddr:            "quux:3232",
-		MaxSendKbps:               1234,
-		MaxRecvKbps:               2341,
-		ReconnectIntervalS:        6000,
-		RelaysEnabled:             false,
-		RelayReconnectIntervalM:   20,
-		StartBrowser:              false,
-		NATEnabled:                false,
-		NATLeaseM:                 90,
-		NATRenewalM:               15,
-		NATTimeoutS:               15,
-		AutoUpgradeIntervalH:      24,
-		KeepTemporariesH:          48,
-		CacheIgnoredFiles:         true,
-		ProgressUpdateIntervalS:   10,
-		LimitBandwidthInLan:       true,
-		MinHomeDiskFree:           Size{5.2, "%"},
-		URSeen:                    8,
-		URAccepted:                4,
-		URURL:                     "https://localhost/newdata",
-		URInitialDelayS:           800,
-		URPostInsecurely:          true,
-		ReleasesURL:               "https://localhost/releases",
-		AlwaysLocalNets:           []string{},
-		OverwriteRemoteDevNames:   true,
-		TempIndexMinBlocks:        100,
-		UnackedNotificationIDs:    []string{"asdfasdf"},
-		SetLowPriority:            false,
-		CRURL:                     "https://localhost/newcrash",
-		CREnabled:                 false,
-		StunKeepaliveStartS:       9000,
-		StunKeepaliveMinS:         900,
-		RawStunServers:            []string{"foo"},
-		FeatureFlags:              []string{"feature"},
-		ConnectionPriorityTCPLAN:  40,
-		ConnectionPriorityQUICLAN: 45,
-		ConnectionPriorityTCPWAN:  50,
-		ConnectionPriorityQUICWAN: 55,
-		ConnectionPriorityRelay:   9000,
+		RawListenAddresses:          []string{"tcp://:23000"},
+		RawGlobalAnnServers:         []string{"udp4://syncthing.nym.se:22026"},
+		GlobalAnnEnabled:            false,
+		LocalAnnEnabled:             false,
+		LocalAnnPort:                42123,
+		LocalAnnMCAddr:              "quux:3232",
+		MaxSendKbps:                 1234,
+		MaxRecvKbps:                 2341,
+		ReconnectIntervalS:          6000,
+		RelaysEnabled:               false,
+		RelayReconnectIntervalM:     20,
+		StartBrowser:                false,
+		NATEnabled:                  false,
+		NATLeaseM:                   90,
+		NATRenewalM:                 15,
+		NATTimeoutS:                 15,
+		AutoUpgradeIntervalH:        24,
+		KeepTemporariesH:            48,
+		CacheIgnoredFiles:           true,
+		ProgressUpdateIntervalS:     10,
+		LimitBandwidthInLan:         true,
+		MinHomeDiskFree:             Size{5.2, "%"},
+		URSeen:                      8,
+		URAccepted:                  4,
+		URURL:                       "https://localhost/newdata",
+		URInitialDelayS:             800,
+		URPostInsecurely:            true,
+		ReleasesURL:                 "https://localhost/releases",
+		AlwaysLocalNets:             []string{},
+		OverwriteRemoteDevNames:     true,
+		TempIndexMinBlocks:          100,
+		UnackedNotificationIDs:      []string{"asdfasdf"},
+		SetLowPriority:              false,
+		CRURL:                       "https://localhost/newcrash",
+		CREnabled:                   false,
+		StunKeepaliveStartS:         9000,
+		StunKeepaliveMinS:           900,
+		RawStunServers:              []string{"foo"},
+		FeatureFlags:                []string{"feature"},
+		ConnectionPriorityTCPLAN:    40,
+		ConnectionPriorityQUICLAN:   45,
+		ConnectionPriorityTCPWAN:    50,
+		ConnectionPriorityQUICWAN:   55,
+		ConnectionPriorityRelay:     9000,
+		ConnectionPriorityWebSocket: 60,
+		TransferHistoryHours:        48,
+		TransferHistoryDays:         30,
+		TransferHistoryMonths:       0,
+		BandwidthSchedule:           BandwidthSchedule{},
+		Webhooks:                    []Webhook{},
 	}
 	expectedPath := "/media/syncthing"
 
@@ -323,30 +341,34 @@ func TestDeviceAddressesDynamic(t *testing.T) {
 	name, _ := os.Hostname()
 	expected := map[protocol.DeviceID]DeviceConfiguration{
 		device1: {
-			DeviceID:        device1,
-			Addresses:       []string{"dynamic"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device1,
+			Addresses:         []string{"dynamic"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device2: {
-			DeviceID:        device2,
-			Addresses:       []string{"dynamic"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device2,
+			Addresses:         []string{"dynamic"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device3: {
-			DeviceID:        device3,
-			Addresses:       []string{"dynamic"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device3,
+			Addresses:         []string{"dynamic"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device4: {
-			DeviceID:        device4,
-			Name:            name, // Set when auto created
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device4,
+			Name:              name, // Set when auto created
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 	}
 
@@ -366,33 +388,37 @@ func TestDeviceCompression(t *testing.T) {
 	name, _ := os.Hostname()
 	expected := map[protocol.DeviceID]DeviceConfiguration{
 		device1: {
-			DeviceID:        device1,
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device1,
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device2: {
-			DeviceID:        device2,
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device2,
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device3: {
-			DeviceID:        device3,
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionNever,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device3,
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionNever,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device4: {
-			DeviceID:        device4,
-			Name:            name, // Set when auto created
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device4,
+			Name:              name, // Set when auto created
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 	}
 
@@ -412,30 +438,34 @@ func TestDeviceAddressesStatic(t *testing.T) {
 	name, _ := os.Hostname()
 	expected := map[protocol.DeviceID]DeviceConfiguration{
 		device1: {
-			DeviceID:        device1,
-			Addresses:       []string{"tcp://192.0.2.1", "tcp://192.0.2.2"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device1,
+			Addresses:         []string{"tcp://192.0.2.1", "tcp://192.0.2.2"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device2: {
-			DeviceID:        device2,
-			Addresses:       []string{"tcp://192.0.2.3:6070", "tcp://[2001:db8::42]:4242"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device2,
+			Addresses:         []string{"tcp://192.0.2.3:6070", "tcp://[2001:db8::42]:4242"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device3: {
-			DeviceID:        device3,
-			Addresses:       []string{"tcp://[2001:db8::44]:4444", "tcp://192.0.2.4:6090"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device3,
+			Addresses:         []string{"tcp://[2001:db8::44]:4444", "tcp://192.0.2.4:6090"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 		device4: {
-			DeviceID:        device4,
-			Name:            name, // Set when auto created
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device4,
+			Name:              name, // Set when auto created
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			BandwidthSchedule: BandwidthSchedule{},
 		},
 	}
 
@@ -0,0 +1,41 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+type DatabaseBackend int32
+
+const (
+	DatabaseBackendLevelDB DatabaseBackend = 0
+	DatabaseBackendSQLite  DatabaseBackend = 1
+)
+
+func (t DatabaseBackend) String() string {
+	switch t {
+	case DatabaseBackendLevelDB:
+		return "leveldb"
+	case DatabaseBackendSQLite:
+		return "sqlite"
+	default:
+		return "unknown"
+	}
+}
+
+func (t DatabaseBackend) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *DatabaseBackend) UnmarshalText(bs []byte) error {
+	switch string(bs) {
+	case "leveldb":
+		*t = DatabaseBackendLevelDB
+	case "sqlite":
+		*t = DatabaseBackendSQLite
+	default:
+		*t = DatabaseBackendLevelDB
+	}
+	return nil
+}
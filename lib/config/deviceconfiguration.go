@@ -35,6 +35,7 @@ type DeviceConfiguration struct {
 	Untrusted                bool              `json:"untrusted" xml:"untrusted"`
 	RemoteGUIPort            int               `json:"remoteGUIPort" xml:"remoteGUIPort"`
 	RawNumConnections        int               `json:"numConnections" xml:"numConnections"`
+	BandwidthSchedule        BandwidthSchedule `json:"bandwidthSchedule" xml:"bandwidthWindow"`
 }
 
 func (cfg DeviceConfiguration) Copy() DeviceConfiguration {
@@ -45,6 +46,7 @@ func (cfg DeviceConfiguration) Copy() DeviceConfiguration {
 	copy(c.AllowedNetworks, cfg.AllowedNetworks)
 	c.IgnoredFolders = make([]ObservedFolder, len(cfg.IgnoredFolders))
 	copy(c.IgnoredFolders, cfg.IgnoredFolders)
+	c.BandwidthSchedule = cfg.BandwidthSchedule.Copy()
 	return c
 }
 
@@ -60,6 +62,7 @@ func (cfg *DeviceConfiguration) prepare(sharedFolders []string) {
 	}
 
 	cfg.IgnoredFolders = sortedObservedFolderSlice(ignoredFolders)
+	cfg.BandwidthSchedule = cfg.BandwidthSchedule.prepare("device " + cfg.DeviceID.Short().String())
 
 	// A device cannot be simultaneously untrusted and an introducer, nor
 	// auto accept folders.
@@ -13,6 +13,7 @@ type FilesystemType int32
 const (
 	FilesystemTypeBasic FilesystemType = 0
 	FilesystemTypeFake  FilesystemType = 1
+	FilesystemTypeS3    FilesystemType = 2
 )
 
 func (t FilesystemType) String() string {
@@ -21,6 +22,8 @@ func (t FilesystemType) String() string {
 		return "basic"
 	case FilesystemTypeFake:
 		return "fake"
+	case FilesystemTypeS3:
+		return "s3"
 	default:
 		return "unknown"
 	}
@@ -32,6 +35,8 @@ func (t FilesystemType) ToFS() fs.FilesystemType {
 		return fs.FilesystemTypeBasic
 	case FilesystemTypeFake:
 		return fs.FilesystemTypeFake
+	case FilesystemTypeS3:
+		return fs.FilesystemTypeS3
 	default:
 		return fs.FilesystemTypeBasic
 	}
@@ -47,6 +52,8 @@ func (t *FilesystemType) UnmarshalText(bs []byte) error {
 		*t = FilesystemTypeBasic
 	case "fake":
 		*t = FilesystemTypeFake
+	case "s3":
+		*t = FilesystemTypeS3
 	default:
 		*t = FilesystemTypeBasic
 	}
@@ -85,6 +85,19 @@ type FolderConfiguration struct {
 	SyncXattrs              bool                        `json:"syncXattrs" xml:"syncXattrs"`
 	SendXattrs              bool                        `json:"sendXattrs" xml:"sendXattrs"`
 	XattrFilter             XattrFilter                 `json:"xattrFilter" xml:"xattrFilter"`
+	FileHistory             bool                        `json:"fileHistory" xml:"fileHistory"`
+	SnapshotIntervalS       int                         `json:"snapshotIntervalS" xml:"snapshotIntervalS"`
+	MaxSnapshots            int                         `json:"maxSnapshots" xml:"maxSnapshots" default:"10"`
+	MassChangeThresholdPct  int                         `json:"massChangeThresholdPct" xml:"massChangeThresholdPct"`
+	MassChangeMinFiles      int                         `json:"massChangeMinFiles" xml:"massChangeMinFiles" default:"100"`
+	ContentDefinedChunking  bool                        `json:"contentDefinedChunking" xml:"contentDefinedChunking"`
+	SelectiveSync           bool                        `json:"selectiveSync" xml:"selectiveSync"`
+	MaxSendKbps             int                         `json:"maxSendKbps" xml:"maxSendKbps"`
+	MaxRecvKbps             int                         `json:"maxRecvKbps" xml:"maxRecvKbps"`
+	// Block transfers of folders with a higher priority go first, those of
+	// lower priority folders with the same device keep a quarter of the
+	// transfers in progress.
+	Priority int `json:"priority" xml:"priority"`
 	// Legacy deprecated
 	DeprecatedReadOnly       bool    `json:"-" xml:"ro,attr,omitempty"`        // Deprecated: Do not use.
 	DeprecatedMinDiskFreePct float64 `json:"-" xml:"minDiskFreePct,omitempty"` // Deprecated: Do not use.
@@ -9,7 +9,9 @@ package config
 import (
 	"net/url"
 	"os"
+	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -33,11 +35,25 @@ type GUIConfiguration struct {
 	InsecureSkipHostCheck     bool     `json:"insecureSkipHostcheck" xml:"insecureSkipHostcheck,omitempty"`
 	InsecureAllowFrameLoading bool     `json:"insecureAllowFrameLoading" xml:"insecureAllowFrameLoading,omitempty"`
 	SendBasicAuthPrompt       bool     `json:"sendBasicAuthPrompt" xml:"sendBasicAuthPrompt,attr"`
+	APIKeys                   []APIKey `json:"apiKeys" xml:"scopedApiKey"`
+	// Users next to the administrator set by User and Password.
+	Users []GUIUser `json:"users" xml:"guiUser"`
 }
 
 func (c GUIConfiguration) IsAuthEnabled() bool {
 	// This function should match isAuthEnabled() in syncthingController.js
-	return c.AuthMode == AuthModeLDAP || (len(c.User) > 0 && len(c.Password) > 0)
+	return c.AuthMode == AuthModeLDAP || c.AuthMode == AuthModeOIDC || (len(c.User) > 0 && len(c.Password) > 0) || len(c.Users) > 0
+}
+
+// GUIUser returns the additional GUI user with the given name, if there is
+// one.
+func (c GUIConfiguration) GUIUser(name string) (GUIUser, bool) {
+	for _, user := range c.Users {
+		if user.Name == name {
+			return user, true
+		}
+	}
+	return GUIUser{}, false
 }
 
 func (GUIConfiguration) IsOverridden() bool {
@@ -158,7 +174,7 @@ func (c GUIConfiguration) CompareHashedPassword(password string) error {
 }
 
 // IsValidAPIKey returns true when the given API key is valid, including both
-// the value in config and any overrides
+// the values in config and any overrides
 func (c GUIConfiguration) IsValidAPIKey(apiKey string) bool {
 	switch apiKey {
 	case "":
@@ -168,16 +184,61 @@ func (c GUIConfiguration) IsValidAPIKey(apiKey string) bool {
 		return true
 
 	default:
-		return false
+		_, ok := c.ScopedAPIKey(apiKey)
+		return ok
+	}
+}
+
+// ScopedAPIKey returns the additional API key with the given value, if
+// there is one. The main API key isn't scoped and is never returned.
+func (c GUIConfiguration) ScopedAPIKey(apiKey string) (APIKey, bool) {
+	if apiKey == "" {
+		return APIKey{}, false
+	}
+	for _, key := range c.APIKeys {
+		if key.Key == apiKey {
+			return key, true
+		}
 	}
+	return APIKey{}, false
 }
 
 func (c *GUIConfiguration) prepare() {
 	if c.APIKey == "" {
 		c.APIKey = rand.String(32)
 	}
+	for i := range c.APIKeys {
+		c.APIKeys[i].prepare()
+	}
+	for i := range c.Users {
+		c.Users[i].prepare()
+	}
+}
+
+// Equal returns whether the configurations are the same, not telling apart
+// nil and empty lists of API keys or users.
+func (c GUIConfiguration) Equal(other GUIConfiguration) bool {
+	if !slices.EqualFunc(c.APIKeys, other.APIKeys, func(a, b APIKey) bool {
+		return a.Name == b.Name && a.Key == b.Key && slices.Equal(a.Permissions, b.Permissions) && slices.Equal(a.Folders, b.Folders)
+	}) {
+		return false
+	}
+	if !slices.Equal(c.Users, other.Users) {
+		return false
+	}
+	c.APIKeys, other.APIKeys = nil, nil
+	c.Users, other.Users = nil, nil
+	return reflect.DeepEqual(c, other)
 }
 
 func (c GUIConfiguration) Copy() GUIConfiguration {
+	if c.APIKeys != nil {
+		keys := make([]APIKey, len(c.APIKeys))
+		for i, key := range c.APIKeys {
+			keys[i] = key.Copy()
+		}
+		c.APIKeys = keys
+	}
+	c.Users = slices.Clone(c.Users)
 	return c
 }
@@ -0,0 +1,117 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// A GUIRole determines what a user logged in to the GUI may do.
+type GUIRole string
+
+const (
+	// Everything.
+	GUIRoleAdmin GUIRole = "admin"
+	// Looking at everything, and the folder operations like scanning,
+	// overriding and reverting, but not changing the configuration.
+	GUIRoleOperator GUIRole = "operator"
+	// Only looking. Secrets like the API key are hidden.
+	GUIRoleViewer GUIRole = "viewer"
+)
+
+// Allows returns whether the role grants the given permission.
+func (r GUIRole) Allows(perm APIPermission) bool {
+	switch r {
+	case GUIRoleAdmin:
+		return true
+	case GUIRoleOperator:
+		return perm == APIPermissionRead || perm == APIPermissionFolder
+	case GUIRoleViewer:
+		return perm == APIPermissionRead
+	default:
+		return false
+	}
+}
+
+func (r GUIRole) rank() int {
+	switch r {
+	case GUIRoleAdmin:
+		return 3
+	case GUIRoleOperator:
+		return 2
+	case GUIRoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// A GUIUser is an additional user of the GUI, next to the administrator
+// set by User and Password in the GUI configuration.
+type GUIUser struct {
+	Name string `json:"name" xml:"name,attr"`
+	// A bcrypt hash. Plaintext passwords are hashed when the
+	// configuration is loaded.
+	Password string  `json:"password" xml:"password"`
+	Role     GUIRole `json:"role" xml:"role,attr"`
+}
+
+// CompareHashedPassword returns nil when the given plaintext password
+// matches the stored hash.
+func (u GUIUser) CompareHashedPassword(password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
+}
+
+func (u *GUIUser) prepare() {
+	if u.Password != "" && !bcryptExpr.MatchString(u.Password) {
+		hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+		if err != nil {
+			l.Warnf("Hashing password of GUI user %q: %v", u.Name, err)
+			u.Password = ""
+		} else {
+			u.Password = string(hash)
+		}
+	}
+	if u.Role.rank() == 0 {
+		if u.Role != "" {
+			l.Warnf("Unknown role %q of GUI user %q, using %q", u.Role, u.Name, GUIRoleViewer)
+		}
+		u.Role = GUIRoleViewer
+	}
+}
+
+// A GroupRole maps a group of an external identity provider, like LDAP or
+// OpenID Connect, to the role its members get.
+type GroupRole struct {
+	Group string  `json:"group" xml:"group,attr"`
+	Role  GUIRole `json:"role" xml:"role,attr"`
+}
+
+// RoleForGroups returns the highest role mapped to any of the given
+// groups. Without any mappings everyone is an administrator, as before
+// roles existed, while with mappings users in none of the groups get no
+// role at all.
+func RoleForGroups(mappings []GroupRole, groups []string) (GUIRole, bool) {
+	if len(mappings) == 0 {
+		return GUIRoleAdmin, true
+	}
+	var role GUIRole
+	for _, m := range mappings {
+		if m.Role.rank() <= role.rank() {
+			continue
+		}
+		for _, group := range groups {
+			if strings.EqualFold(group, m.Group) {
+				role = m.Role
+				break
+			}
+		}
+	}
+	return role, role != ""
+}
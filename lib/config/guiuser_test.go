@@ -0,0 +1,58 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import "testing"
+
+func TestRoleForGroups(t *testing.T) {
+	t.Parallel()
+
+	mappings := []GroupRole{
+		{Group: "cn=viewers,dc=example,dc=com", Role: GUIRoleViewer},
+		{Group: "cn=admins,dc=example,dc=com", Role: GUIRoleAdmin},
+		{Group: "cn=operators,dc=example,dc=com", Role: GUIRoleOperator},
+	}
+	cases := []struct {
+		mappings []GroupRole
+		groups   []string
+		role     GUIRole
+		ok       bool
+	}{
+		{nil, nil, GUIRoleAdmin, true},
+		{mappings, nil, "", false},
+		{mappings, []string{"cn=others,dc=example,dc=com"}, "", false},
+		{mappings, []string{"CN=Viewers,DC=example,DC=com"}, GUIRoleViewer, true},
+		{mappings, []string{"cn=viewers,dc=example,dc=com", "cn=operators,dc=example,dc=com"}, GUIRoleOperator, true},
+		{mappings, []string{"cn=operators,dc=example,dc=com", "cn=admins,dc=example,dc=com"}, GUIRoleAdmin, true},
+		{[]GroupRole{{Group: "group", Role: "superuser"}}, []string{"group"}, "", false},
+	}
+	for _, tc := range cases {
+		role, ok := RoleForGroups(tc.mappings, tc.groups)
+		if role != tc.role || ok != tc.ok {
+			t.Errorf("RoleForGroups(%v, %v) = %q, %v; expected %q, %v", tc.mappings, tc.groups, role, ok, tc.role, tc.ok)
+		}
+	}
+}
+
+func TestGUIUserPrepare(t *testing.T) {
+	t.Parallel()
+
+	user := GUIUser{Name: "user", Password: "secret", Role: "superuser"}
+	user.prepare()
+	if user.Role != GUIRoleViewer {
+		t.Error("expected unknown role to become viewer, got", user.Role)
+	}
+	if user.Password == "secret" || user.CompareHashedPassword("secret") != nil {
+		t.Error("expected the password to be hashed")
+	}
+
+	hash := user.Password
+	user.prepare()
+	if user.Password != hash {
+		t.Error("expected the hash to be kept")
+	}
+}
@@ -6,6 +6,8 @@
 
 package config
 
+import "slices"
+
 type LDAPConfiguration struct {
 	Address            string        `json:"address" xml:"address,omitempty"`
 	BindDN             string        `json:"bindDN" xml:"bindDN,omitempty"`
@@ -13,8 +15,12 @@ type LDAPConfiguration struct {
 	InsecureSkipVerify bool          `json:"insecureSkipVerify" xml:"insecureSkipVerify,omitempty" default:"false"`
 	SearchBaseDN       string        `json:"searchBaseDN" xml:"searchBaseDN,omitempty"`
 	SearchFilter       string        `json:"searchFilter" xml:"searchFilter,omitempty"`
+	// Maps the groups in the memberOf attribute of the user found by the
+	// search to roles. Without any mappings, every user is an admin.
+	GroupRoles []GroupRole `json:"groupRoles" xml:"groupRole"`
 }
 
 func (c LDAPConfiguration) Copy() LDAPConfiguration {
+	c.GroupRoles = slices.Clone(c.GroupRoles)
 	return c
 }
@@ -203,6 +203,16 @@ type Wrapper struct {
 	myIDReturnsOnCall map[int]struct {
 		result1 protocol.DeviceID
 	}
+	OIDCStub        func() config.OIDCConfiguration
+	oIDCMutex       sync.RWMutex
+	oIDCArgsForCall []struct {
+	}
+	oIDCReturns struct {
+		result1 config.OIDCConfiguration
+	}
+	oIDCReturnsOnCall map[int]struct {
+		result1 config.OIDCConfiguration
+	}
 	OptionsStub        func() config.OptionsConfiguration
 	optionsMutex       sync.RWMutex
 	optionsArgsForCall []struct {
@@ -1312,6 +1322,59 @@ func (fake *Wrapper) MyIDReturnsOnCall(i int, result1 protocol.DeviceID) {
 	}{result1}
 }
 
+func (fake *Wrapper) OIDC() config.OIDCConfiguration {
+	fake.oIDCMutex.Lock()
+	ret, specificReturn := fake.oIDCReturnsOnCall[len(fake.oIDCArgsForCall)]
+	fake.oIDCArgsForCall = append(fake.oIDCArgsForCall, struct {
+	}{})
+	stub := fake.OIDCStub
+	fakeReturns := fake.oIDCReturns
+	fake.recordInvocation("OIDC", []interface{}{})
+	fake.oIDCMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Wrapper) OIDCCallCount() int {
+	fake.oIDCMutex.RLock()
+	defer fake.oIDCMutex.RUnlock()
+	return len(fake.oIDCArgsForCall)
+}
+
+func (fake *Wrapper) OIDCCalls(stub func() config.OIDCConfiguration) {
+	fake.oIDCMutex.Lock()
+	defer fake.oIDCMutex.Unlock()
+	fake.OIDCStub = stub
+}
+
+func (fake *Wrapper) OIDCReturns(result1 config.OIDCConfiguration) {
+	fake.oIDCMutex.Lock()
+	defer fake.oIDCMutex.Unlock()
+	fake.OIDCStub = nil
+	fake.oIDCReturns = struct {
+		result1 config.OIDCConfiguration
+	}{result1}
+}
+
+func (fake *Wrapper) OIDCReturnsOnCall(i int, result1 config.OIDCConfiguration) {
+	fake.oIDCMutex.Lock()
+	defer fake.oIDCMutex.Unlock()
+	fake.OIDCStub = nil
+	if fake.oIDCReturnsOnCall == nil {
+		fake.oIDCReturnsOnCall = make(map[int]struct {
+			result1 config.OIDCConfiguration
+		})
+	}
+	fake.oIDCReturnsOnCall[i] = struct {
+		result1 config.OIDCConfiguration
+	}{result1}
+}
+
 func (fake *Wrapper) Options() config.OptionsConfiguration {
 	fake.optionsMutex.Lock()
 	ret, specificReturn := fake.optionsReturnsOnCall[len(fake.optionsArgsForCall)]
@@ -1845,6 +1908,8 @@ func (fake *Wrapper) Invocations() map[string][][]interface{} {
 	defer fake.modifyMutex.RUnlock()
 	fake.myIDMutex.RLock()
 	defer fake.myIDMutex.RUnlock()
+	fake.oIDCMutex.RLock()
+	defer fake.oIDCMutex.RUnlock()
 	fake.optionsMutex.RLock()
 	defer fake.optionsMutex.RUnlock()
 	fake.rawCopyMutex.RLock()
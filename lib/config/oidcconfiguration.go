@@ -0,0 +1,70 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import "slices"
+
+// OIDCConfiguration describes the OpenID Connect identity provider used
+// for the GUI when the authentication mode is "oidc".
+type OIDCConfiguration struct {
+	Issuer       string `json:"issuer" xml:"issuer,omitempty"`
+	ClientID     string `json:"clientID" xml:"clientID,omitempty"`
+	ClientSecret string `json:"clientSecret" xml:"clientSecret,omitempty"`
+	// The URL the identity provider redirects back to. If empty, it's
+	// derived from the address the GUI was accessed on.
+	RedirectURL string   `json:"redirectURL" xml:"redirectURL,omitempty"`
+	Scopes      []string `json:"scopes" xml:"scope"`
+	// The ID token claim holding the user name.
+	UsernameClaim string `json:"usernameClaim" xml:"usernameClaim,omitempty" default:"preferred_username"`
+	// The ID token claim holding the groups of the user, and the groups
+	// that may log in. Everyone may log in if no groups are given.
+	GroupsClaim   string   `json:"groupsClaim" xml:"groupsClaim,omitempty" default:"groups"`
+	AllowedGroups []string `json:"allowedGroups" xml:"allowedGroup"`
+	// Maps the groups of the user to roles. Without any mappings, every
+	// user is an admin.
+	GroupRoles []GroupRole `json:"groupRoles" xml:"groupRole"`
+}
+
+func (c OIDCConfiguration) Copy() OIDCConfiguration {
+	c.Scopes = slices.Clone(c.Scopes)
+	c.AllowedGroups = slices.Clone(c.AllowedGroups)
+	c.GroupRoles = slices.Clone(c.GroupRoles)
+	return c
+}
+
+// AllScopes returns the scopes to request, which always include "openid".
+func (c OIDCConfiguration) AllScopes() []string {
+	if len(c.Scopes) == 0 {
+		return []string{"openid", "profile", "email"}
+	}
+	if slices.Contains(c.Scopes, "openid") {
+		return c.Scopes
+	}
+	return append([]string{"openid"}, c.Scopes...)
+}
+
+// AllowsGroups returns whether a user in the given groups may log in.
+func (c OIDCConfiguration) AllowsGroups(groups []string) bool {
+	if len(c.AllowedGroups) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(groups, func(group string) bool {
+		return slices.Contains(c.AllowedGroups, group)
+	})
+}
+
+func (c OIDCConfiguration) Equal(other OIDCConfiguration) bool {
+	return slices.Equal(c.Scopes, other.Scopes) &&
+		slices.Equal(c.AllowedGroups, other.AllowedGroups) &&
+		slices.Equal(c.GroupRoles, other.GroupRoles) &&
+		c.Issuer == other.Issuer &&
+		c.ClientID == other.ClientID &&
+		c.ClientSecret == other.ClientSecret &&
+		c.RedirectURL == other.RedirectURL &&
+		c.UsernameClaim == other.UsernameClaim &&
+		c.GroupsClaim == other.GroupsClaim
+}
@@ -61,11 +61,15 @@ type OptionsConfiguration struct {
 	StunKeepaliveStartS         int      `json:"stunKeepaliveStartS" xml:"stunKeepaliveStartS" default:"180"`
 	StunKeepaliveMinS           int      `json:"stunKeepaliveMinS" xml:"stunKeepaliveMinS" default:"20"`
 	RawStunServers              []string `json:"stunServers" xml:"stunServer" default:"default"`
-	DatabaseTuning              Tuning   `json:"databaseTuning" xml:"databaseTuning" restart:"true"`
-	RawMaxCIRequestKiB          int      `json:"maxConcurrentIncomingRequestKiB" xml:"maxConcurrentIncomingRequestKiB"`
-	AnnounceLANAddresses        bool     `json:"announceLANAddresses" xml:"announceLANAddresses" default:"true"`
-	SendFullIndexOnUpgrade      bool     `json:"sendFullIndexOnUpgrade" xml:"sendFullIndexOnUpgrade"`
-	FeatureFlags                []string `json:"featureFlags" xml:"featureFlag"`
+	// Has no effect on the SQLite database.
+	DatabaseTuning         Tuning   `json:"databaseTuning" xml:"databaseTuning" restart:"true"`
+	RawMaxCIRequestKiB     int      `json:"maxConcurrentIncomingRequestKiB" xml:"maxConcurrentIncomingRequestKiB"`
+	AnnounceLANAddresses   bool     `json:"announceLANAddresses" xml:"announceLANAddresses" default:"true"`
+	SendFullIndexOnUpgrade bool     `json:"sendFullIndexOnUpgrade" xml:"sendFullIndexOnUpgrade"`
+	FeatureFlags           []string `json:"featureFlags" xml:"featureFlag"`
+	// The database implementation to use. An existing LevelDB database is
+	// migrated when switching to SQLite.
+	DatabaseBackend DatabaseBackend `json:"databaseBackend" xml:"databaseBackend" restart:"true"`
 	// The number of connections at which we stop trying to connect to more
 	// devices, zero meaning no limit. Does not affect incoming connections.
 	ConnectionLimitEnough int `json:"connectionLimitEnough" xml:"connectionLimitEnough"`
@@ -73,6 +77,15 @@ type OptionsConfiguration struct {
 	// meaning no limit. Affects incoming connections and prevents
 	// attempting outgoing connections.
 	ConnectionLimitMax int `json:"connectionLimitMax" xml:"connectionLimitMax"`
+	// The maximum number of devices whose block requests are served at the
+	// same time, zero meaning no limit. Other devices then fetch blocks from
+	// those that already have them; blocks nobody else has are always served.
+	MaxUploadFanOut int `json:"maxUploadFanOut" xml:"maxUploadFanOut"`
+	// The number of hours, days and months of transfer history to keep per
+	// device and folder, zero meaning forever.
+	TransferHistoryHours  int `json:"transferHistoryHours" xml:"transferHistoryHours" default:"168"`
+	TransferHistoryDays   int `json:"transferHistoryDays" xml:"transferHistoryDays" default:"90"`
+	TransferHistoryMonths int `json:"transferHistoryMonths" xml:"transferHistoryMonths" default:"24"`
 	// When set, this allows TLS 1.2 on sync connections, where we otherwise
 	// default to TLS 1.3+ only.
 	InsecureAllowOldTLSVersions        bool `json:"insecureAllowOldTLSVersions" xml:"insecureAllowOldTLSVersions"`
@@ -81,7 +94,13 @@ type OptionsConfiguration struct {
 	ConnectionPriorityTCPWAN           int  `json:"connectionPriorityTcpWan" xml:"connectionPriorityTcpWan" default:"30"`
 	ConnectionPriorityQUICWAN          int  `json:"connectionPriorityQuicWan" xml:"connectionPriorityQuicWan" default:"40"`
 	ConnectionPriorityRelay            int  `json:"connectionPriorityRelay" xml:"connectionPriorityRelay" default:"50"`
+	ConnectionPriorityWebSocket        int  `json:"connectionPriorityWebSocket" xml:"connectionPriorityWebSocket" default:"45"`
 	ConnectionPriorityUpgradeThreshold int  `json:"connectionPriorityUpgradeThreshold" xml:"connectionPriorityUpgradeThreshold" default:"0"`
+	// Time windows during which other rate limits than MaxSendKbps and
+	// MaxRecvKbps apply.
+	BandwidthSchedule BandwidthSchedule `json:"bandwidthSchedule" xml:"bandwidthWindow"`
+	// HTTP endpoints receiving the events as they happen.
+	Webhooks []Webhook `json:"webhooks" xml:"webhook"`
 	// Legacy deprecated
 	DeprecatedUPnPEnabled        bool     `json:"-" xml:"upnpEnabled,omitempty"`        // Deprecated: Do not use.
 	DeprecatedUPnPLeaseM         int      `json:"-" xml:"upnpLeaseMinutes,omitempty"`   // Deprecated: Do not use.
@@ -102,6 +121,13 @@ func (opts OptionsConfiguration) Copy() OptionsConfiguration {
 	copy(optsCopy.AlwaysLocalNets, opts.AlwaysLocalNets)
 	optsCopy.UnackedNotificationIDs = make([]string, len(opts.UnackedNotificationIDs))
 	copy(optsCopy.UnackedNotificationIDs, opts.UnackedNotificationIDs)
+	optsCopy.BandwidthSchedule = opts.BandwidthSchedule.Copy()
+	if opts.Webhooks != nil {
+		optsCopy.Webhooks = make([]Webhook, len(opts.Webhooks))
+		for i, w := range opts.Webhooks {
+			optsCopy.Webhooks[i] = w.Copy()
+		}
+	}
 	return optsCopy
 }
 
@@ -110,6 +136,8 @@ func (opts *OptionsConfiguration) prepare(guiPWIsSet bool) {
 
 	opts.RawListenAddresses = stringutil.UniqueTrimmedStrings(opts.RawListenAddresses)
 	opts.RawGlobalAnnServers = stringutil.UniqueTrimmedStrings(opts.RawGlobalAnnServers)
+	opts.BandwidthSchedule = opts.BandwidthSchedule.prepare("options")
+	opts.Webhooks = prepareWebhooks(opts.Webhooks)
 
 	// Very short reconnection intervals are annoying
 	if opts.ReconnectIntervalS < 5 {
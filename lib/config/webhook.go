@@ -0,0 +1,104 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// The events sent to webhooks that don't list any, which are all but the
+// very noisy ones and the configuration, which holds secrets.
+const DefaultWebhookEvents = events.AllEvents &^ events.LocalChangeDetected &^ events.RemoteChangeDetected &^ events.ConfigSaved
+
+// A Webhook receives the events matching its filters as HTTP POST requests
+// to its URL.
+type Webhook struct {
+	URL string `json:"url" xml:"url,attr"`
+	// Used to sign the requests with HMAC-SHA256. The signature is sent in
+	// the X-Syncthing-Signature header as "sha256=<hex>".
+	Secret string `json:"secret" xml:"secret,omitempty"`
+	// The names of the event types to send, like "ItemFinished". None
+	// means DefaultWebhookEvents.
+	Events []string `json:"events" xml:"event"`
+	// If given, only the events about these folders or devices are sent.
+	// Events that aren't about any folder or device pass these filters.
+	Folders []string            `json:"folders" xml:"folder"`
+	Devices []protocol.DeviceID `json:"devices" xml:"device"`
+}
+
+// EventMask returns the event types to send to the webhook.
+func (w Webhook) EventMask() events.EventType {
+	if len(w.Events) == 0 {
+		return DefaultWebhookEvents
+	}
+	var mask events.EventType
+	for _, name := range w.Events {
+		mask |= events.UnmarshalEventType(name)
+	}
+	return mask
+}
+
+// Matches returns whether an event about the given folder and device, any
+// of which may be empty, passes the filters of the webhook.
+func (w Webhook) Matches(folder string, device protocol.DeviceID) bool {
+	if folder != "" && len(w.Folders) > 0 && !slices.Contains(w.Folders, folder) {
+		return false
+	}
+	if device != protocol.EmptyDeviceID && len(w.Devices) > 0 && !slices.Contains(w.Devices, device) {
+		return false
+	}
+	return true
+}
+
+func (w Webhook) Copy() Webhook {
+	w.Events = slices.Clone(w.Events)
+	w.Folders = slices.Clone(w.Folders)
+	w.Devices = slices.Clone(w.Devices)
+	return w
+}
+
+func (w Webhook) validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("not an HTTP(S) URL: %q", w.URL)
+	}
+	for _, name := range w.Events {
+		if events.UnmarshalEventType(name) == 0 {
+			return fmt.Errorf("unknown event type %q", name)
+		}
+	}
+	return nil
+}
+
+func prepareWebhooks(hooks []Webhook) []Webhook {
+	valid := hooks[:0]
+	for _, w := range hooks {
+		if err := w.validate(); err != nil {
+			l.Warnf("Ignoring webhook %s: %v", w.URL, err)
+			continue
+		}
+		if w.Events == nil {
+			w.Events = []string{}
+		}
+		if w.Folders == nil {
+			w.Folders = []string{}
+		}
+		if w.Devices == nil {
+			w.Devices = []protocol.DeviceID{}
+		}
+		valid = append(valid, w)
+	}
+	return valid
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestPrepareWebhooks(t *testing.T) {
+	hooks := prepareWebhooks([]Webhook{
+		{URL: "https://example.com/hook", Events: []string{"ItemFinished"}},
+		{URL: "ftp://example.com/hook"},
+		{URL: "http:///hook"},
+		{URL: "http://example.com/hook", Events: []string{"NoSuchEvent"}},
+	})
+	if len(hooks) != 1 || hooks[0].URL != "https://example.com/hook" {
+		t.Fatal("expected only the valid webhook to remain, got", hooks)
+	}
+	if hooks[0].Folders == nil || hooks[0].Devices == nil {
+		t.Error("expected empty filters rather than nil")
+	}
+}
+
+func TestWebhookFilters(t *testing.T) {
+	if mask := (Webhook{}).EventMask(); mask != DefaultWebhookEvents || mask&events.ConfigSaved != 0 {
+		t.Error("unexpected default mask", mask)
+	}
+	if mask := (Webhook{Events: []string{"ItemStarted", "ItemFinished"}}).EventMask(); mask != events.ItemStarted|events.ItemFinished {
+		t.Error("unexpected mask", mask)
+	}
+
+	device1 := protocol.DeviceID{1}
+	device2 := protocol.DeviceID{2}
+	w := Webhook{Folders: []string{"a"}, Devices: []protocol.DeviceID{device1}}
+	cases := []struct {
+		folder  string
+		device  protocol.DeviceID
+		matches bool
+	}{
+		{"", protocol.EmptyDeviceID, true},
+		{"a", protocol.EmptyDeviceID, true},
+		{"b", protocol.EmptyDeviceID, false},
+		{"", device1, true},
+		{"", device2, false},
+		{"a", device1, true},
+		{"a", device2, false},
+	}
+	for _, tc := range cases {
+		if w.Matches(tc.folder, tc.device) != tc.matches {
+			t.Errorf("Matches(%q, %v) should be %v", tc.folder, tc.device.Short(), tc.matches)
+		}
+	}
+	if !(Webhook{}).Matches("b", device2) {
+		t.Error("expected a webhook without filters to match everything")
+	}
+}
@@ -101,6 +101,7 @@ type Wrapper interface {
 
 	GUI() GUIConfiguration
 	LDAP() LDAPConfiguration
+	OIDC() OIDCConfiguration
 	Options() OptionsConfiguration
 	DefaultIgnores() Ignores
 
@@ -434,6 +435,12 @@ func (w *wrapper) LDAP() LDAPConfiguration {
 	return w.cfg.LDAP.Copy()
 }
 
+func (w *wrapper) OIDC() OIDCConfiguration {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	return w.cfg.OIDC.Copy()
+}
+
 // GUI returns the current GUI configuration object.
 func (w *wrapper) GUI() GUIConfiguration {
 	w.mut.Lock()
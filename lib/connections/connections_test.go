@@ -15,6 +15,7 @@ import (
 	"io"
 	"math/rand"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 	"testing"
@@ -154,12 +155,13 @@ func TestGetDialer(t *testing.T) {
 		disabled   bool
 		deprecated bool
 	}{
-		{mustParseURI("tcp://1.2.3.4:5678"), true, false, false},   // ok
-		{mustParseURI("tcp4://1.2.3.4:5678"), true, false, false},  // ok
-		{mustParseURI("kcp://1.2.3.4:5678"), false, false, true},   // deprecated
-		{mustParseURI("relay://1.2.3.4:5678"), false, true, false}, // disabled
-		{mustParseURI("http://1.2.3.4:5678"), false, false, false}, // generally bad
-		{mustParseURI("bananas!"), false, false, false},            // wat
+		{mustParseURI("tcp://1.2.3.4:5678"), true, false, false},     // ok
+		{mustParseURI("tcp4://1.2.3.4:5678"), true, false, false},    // ok
+		{mustParseURI("kcp://1.2.3.4:5678"), false, false, true},     // deprecated
+		{mustParseURI("relay://1.2.3.4:5678"), false, true, false},   // disabled
+		{mustParseURI("wss://1.2.3.4:5678/bep"), true, false, false}, // ok
+		{mustParseURI("http://1.2.3.4:5678"), false, false, false},   // generally bad
+		{mustParseURI("bananas!"), false, false, false},              // wat
 	}
 
 	cfg := config.New(protocol.LocalDeviceID)
@@ -374,6 +376,8 @@ func TestConnectionEstablishment(t *testing.T) {
 	addrs := []string{
 		"tcp://127.0.0.1:0",
 		"quic://127.0.0.1:0",
+		"ws://127.0.0.1:0",
+		"wss://127.0.0.1:0",
 	}
 
 	send := make([]byte, 128<<10)
@@ -404,6 +408,28 @@ func TestConnectionEstablishment(t *testing.T) {
 	}
 }
 
+func TestWSRemoteAddr(t *testing.T) {
+	cases := []struct {
+		remote, forwarded, expected string
+	}{
+		{"1.2.3.4:5678", "", "1.2.3.4"},
+		{"1.2.3.4:5678", "192.168.0.1", "1.2.3.4"},         // not trusted from remote
+		{"127.0.0.1:5678", "10.0.0.1, 5.6.7.8", "5.6.7.8"}, // reverse proxy
+		{"192.168.0.2:5678", "5.6.7.8", "192.168.0.2"},     // nor from the LAN
+		{"127.0.0.1:5678", "", "127.0.0.1"},
+		{"127.0.0.1:5678", "garbage", "127.0.0.1"},
+	}
+	for _, tc := range cases {
+		req := &http.Request{RemoteAddr: tc.remote, Header: make(http.Header)}
+		if tc.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", tc.forwarded)
+		}
+		if ip := wsRemoteAddr(req).(*net.TCPAddr).IP.String(); ip != tc.expected {
+			t.Errorf("wsRemoteAddr(%s, %q) = %s, expected %s", tc.remote, tc.forwarded, ip, tc.expected)
+		}
+	}
+}
+
 func withConnectionPair(b interface{ Fatal(...interface{}) }, connUri string, h func(client, server internalConn)) {
 	// Root of the service tree.
 	supervisor := suture.New("main", suture.Spec{
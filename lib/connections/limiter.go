@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"sync/atomic"
+	"time"
 
 	"golang.org/x/time/rate"
 
@@ -20,7 +21,7 @@ import (
 )
 
 // limiter manages a read and write rate limit, reacting to config changes
-// as appropriate.
+// and bandwidth schedules as appropriate.
 type limiter struct {
 	myID                protocol.DeviceID
 	mu                  sync.Mutex
@@ -29,6 +30,8 @@ type limiter struct {
 	limitsLAN           atomic.Bool
 	deviceReadLimiters  map[protocol.DeviceID]*rate.Limiter
 	deviceWriteLimiters map[protocol.DeviceID]*rate.Limiter
+	cfg                 config.Configuration // the latest config, for evaluating the schedules
+	sendKbps, recvKbps  int                  // the overall limits currently in effect
 }
 
 type waiter interface {
@@ -49,44 +52,77 @@ func newLimiter(myId protocol.DeviceID, cfg config.Wrapper) *limiter {
 		mu:                  sync.NewMutex(),
 		deviceReadLimiters:  make(map[protocol.DeviceID]*rate.Limiter),
 		deviceWriteLimiters: make(map[protocol.DeviceID]*rate.Limiter),
+		sendKbps:            -1,
+		recvKbps:            -1,
 	}
 
 	cfg.Subscribe(l)
-	prev := config.Configuration{Options: config.OptionsConfiguration{MaxRecvKbps: -1, MaxSendKbps: -1}}
-
-	l.CommitConfiguration(prev, cfg.RawCopy())
+	l.CommitConfiguration(config.Configuration{}, cfg.RawCopy())
 	return l
 }
 
+// serve applies the bandwidth schedules as their windows start and end.
+// The windows have a resolution of one minute, so that is how often we
+// look at them.
+func (lim *limiter) serve(ctx context.Context) error {
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case now = <-timer.C:
+		}
+
+		lim.mu.Lock()
+		lim.processDevicesConfigurationLocked(lim.cfg, lim.cfg, now)
+		lim.setOverallLimitsLocked(lim.cfg.Options, now)
+		lim.mu.Unlock()
+	}
+}
+
+// kbpsLimit converts a limit in KiB/s from the config, where zero or less
+// means unlimited, to a rate in bytes/s.
+func kbpsLimit(kbps int) rate.Limit {
+	if kbps <= 0 {
+		return rate.Inf
+	}
+	return 1024 * rate.Limit(kbps)
+}
+
+func limitString(kbps int) string {
+	if kbps <= 0 {
+		return "is unlimited"
+	}
+	return fmt.Sprintf("limit is %d KiB/s", kbps)
+}
+
 // This function sets limiters according to corresponding DeviceConfiguration
-func (lim *limiter) setLimitsLocked(device config.DeviceConfiguration) bool {
+// and the bandwidth schedule window active at the given time.
+func (lim *limiter) setLimitsLocked(device config.DeviceConfiguration, now time.Time) (sendKbps, recvKbps int, changed bool) {
 	readLimiter := lim.getReadLimiterLocked(device.DeviceID)
 	writeLimiter := lim.getWriteLimiterLocked(device.DeviceID)
 
 	// limiters for this device are created so we can store previous rates for logging
 	previousReadLimit := readLimiter.Limit()
 	previousWriteLimit := writeLimiter.Limit()
-	currentReadLimit := rate.Limit(device.MaxRecvKbps) * 1024
-	currentWriteLimit := rate.Limit(device.MaxSendKbps) * 1024
-	if device.MaxSendKbps <= 0 {
-		currentWriteLimit = rate.Inf
-	}
-	if device.MaxRecvKbps <= 0 {
-		currentReadLimit = rate.Inf
-	}
+	sendKbps, recvKbps = device.BandwidthSchedule.Limits(now, device.MaxSendKbps, device.MaxRecvKbps)
+	currentReadLimit := kbpsLimit(recvKbps)
+	currentWriteLimit := kbpsLimit(sendKbps)
 	// Nothing about this device has changed. Start processing next device
 	if previousWriteLimit == currentWriteLimit && previousReadLimit == currentReadLimit {
-		return false
+		return sendKbps, recvKbps, false
 	}
 
 	readLimiter.SetLimit(currentReadLimit)
 	writeLimiter.SetLimit(currentWriteLimit)
 
-	return true
+	return sendKbps, recvKbps, true
 }
 
 // This function handles removing, adding and updating of device limiters.
-func (lim *limiter) processDevicesConfigurationLocked(from, to config.Configuration) {
+func (lim *limiter) processDevicesConfigurationLocked(from, to config.Configuration, now time.Time) {
 	seen := make(map[protocol.DeviceID]struct{})
 
 	// Mark devices which should not be removed, create new limiters if needed and assign new limiter rate
@@ -97,17 +133,8 @@ func (lim *limiter) processDevicesConfigurationLocked(from, to config.Configurat
 		}
 		seen[dev.DeviceID] = struct{}{}
 
-		if lim.setLimitsLocked(dev) {
-			readLimitStr := "is unlimited"
-			if dev.MaxRecvKbps > 0 {
-				readLimitStr = fmt.Sprintf("limit is %d KiB/s", dev.MaxRecvKbps)
-			}
-			writeLimitStr := "is unlimited"
-			if dev.MaxSendKbps > 0 {
-				writeLimitStr = fmt.Sprintf("limit is %d KiB/s", dev.MaxSendKbps)
-			}
-
-			l.Infof("Device %s send rate %s, receive rate %s", dev.DeviceID, writeLimitStr, readLimitStr)
+		if sendKbps, recvKbps, changed := lim.setLimitsLocked(dev, now); changed {
+			l.Infof("Device %s send rate %s, receive rate %s", dev.DeviceID, limitString(sendKbps), limitString(recvKbps))
 		}
 	}
 
@@ -122,53 +149,46 @@ func (lim *limiter) processDevicesConfigurationLocked(from, to config.Configurat
 	}
 }
 
-func (lim *limiter) CommitConfiguration(from, to config.Configuration) bool {
-	// to ensure atomic update of configuration
-	lim.mu.Lock()
-	defer lim.mu.Unlock()
-
-	// Delete, add or update limiters for devices
-	lim.processDevicesConfigurationLocked(from, to)
-
-	if from.Options.MaxRecvKbps == to.Options.MaxRecvKbps &&
-		from.Options.MaxSendKbps == to.Options.MaxSendKbps &&
-		from.Options.LimitBandwidthInLan == to.Options.LimitBandwidthInLan {
-		return true
+// setOverallLimitsLocked sets the limits shared by all devices, according
+// to the options and the bandwidth schedule window active at the given
+// time.
+func (lim *limiter) setOverallLimitsLocked(opts config.OptionsConfiguration, now time.Time) {
+	sendKbps, recvKbps := opts.BandwidthSchedule.Limits(now, opts.MaxSendKbps, opts.MaxRecvKbps)
+	if sendKbps == lim.sendKbps && recvKbps == lim.recvKbps &&
+		opts.LimitBandwidthInLan == lim.limitsLAN.Load() {
+		return
 	}
-
-	limited := false
-	sendLimitStr := "is unlimited"
-	recvLimitStr := "is unlimited"
+	lim.sendKbps, lim.recvKbps = sendKbps, recvKbps
 
 	// The rate variables are in KiB/s in the config (despite the camel casing
 	// of the name). We multiply by 1024 to get bytes/s.
-	if to.Options.MaxRecvKbps <= 0 {
-		lim.read.SetLimit(rate.Inf)
-	} else {
-		lim.read.SetLimit(1024 * rate.Limit(to.Options.MaxRecvKbps))
-		recvLimitStr = fmt.Sprintf("limit is %d KiB/s", to.Options.MaxRecvKbps)
-		limited = true
-	}
+	lim.read.SetLimit(kbpsLimit(recvKbps))
+	lim.write.SetLimit(kbpsLimit(sendKbps))
 
-	if to.Options.MaxSendKbps <= 0 {
-		lim.write.SetLimit(rate.Inf)
-	} else {
-		lim.write.SetLimit(1024 * rate.Limit(to.Options.MaxSendKbps))
-		sendLimitStr = fmt.Sprintf("limit is %d KiB/s", to.Options.MaxSendKbps)
-		limited = true
-	}
-
-	lim.limitsLAN.Store(to.Options.LimitBandwidthInLan)
+	lim.limitsLAN.Store(opts.LimitBandwidthInLan)
 
-	l.Infof("Overall send rate %s, receive rate %s", sendLimitStr, recvLimitStr)
+	l.Infof("Overall send rate %s, receive rate %s", limitString(sendKbps), limitString(recvKbps))
 
-	if limited {
-		if to.Options.LimitBandwidthInLan {
+	if sendKbps > 0 || recvKbps > 0 {
+		if opts.LimitBandwidthInLan {
 			l.Infoln("Rate limits apply to LAN connections")
 		} else {
 			l.Infoln("Rate limits do not apply to LAN connections")
 		}
 	}
+}
+
+func (lim *limiter) CommitConfiguration(from, to config.Configuration) bool {
+	// to ensure atomic update of configuration
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+
+	lim.cfg = to
+	now := time.Now()
+
+	// Delete, add or update limiters for devices
+	lim.processDevicesConfigurationLocked(from, to, now)
+	lim.setOverallLimitsLocked(to.Options, now)
 
 	return true
 }
@@ -216,15 +236,105 @@ func (lim *limiter) getWriteLimiterLocked(deviceID protocol.DeviceID) *rate.Limi
 	return getRateLimiter(lim.deviceWriteLimiters, deviceID)
 }
 
-func getRateLimiter(m map[protocol.DeviceID]*rate.Limiter, deviceID protocol.DeviceID) *rate.Limiter {
-	limiter, ok := m[deviceID]
+func getRateLimiter[K comparable](m map[K]*rate.Limiter, key K) *rate.Limiter {
+	limiter, ok := m[key]
 	if !ok {
 		limiter = rate.NewLimiter(rate.Inf, limiterBurstSize)
-		m[deviceID] = limiter
+		m[key] = limiter
 	}
 	return limiter
 }
 
+// FolderLimiter manages the send and receive rate limits of folders. They
+// apply to the block data of the folder on top of the connection limits,
+// and like those don't apply to LAN connections unless configured to. The
+// owner passes config changes on through CommitConfiguration.
+type FolderLimiter struct {
+	mu        sync.Mutex
+	write     map[string]*rate.Limiter
+	read      map[string]*rate.Limiter
+	limitsLAN atomic.Bool
+}
+
+func NewFolderLimiter(cfg config.Configuration) *FolderLimiter {
+	lim := &FolderLimiter{
+		mu:    sync.NewMutex(),
+		write: make(map[string]*rate.Limiter),
+		read:  make(map[string]*rate.Limiter),
+	}
+	lim.CommitConfiguration(config.Configuration{}, cfg)
+	return lim
+}
+
+func (lim *FolderLimiter) CommitConfiguration(_, to config.Configuration) bool {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(to.Folders))
+	for _, folder := range to.Folders {
+		seen[folder.ID] = struct{}{}
+
+		readLimiter := getRateLimiter(lim.read, folder.ID)
+		writeLimiter := getRateLimiter(lim.write, folder.ID)
+		readLimit := kbpsLimit(folder.MaxRecvKbps)
+		writeLimit := kbpsLimit(folder.MaxSendKbps)
+		if readLimiter.Limit() == readLimit && writeLimiter.Limit() == writeLimit {
+			continue
+		}
+		readLimiter.SetLimit(readLimit)
+		writeLimiter.SetLimit(writeLimit)
+		l.Infof("Folder %s send rate %s, receive rate %s", folder.Description(), limitString(folder.MaxSendKbps), limitString(folder.MaxRecvKbps))
+	}
+
+	for id := range lim.read {
+		if _, ok := seen[id]; !ok {
+			delete(lim.read, id)
+			delete(lim.write, id)
+		}
+	}
+
+	lim.limitsLAN.Store(to.Options.LimitBandwidthInLan)
+	return true
+}
+
+func (*FolderLimiter) String() string {
+	return "connections.FolderLimiter"
+}
+
+// WaitSend waits until n bytes of the folder may be sent over a LAN or
+// WAN connection.
+func (lim *FolderLimiter) WaitSend(ctx context.Context, folder string, isLAN bool, n int) error {
+	return lim.wait(ctx, lim.write, folder, isLAN, n)
+}
+
+// WaitRecv waits until n bytes of the folder may be received over a LAN
+// or WAN connection.
+func (lim *FolderLimiter) WaitRecv(ctx context.Context, folder string, isLAN bool, n int) error {
+	return lim.wait(ctx, lim.read, folder, isLAN, n)
+}
+
+func (lim *FolderLimiter) wait(ctx context.Context, limiters map[string]*rate.Limiter, folder string, isLAN bool, n int) error {
+	if isLAN && !lim.limitsLAN.Load() {
+		return nil
+	}
+	lim.mu.Lock()
+	limiter, ok := limiters[folder]
+	lim.mu.Unlock()
+	if !ok || limiter.Limit() == rate.Inf {
+		return nil
+	}
+
+	// No call to WaitN may be larger than the burst size.
+	for n > 0 {
+		tokens := min(n, limiterBurstSize)
+		if err := limiter.WaitN(ctx, tokens); err != nil {
+			return err
+		}
+		n -= tokens
+	}
+	return nil
+}
+
 // limitedReader is a rate limited io.Reader
 type limitedReader struct {
 	reader io.Reader
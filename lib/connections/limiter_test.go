@@ -14,6 +14,7 @@ import (
 	"math/rand"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"golang.org/x/time/rate"
 
@@ -217,6 +218,100 @@ func TestAddAndRemove(t *testing.T) {
 	checkActualAndExpected(t, actualR, actualW, expectedR, expectedW)
 }
 
+func TestBandwidthSchedule(t *testing.T) {
+	wrapper, wrapperCancel := initConfig()
+	defer wrapperCancel()
+	lim := newLimiter(device1, wrapper)
+
+	dev3Conf.MaxRecvKbps = 100
+	dev3Conf.BandwidthSchedule = config.BandwidthSchedule{
+		{Days: []string{"mon", "tue"}, Start: "22:00", End: "06:00", MaxRecvKbps: 0, MaxSendKbps: 50},
+	}
+	waiter, _ := wrapper.Modify(func(cfg *config.Configuration) {
+		cfg.Options.MaxSendKbps = 1000
+		cfg.Options.BandwidthSchedule = config.BandwidthSchedule{
+			{Start: "08:00", End: "17:00", MaxSendKbps: 10, MaxRecvKbps: 20},
+		}
+		cfg.SetDevice(dev3Conf)
+	})
+	waiter.Wait()
+
+	cases := []struct {
+		when                time.Time
+		sendKbps, recvKbps  int
+		dev3Write, dev3Read rate.Limit
+	}{
+		// Monday afternoon, in the overall window
+		{time.Date(2025, 3, 3, 12, 0, 0, 0, time.Local), 10, 20, rate.Inf, 100 * 1024},
+		// Monday evening, no window
+		{time.Date(2025, 3, 3, 20, 0, 0, 0, time.Local), 1000, 0, rate.Inf, 100 * 1024},
+		// Tuesday night, in the device window that started on Monday
+		{time.Date(2025, 3, 4, 5, 59, 0, 0, time.Local), 1000, 0, 50 * 1024, rate.Inf},
+		// Thursday night, the device window doesn't apply
+		{time.Date(2025, 3, 6, 5, 0, 0, 0, time.Local), 1000, 0, rate.Inf, 100 * 1024},
+	}
+	for _, tc := range cases {
+		lim.mu.Lock()
+		lim.processDevicesConfigurationLocked(lim.cfg, lim.cfg, tc.when)
+		lim.setOverallLimitsLocked(lim.cfg.Options, tc.when)
+		lim.mu.Unlock()
+
+		if lim.sendKbps != tc.sendKbps || lim.recvKbps != tc.recvKbps {
+			t.Errorf("%v: overall limits %d/%d, expected %d/%d", tc.when, lim.sendKbps, lim.recvKbps, tc.sendKbps, tc.recvKbps)
+		}
+		if l := lim.write.Limit(); l != kbpsLimit(tc.sendKbps) {
+			t.Errorf("%v: overall write limit %v, expected %d KiB/s", tc.when, l, tc.sendKbps)
+		}
+		if l := lim.deviceWriteLimiters[device3].Limit(); l != tc.dev3Write {
+			t.Errorf("%v: device write limit %v, expected %v", tc.when, l, tc.dev3Write)
+		}
+		if l := lim.deviceReadLimiters[device3].Limit(); l != tc.dev3Read {
+			t.Errorf("%v: device read limit %v, expected %v", tc.when, l, tc.dev3Read)
+		}
+	}
+}
+
+func TestFolderLimiter(t *testing.T) {
+	cfg := config.New(device1)
+	cfg.Folders = []config.FolderConfiguration{
+		{ID: "limited", MaxSendKbps: 1, MaxRecvKbps: 100},
+		{ID: "unlimited"},
+	}
+	lim := NewFolderLimiter(cfg)
+
+	if lim.read["limited"].Limit() != 100*1024 || lim.write["limited"].Limit() != 1024 {
+		t.Fatal("expected folder limits to be set")
+	}
+	if lim.read["unlimited"].Limit() != rate.Inf {
+		t.Error("expected unlimited folder")
+	}
+
+	// Drain the burst, after which sending takes time, except on the LAN
+	// where the limits don't apply by default.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := lim.WaitSend(ctx, "limited", false, limiterBurstSize); err != nil {
+		t.Fatal(err)
+	}
+	if err := lim.WaitSend(ctx, "limited", true, 1<<20); err != nil {
+		t.Error("expected LAN transfer not to be limited, got", err)
+	}
+	if err := lim.WaitSend(ctx, "limited", false, 1024); err == nil {
+		t.Error("expected WAN transfer to be limited")
+	}
+	if err := lim.WaitRecv(ctx, "unlimited", false, 1<<20); err != nil {
+		t.Error("expected unlimited folder not to wait, got", err)
+	}
+
+	// Removed folders are forgotten
+	to := cfg.Copy()
+	to.Folders = to.Folders[1:]
+	lim.CommitConfiguration(cfg, to)
+	if _, ok := lim.read["limited"]; ok {
+		t.Error("expected removed folder's limiter to be deleted")
+	}
+}
+
 func TestLimitedWriterWrite(t *testing.T) {
 	// Check that the limited writer writes the correct data in the correct manner.
 
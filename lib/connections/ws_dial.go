@@ -0,0 +1,128 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package connections
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/connections/registry"
+	"github.com/syncthing/syncthing/lib/dialer"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func init() {
+	factory := &wsDialerFactory{}
+	for _, scheme := range []string{"ws", "wss"} {
+		dialers[scheme] = factory
+	}
+}
+
+type wsDialer struct {
+	commonDialer
+}
+
+func (d *wsDialer) Dial(ctx context.Context, _ protocol.DeviceID, uri *url.URL) (internalConn, error) {
+	uri = fixupPort(uri, wsDefaultPort(uri))
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	// Goes through the proxy from all_proxy, if any.
+	conn, err := dialer.DialContext(timeoutCtx, "tcp", uri.Host)
+	if err != nil {
+		return internalConn{}, err
+	}
+
+	err = dialer.SetTCPOptions(conn)
+	if err != nil {
+		l.Debugln("Dial (BEP/websocket): setting tcp options:", err)
+	}
+
+	err = dialer.SetTrafficClass(conn, d.trafficClass)
+	if err != nil {
+		l.Debugln("Dial (BEP/websocket): setting traffic class:", err)
+	}
+
+	ws, err := d.handshake(timeoutCtx, conn, uri)
+	if err != nil {
+		conn.Close()
+		return internalConn{}, err
+	}
+
+	tc := tls.Client(newWSConn(ws, conn.LocalAddr(), conn.RemoteAddr()), d.tlsCfg)
+	err = tlsTimedHandshake(tc)
+	if err != nil {
+		tc.Close()
+		return internalConn{}, err
+	}
+
+	priority := d.wanPriority
+	isLocal := d.lanChecker.isLAN(conn.RemoteAddr())
+	if isLocal {
+		priority = d.lanPriority
+	}
+
+	return newInternalConn(tc, connTypeWebSocketClient, isLocal, priority), nil
+}
+
+// handshake sets up the HTTPS connection, if any, and the WebSocket on top
+// of the connection.
+func (d *wsDialer) handshake(ctx context.Context, conn net.Conn, uri *url.URL) (*websocket.Conn, error) {
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	defer conn.SetDeadline(time.Time{})
+
+	var rwc net.Conn = conn
+	origin := &url.URL{Scheme: "http", Host: uri.Host}
+	if uri.Scheme == "wss" {
+		tc := tls.Client(conn, wsOuterTLSConfig(d.tlsCfg, uri.Hostname()))
+		if err := tc.HandshakeContext(ctx); err != nil {
+			return nil, err
+		}
+		rwc = tc
+		origin.Scheme = "https"
+	}
+
+	wsCfg, err := websocket.NewConfig(uri.String(), origin.String())
+	if err != nil {
+		return nil, err
+	}
+	return websocket.NewClient(wsCfg, rwc)
+}
+
+type wsDialerFactory struct{}
+
+func (wsDialerFactory) New(opts config.OptionsConfiguration, tlsCfg *tls.Config, _ *registry.Registry, lanChecker *lanChecker) genericDialer {
+	return &wsDialer{commonDialer{
+		trafficClass:      opts.TrafficClass,
+		reconnectInterval: time.Duration(opts.ReconnectIntervalS) * time.Second,
+		tlsCfg:            tlsCfg,
+		lanChecker:        lanChecker,
+		lanPriority:       opts.ConnectionPriorityWebSocket,
+		wanPriority:       opts.ConnectionPriorityWebSocket,
+		allowsMultiConns:  true,
+	}}
+}
+
+func (wsDialerFactory) AlwaysWAN() bool {
+	return false
+}
+
+func (wsDialerFactory) Valid(_ config.Configuration) error {
+	// Always valid
+	return nil
+}
+
+func (wsDialerFactory) String() string {
+	return "WebSocket Dialer"
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package connections
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/connections/registry"
+	"github.com/syncthing/syncthing/lib/dialer"
+	"github.com/syncthing/syncthing/lib/nat"
+	"github.com/syncthing/syncthing/lib/svcutil"
+)
+
+func init() {
+	factory := &wsListenerFactory{}
+	for _, scheme := range []string{"ws", "wss"} {
+		listeners[scheme] = factory
+	}
+}
+
+type wsListener struct {
+	svcutil.ServiceWithError
+	onAddressesChangedNotifier
+
+	uri        *url.URL
+	cfg        config.Wrapper
+	tlsCfg     *tls.Config
+	conns      chan internalConn
+	factory    listenerFactory
+	lanChecker *lanChecker
+
+	laddr net.Addr
+	mut   sync.RWMutex
+}
+
+func (t *wsListener) serve(ctx context.Context) error {
+	tcaddr, err := net.ResolveTCPAddr("tcp", t.uri.Host)
+	if err != nil {
+		l.Infoln("Listen (BEP/websocket):", err)
+		return err
+	}
+
+	listener, err := net.Listen("tcp", tcaddr.String())
+	if err != nil {
+		l.Infoln("Listen (BEP/websocket):", err)
+		return err
+	}
+	defer listener.Close()
+
+	// We might bind to :0, so use the port we've been given.
+	tcaddr = listener.Addr().(*net.TCPAddr)
+
+	t.mut.Lock()
+	t.laddr = tcaddr
+	t.mut.Unlock()
+	defer func() {
+		t.mut.Lock()
+		t.laddr = nil
+		t.mut.Unlock()
+	}()
+
+	t.notifyAddressesChanged(t)
+	defer t.clearAddresses(t)
+
+	l.Infof("WebSocket listener (%v) starting", maybeReplacePort(t.uri, tcaddr))
+	defer l.Infof("WebSocket listener (%v) shutting down", maybeReplacePort(t.uri, tcaddr))
+
+	var httpListener net.Listener = &wsTCPListener{Listener: listener, cfg: t.cfg}
+	if t.uri.Scheme == "wss" {
+		httpListener = tls.NewListener(httpListener, wsOuterTLSConfig(t.tlsCfg, ""))
+	}
+
+	srv := &http.Server{
+		Handler:           websocket.Server{Handler: t.handle},
+		ReadHeaderTimeout: 10 * time.Second,
+		ErrorLog:          log.New(io.Discard, "", 0),
+	}
+	// Connections already handed off are hijacked, and hence not closed
+	// along with the server.
+	stop := context.AfterFunc(ctx, func() { srv.Close() })
+	defer stop()
+
+	err = srv.Serve(httpListener)
+	if ctx.Err() != nil {
+		return nil
+	}
+	l.Warnln("Listen (BEP/websocket):", err)
+	return err
+}
+
+// handle runs for the lifetime of an incoming WebSocket, which is closed
+// when it returns.
+func (t *wsListener) handle(ws *websocket.Conn) {
+	req := ws.Request()
+	remoteAddr := wsRemoteAddr(req)
+	localAddr, _ := req.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	l.Debugln("Listen (BEP/websocket): connect from", remoteAddr)
+
+	wc := newWSConn(ws, localAddr, remoteAddr)
+	tc := tls.Server(wc, t.tlsCfg)
+	if err := tlsTimedHandshake(tc); err != nil {
+		l.Infoln("Listen (BEP/websocket): TLS handshake:", err)
+		tc.Close()
+		return
+	}
+
+	isLocal := t.lanChecker.isLAN(remoteAddr)
+	t.conns <- newInternalConn(tc, connTypeWebSocketServer, isLocal, t.cfg.Options().ConnectionPriorityWebSocket)
+	<-wc.closed
+}
+
+// wsRemoteAddr returns the address of the remote device. Behind a reverse
+// proxy on the same host that is the last address in X-Forwarded-For. The
+// header is only trusted from loopback addresses, as anyone else could
+// otherwise pretend to be on the LAN or in the allowed networks.
+func wsRemoteAddr(req *http.Request) net.Addr {
+	addr, err := net.ResolveTCPAddr("tcp", req.RemoteAddr)
+	if err != nil {
+		return &net.TCPAddr{}
+	}
+	if !addr.IP.IsLoopback() {
+		return addr
+	}
+	fwd := req.Header.Get("X-Forwarded-For")
+	if fwd == "" {
+		return addr
+	}
+	fwd = strings.TrimSpace(fwd[strings.LastIndex(fwd, ",")+1:])
+	if ip := net.ParseIP(fwd); ip != nil {
+		return &net.TCPAddr{IP: ip}
+	}
+	return addr
+}
+
+func (t *wsListener) URI() *url.URL {
+	return t.uri
+}
+
+func (t *wsListener) WANAddresses() []*url.URL {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+	return []*url.URL{maybeReplacePort(t.uri, t.laddr)}
+}
+
+func (t *wsListener) LANAddresses() []*url.URL {
+	t.mut.RLock()
+	uri := maybeReplacePort(t.uri, t.laddr)
+	t.mut.RUnlock()
+	addrs := []*url.URL{uri}
+	addrs = append(addrs, getURLsForAllAdaptersIfUnspecified("tcp", uri)...)
+	return addrs
+}
+
+func (t *wsListener) String() string {
+	return t.uri.String()
+}
+
+func (t *wsListener) Factory() listenerFactory {
+	return t.factory
+}
+
+func (*wsListener) NATType() string {
+	return "unknown"
+}
+
+// wsTCPListener sets our TCP options on accepted connections.
+type wsTCPListener struct {
+	net.Listener
+	cfg config.Wrapper
+}
+
+func (ln *wsTCPListener) Accept() (net.Conn, error) {
+	conn, err := ln.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if err := dialer.SetTCPOptions(conn); err != nil {
+		l.Debugln("Listen (BEP/websocket): setting tcp options:", err)
+	}
+	if tc := ln.cfg.Options().TrafficClass; tc != 0 {
+		if err := dialer.SetTrafficClass(conn, tc); err != nil {
+			l.Debugln("Listen (BEP/websocket): setting traffic class:", err)
+		}
+	}
+	return conn, nil
+}
+
+type wsListenerFactory struct{}
+
+func (f *wsListenerFactory) New(uri *url.URL, cfg config.Wrapper, tlsCfg *tls.Config, conns chan internalConn, _ *nat.Service, _ *registry.Registry, lanChecker *lanChecker) genericListener {
+	l := &wsListener{
+		uri:        fixupPort(uri, wsDefaultPort(uri)),
+		cfg:        cfg,
+		tlsCfg:     tlsCfg,
+		conns:      conns,
+		factory:    f,
+		lanChecker: lanChecker,
+	}
+	l.ServiceWithError = svcutil.AsService(l.serve, l.String())
+	return l
+}
+
+func (wsListenerFactory) Valid(_ config.Configuration) error {
+	// Always valid
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package connections
+
+import (
+	"crypto/tls"
+	"net"
+	"net/url"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// BEP over WebSocket is the usual BEP TLS connection, tunnelled in the
+// binary frames of a WebSocket. A ws:// address speaks plain HTTP, for
+// listening behind a reverse proxy that terminates HTTPS; a wss:// address
+// speaks HTTPS itself. Either way the devices authenticate each other in
+// the tunnelled TLS handshake, so the outer HTTPS connection only serves to
+// get through proxies and is not verified.
+
+func wsDefaultPort(uri *url.URL) int {
+	if uri.Scheme == "wss" {
+		return 443
+	}
+	return 80
+}
+
+// wsOuterTLSConfig returns the configuration for the HTTPS connection the
+// WebSocket runs over, based on the BEP TLS configuration.
+func wsOuterTLSConfig(tlsCfg *tls.Config, serverName string) *tls.Config {
+	return &tls.Config{
+		Certificates:       tlsCfg.Certificates,
+		ServerName:         serverName,
+		NextProtos:         []string{"http/1.1"},
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true, // The device is verified in the tunnelled handshake.
+	}
+}
+
+// wsConn is a WebSocket connection with the addresses of the underlying
+// network connection, rather than the WebSocket URLs.
+type wsConn struct {
+	*websocket.Conn
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+func newWSConn(ws *websocket.Conn, localAddr, remoteAddr net.Addr) *wsConn {
+	ws.PayloadType = websocket.BinaryFrame
+	return &wsConn{
+		Conn:       ws,
+		localAddr:  localAddr,
+		remoteAddr: remoteAddr,
+		closed:     make(chan struct{}),
+	}
+}
+
+func (c *wsConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *wsConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c *wsConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return c.Conn.Close()
+}
@@ -0,0 +1,392 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package backend
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/gofrs/flock"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	sqliteGet        = `SELECT value FROM kv WHERE key = ?`
+	sqlitePut        = `INSERT OR REPLACE INTO kv (key, value) VALUES (?, ?)`
+	sqliteDelete     = `DELETE FROM kv WHERE key = ?`
+	sqliteIterAll    = `SELECT key, value FROM kv ORDER BY key`
+	sqliteIterFrom   = `SELECT key, value FROM kv WHERE key >= ? ORDER BY key`
+	sqliteIterTo     = `SELECT key, value FROM kv WHERE key < ? ORDER BY key`
+	sqliteIterRange  = `SELECT key, value FROM kv WHERE key >= ? AND key < ? ORDER BY key`
+	sqliteSnapshotAt = `SELECT 1 FROM kv LIMIT 1`
+)
+
+// sqliteBackend implements Backend on top of a single key-value table in
+// an SQLite database in WAL mode. Read transactions are SQLite read
+// transactions, which see a stable snapshot of the database. Write
+// transactions mimic the leveldb backend: reads come from a snapshot taken
+// when the transaction was created, while writes are collected in a batch
+// and applied in an SQLite write transaction when flushed.
+type sqliteBackend struct {
+	db       *sql.DB
+	closeWG  *closeWaitGroup
+	location string
+	lock     *flock.Flock // held while the database is open
+	writeMut sync.Mutex   // SQLite supports only one writer at a time
+}
+
+func newSqliteBackend(db *sql.DB, location string, lock *flock.Flock) *sqliteBackend {
+	return &sqliteBackend{
+		db:       db,
+		closeWG:  &closeWaitGroup{},
+		location: location,
+		lock:     lock,
+	}
+}
+
+func (b *sqliteBackend) NewReadTransaction() (ReadTransaction, error) {
+	return b.newSnapshot()
+}
+
+func (b *sqliteBackend) newSnapshot() (*sqliteSnapshot, error) {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := b.db.Begin()
+	if err != nil {
+		rel.Release()
+		return nil, wrapSqliteErr(err)
+	}
+	// SQLite transactions are deferred; the snapshot is established by
+	// the first read, not by BEGIN.
+	if err := tx.QueryRow(sqliteSnapshotAt).Scan(new(int)); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		_ = tx.Rollback()
+		rel.Release()
+		return nil, wrapSqliteErr(err)
+	}
+	return &sqliteSnapshot{
+		tx:  tx,
+		rel: rel,
+	}, nil
+}
+
+func (b *sqliteBackend) NewWriteTransaction(hooks ...CommitHook) (WriteTransaction, error) {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := b.newSnapshot()
+	if err != nil {
+		rel.Release()
+		return nil, err // already wrapped
+	}
+	return &sqliteTransaction{
+		sqliteSnapshot: snap,
+		b:              b,
+		rel:            rel,
+		commitHooks:    hooks,
+	}, nil
+}
+
+func (b *sqliteBackend) Close() error {
+	b.closeWG.CloseWait()
+	err := b.db.Close()
+	b.lock.Unlock()
+	return wrapSqliteErr(err)
+}
+
+func (b *sqliteBackend) Get(key []byte) ([]byte, error) {
+	if err := b.closeWG.Add(1); err != nil {
+		return nil, err
+	}
+	defer b.closeWG.Done()
+	var val []byte
+	err := b.db.QueryRow(sqliteGet, key).Scan(&val)
+	return val, wrapSqliteErr(err)
+}
+
+func (b *sqliteBackend) NewPrefixIterator(prefix []byte) (Iterator, error) {
+	r := util.BytesPrefix(prefix)
+	return b.NewRangeIterator(r.Start, r.Limit)
+}
+
+func (b *sqliteBackend) NewRangeIterator(first, last []byte) (Iterator, error) {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return nil, err
+	}
+	it, err := newSqliteIterator(b.db, first, last)
+	if err != nil {
+		rel.Release()
+		return nil, err
+	}
+	it.rel = rel
+	return it, nil
+}
+
+func (b *sqliteBackend) Put(key, val []byte) error {
+	if err := b.closeWG.Add(1); err != nil {
+		return err
+	}
+	defer b.closeWG.Done()
+	if val == nil {
+		val = []byte{} // nil would be NULL
+	}
+	b.writeMut.Lock()
+	defer b.writeMut.Unlock()
+	_, err := b.db.Exec(sqlitePut, key, val)
+	return wrapSqliteErr(err)
+}
+
+func (b *sqliteBackend) Delete(key []byte) error {
+	if err := b.closeWG.Add(1); err != nil {
+		return err
+	}
+	defer b.closeWG.Done()
+	b.writeMut.Lock()
+	defer b.writeMut.Unlock()
+	_, err := b.db.Exec(sqliteDelete, key)
+	return wrapSqliteErr(err)
+}
+
+func (b *sqliteBackend) Compact() error {
+	if err := b.closeWG.Add(1); err != nil {
+		return err
+	}
+	defer b.closeWG.Done()
+	b.writeMut.Lock()
+	defer b.writeMut.Unlock()
+	_, err := b.db.Exec(`VACUUM`)
+	return wrapSqliteErr(err)
+}
+
+func (b *sqliteBackend) Location() string {
+	return b.location
+}
+
+// write applies the given operations in a single SQLite transaction.
+func (b *sqliteBackend) write(ops []sqliteOp) error {
+	b.writeMut.Lock()
+	defer b.writeMut.Unlock()
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return wrapSqliteErr(err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	put, err := tx.Prepare(sqlitePut)
+	if err != nil {
+		return wrapSqliteErr(err)
+	}
+	defer put.Close()
+	del, err := tx.Prepare(sqliteDelete)
+	if err != nil {
+		return wrapSqliteErr(err)
+	}
+	defer del.Close()
+
+	for _, op := range ops {
+		if op.delete {
+			_, err = del.Exec(op.key)
+		} else {
+			_, err = put.Exec(op.key, op.val)
+		}
+		if err != nil {
+			return wrapSqliteErr(err)
+		}
+	}
+	return wrapSqliteErr(tx.Commit())
+}
+
+// sqliteSnapshot implements backend.ReadTransaction
+type sqliteSnapshot struct {
+	tx  *sql.Tx
+	rel *releaser
+}
+
+func (s *sqliteSnapshot) Get(key []byte) ([]byte, error) {
+	var val []byte
+	err := s.tx.QueryRow(sqliteGet, key).Scan(&val)
+	return val, wrapSqliteErr(err)
+}
+
+func (s *sqliteSnapshot) NewPrefixIterator(prefix []byte) (Iterator, error) {
+	r := util.BytesPrefix(prefix)
+	return s.NewRangeIterator(r.Start, r.Limit)
+}
+
+func (s *sqliteSnapshot) NewRangeIterator(first, last []byte) (Iterator, error) {
+	return newSqliteIterator(s.tx, first, last)
+}
+
+func (s *sqliteSnapshot) Release() {
+	// Rolling back an already finished transaction is a no-op returning
+	// sql.ErrTxDone.
+	_ = s.tx.Rollback()
+	s.rel.Release()
+}
+
+type sqliteOp struct {
+	key, val []byte
+	delete   bool
+}
+
+// sqliteTransaction implements backend.WriteTransaction using a batch of
+// operations applied on flush, and a snapshot for reads.
+type sqliteTransaction struct {
+	*sqliteSnapshot
+	b           *sqliteBackend
+	batch       []sqliteOp
+	batchSize   int
+	rel         *releaser
+	commitHooks []CommitHook
+	inFlush     bool
+}
+
+func (t *sqliteTransaction) Delete(key []byte) error {
+	// The caller may reuse the key buffer, so we must copy it.
+	t.batch = append(t.batch, sqliteOp{key: append([]byte(nil), key...), delete: true})
+	t.batchSize += len(key)
+	return t.checkFlush(dbFlushBatchMax)
+}
+
+func (t *sqliteTransaction) Put(key, val []byte) error {
+	// The caller may reuse the key and value buffers, so we must copy
+	// them. The value copy is never nil, as that would be a NULL.
+	t.batch = append(t.batch, sqliteOp{key: append([]byte(nil), key...), val: append([]byte{}, val...)})
+	t.batchSize += len(key) + len(val)
+	return t.checkFlush(dbFlushBatchMax)
+}
+
+func (t *sqliteTransaction) Checkpoint() error {
+	return t.checkFlush(dbFlushBatchMin)
+}
+
+func (t *sqliteTransaction) Commit() error {
+	err := t.flush()
+	t.sqliteSnapshot.Release()
+	t.rel.Release()
+	return err
+}
+
+func (t *sqliteTransaction) Release() {
+	t.sqliteSnapshot.Release()
+	t.rel.Release()
+}
+
+// checkFlush flushes and resets the batch if its size exceeds the given size.
+func (t *sqliteTransaction) checkFlush(size int) error {
+	// Hooks might put values in the database, which triggers a checkFlush
+	// which might trigger a flush, which might trigger the hooks. Don't
+	// recurse...
+	if t.inFlush || t.batchSize < size {
+		return nil
+	}
+	return t.flush()
+}
+
+func (t *sqliteTransaction) flush() error {
+	t.inFlush = true
+	defer func() { t.inFlush = false }()
+
+	for _, hook := range t.commitHooks {
+		if err := hook(t); err != nil {
+			return err
+		}
+	}
+	if len(t.batch) == 0 {
+		return nil
+	}
+	if err := t.b.write(t.batch); err != nil {
+		return err
+	}
+	t.batch = t.batch[:0]
+	t.batchSize = 0
+	return nil
+}
+
+// sqliteQueryer is the query method shared by *sql.DB and *sql.Tx
+type sqliteQueryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+type sqliteIterator struct {
+	rows     *sql.Rows
+	key, val []byte
+	err      error
+	rel      *releaser // set for iterators outside of a transaction
+}
+
+func newSqliteIterator(q sqliteQueryer, first, last []byte) (*sqliteIterator, error) {
+	var rows *sql.Rows
+	var err error
+	switch {
+	case first == nil && last == nil:
+		rows, err = q.Query(sqliteIterAll)
+	case last == nil:
+		rows, err = q.Query(sqliteIterFrom, first)
+	case first == nil:
+		rows, err = q.Query(sqliteIterTo, last)
+	default:
+		rows, err = q.Query(sqliteIterRange, first, last)
+	}
+	if err != nil {
+		return nil, wrapSqliteErr(err)
+	}
+	return &sqliteIterator{rows: rows}, nil
+}
+
+func (it *sqliteIterator) Next() bool {
+	if it.err != nil || it.rows == nil {
+		return false
+	}
+	if !it.rows.Next() {
+		it.err = wrapSqliteErr(it.rows.Err())
+		return false
+	}
+	if err := it.rows.Scan(&it.key, &it.val); err != nil {
+		it.err = wrapSqliteErr(err)
+		return false
+	}
+	return true
+}
+
+func (it *sqliteIterator) Key() []byte {
+	return it.key
+}
+
+func (it *sqliteIterator) Value() []byte {
+	return it.val
+}
+
+func (it *sqliteIterator) Error() error {
+	return it.err
+}
+
+func (it *sqliteIterator) Release() {
+	if it.rows != nil {
+		_ = it.rows.Close()
+		it.rows = nil
+	}
+	if it.rel != nil {
+		it.rel.Release()
+	}
+}
+
+// wrapSqliteErr wraps errors so that the backend package can recognize them
+func wrapSqliteErr(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return errNotFound
+	case errors.Is(err, sql.ErrConnDone), errors.Is(err, sql.ErrTxDone):
+		return errClosed
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package backend
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gofrs/flock"
+	_ "modernc.org/sqlite" // register the "sqlite" driver
+)
+
+// The pragmas are applied by the driver to every new connection.
+const sqliteOptions = "?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(30000)"
+
+// SQLiteLockSuffix is appended to the database location to get the file
+// locked by the process using the database.
+const SQLiteLockSuffix = ".lock"
+
+var errSQLiteLocked = errors.New("database is locked by another process")
+
+// OpenSQLite opens the SQLite database at the given location, creating it
+// if it does not exist. SQLite itself allows several processes to use the
+// database, so like LevelDB we hold a lock on a file next to it until the
+// database is closed.
+func OpenSQLite(location string) (Backend, error) {
+	lock := flock.New(location + SQLiteLockSuffix)
+	if locked, err := lock.TryLock(); err != nil {
+		return nil, err
+	} else if !locked {
+		return nil, errSQLiteLocked
+	}
+
+	db, err := sql.Open("sqlite", location+sqliteOptions)
+	if err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS kv (
+		key BLOB NOT NULL PRIMARY KEY,
+		value BLOB NOT NULL
+	) WITHOUT ROWID`); err != nil {
+		db.Close()
+		lock.Unlock()
+		return nil, err
+	}
+	return newSqliteBackend(db, location, lock), nil
+}
+
+// MigrateLevelDBToSQLite copies the entire contents of the LevelDB
+// database at from into a new SQLite database at to. The SQLite database
+// is built under a temporary name and only moved into place once complete,
+// so an interrupted migration can simply be run again.
+func MigrateLevelDBToSQLite(from, to string) error {
+	src, err := OpenLevelDBRO(from)
+	if err != nil {
+		return fmt.Errorf("opening source: %w", err)
+	}
+	defer src.Close()
+
+	tmp := to + ".migrating"
+	for _, suffix := range []string{"", "-wal", "-shm", SQLiteLockSuffix} {
+		if err := os.Remove(tmp + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	dst, err := OpenSQLite(tmp)
+	if err != nil {
+		return fmt.Errorf("creating destination: %w", err)
+	}
+
+	if err := copyAll(src, dst); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	os.Remove(tmp + SQLiteLockSuffix)
+	return os.Rename(tmp, to)
+}
+
+func copyAll(src, dst Backend) error {
+	snap, err := src.NewReadTransaction()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+	it, err := snap.NewPrefixIterator(nil)
+	if err != nil {
+		return err
+	}
+	defer it.Release()
+
+	tx, err := dst.NewWriteTransaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+
+	var n int
+	for it.Next() {
+		if err := tx.Put(it.Key(), it.Value()); err != nil {
+			return err
+		}
+		n++
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	l.Infof("Migrated %d database entries", n)
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func openTestSQLite(t *testing.T) func() Backend {
+	return func() Backend {
+		db, err := OpenSQLite(filepath.Join(t.TempDir(), "index.sqlite"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db
+	}
+}
+
+func TestSQLiteBackendBehavior(t *testing.T) {
+	testBackendBehavior(t, openTestSQLite(t))
+}
+
+func TestSQLiteIterators(t *testing.T) {
+	db := openTestSQLite(t)()
+	defer db.Close()
+
+	for _, k := range []string{"a1", "a2", "b1", "b2", "c1"} {
+		if err := db.Put([]byte(k), []byte("v"+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collect := func(it Iterator, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer it.Release()
+		var res string
+		for it.Next() {
+			res += fmt.Sprintf("%s=%s ", it.Key(), it.Value())
+		}
+		if err := it.Error(); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := collect(db.NewPrefixIterator([]byte("b"))); res != "b1=vb1 b2=vb2 " {
+		t.Error("unexpected prefix iteration result:", res)
+	}
+	if res := collect(db.NewRangeIterator([]byte("a2"), []byte("c1"))); res != "a2=va2 b1=vb1 b2=vb2 " {
+		t.Error("unexpected range iteration result:", res)
+	}
+	if res := collect(db.NewPrefixIterator(nil)); res != "a1=va1 a2=va2 b1=vb1 b2=vb2 c1=vc1 " {
+		t.Error("unexpected full iteration result:", res)
+	}
+
+	// Iterating and deleting in a transaction sees the original snapshot,
+	// while the deletes are visible after commit.
+
+	tx, err := db.NewWriteTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Release()
+	it, err := tx.NewPrefixIterator([]byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for it.Next() {
+		if err := tx.Delete(it.Key()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	it.Release()
+	if _, err := tx.Get([]byte("a1")); err != nil {
+		t.Error("expected deleted key to be visible within transaction:", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get([]byte("a1")); !IsNotFound(err) {
+		t.Error("expected not found error after commit, got", err)
+	}
+	if res := collect(db.NewPrefixIterator(nil)); res != "b1=vb1 b2=vb2 c1=vc1 " {
+		t.Error("unexpected iteration result after commit:", res)
+	}
+}
+
+func TestSQLiteCommitHooks(t *testing.T) {
+	db := openTestSQLite(t)()
+	defer db.Close()
+
+	tx, err := db.NewWriteTransaction(func(tx WriteTransaction) error {
+		return tx.Put([]byte("hook"), []byte("ran"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Release()
+	if err := tx.Put([]byte("key"), []byte("val")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	for k, v := range map[string]string{"key": "val", "hook": "ran"} {
+		if val, err := db.Get([]byte(k)); err != nil {
+			t.Fatal(err)
+		} else if string(val) != v {
+			t.Errorf("unexpected value %q for %q", val, k)
+		}
+	}
+}
+
+func TestMigrateLevelDBToSQLite(t *testing.T) {
+	dir := t.TempDir()
+	ldbPath := filepath.Join(dir, "index-v0.14.0.db")
+	sqlitePath := filepath.Join(dir, "index.sqlite")
+
+	ldb, err := OpenLevelDB(ldbPath, TuningAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		if err := ldb.Put([]byte(fmt.Sprintf("key%04d", i)), []byte(fmt.Sprintf("val%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ldb.Close()
+
+	if err := MigrateLevelDBToSQLite(ldbPath, sqlitePath); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := OpenSQLite(sqlitePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	it, err := db.NewPrefixIterator([]byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Release()
+	n := 0
+	for it.Next() {
+		if exp := fmt.Sprintf("key%04d", n); string(it.Key()) != exp {
+			t.Fatalf("unexpected key %q, expected %q", it.Key(), exp)
+		}
+		if exp := fmt.Sprintf("val%d", n); string(it.Value()) != exp {
+			t.Fatalf("unexpected value %q, expected %q", it.Value(), exp)
+		}
+		n++
+	}
+	if n != 1000 {
+		t.Error("unexpected number of migrated entries", n)
+	}
+}
+
+func TestSQLiteLocked(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "index.sqlite")
+	db, err := OpenSQLite(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, err := OpenSQLite(location); err == nil {
+		other.Close()
+		t.Fatal("expected the database to be locked while open")
+	}
+	db.Close()
+
+	db, err = OpenSQLite(location)
+	if err != nil {
+		t.Fatal("expected the lock to be released on close:", err)
+	}
+	db.Close()
+}
@@ -353,7 +353,7 @@ func TestGCIndirect(t *testing.T) {
 		{Name: "c", Blocks: genBlocks(300)},
 	}
 
-	db.updateLocalFiles([]byte("folder"), files, meta)
+	db.updateLocalFiles([]byte("folder"), files, meta, false)
 
 	// Run a GC pass
 
@@ -376,7 +376,7 @@ func TestGCIndirect(t *testing.T) {
 		files[i].Blocks = genBlocks(len(files[i].Blocks) + 1)
 	}
 
-	db.updateLocalFiles([]byte("folder"), files, meta)
+	db.updateLocalFiles([]byte("folder"), files, meta, false)
 
 	// Verify that we now have *six* different block lists
 
@@ -445,7 +445,7 @@ func TestUpdateTo14(t *testing.T) {
 	}
 
 	// Initially add the correct file the usual way, all good here.
-	if err := db.updateLocalFiles(folder, []protocol.FileInfo{file}, meta); err != nil {
+	if err := db.updateLocalFiles(folder, []protocol.FileInfo{file}, meta, false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -0,0 +1,164 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"bytes"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/syncthing/syncthing/internal/gen/dbproto"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// maxFileHistory is the number of index changes remembered per file.
+const maxFileHistory = 50
+
+// A FileHistoryEntry records a change to the index entry of a file, i.e. a
+// device announcing a new version of it.
+type FileHistoryEntry struct {
+	Time time.Time `json:"time"`
+	// The device whose index changed, protocol.LocalDeviceID for our own
+	// files. Which device made the change is in ModifiedBy.
+	Device     protocol.DeviceID `json:"device"`
+	Local      bool              `json:"local"`
+	Version    protocol.Vector   `json:"version"`
+	ModifiedBy protocol.ShortID  `json:"modifiedBy"`
+	Size       int64             `json:"size"`
+	ModTime    time.Time         `json:"modTime"`
+	BlocksHash []byte            `json:"blocksHash"`
+	Deleted    bool              `json:"deleted"`
+}
+
+func newFileHistoryEntryWire(device protocol.DeviceID, f protocol.FileInfo) *dbproto.FileHistoryEntry {
+	return &dbproto.FileHistoryEntry{
+		Time:       timestamppb.New(time.Now().Truncate(time.Second)),
+		Device:     device[:],
+		Version:    f.Version.ToWire(),
+		ModifiedBy: uint64(f.ModifiedBy),
+		Size:       f.FileSize(),
+		ModifiedS:  f.ModifiedS,
+		ModifiedNs: f.ModifiedNs,
+		BlocksHash: f.BlocksHash,
+		Deleted:    f.IsDeleted(),
+	}
+}
+
+func fileHistoryEntryFromWire(w *dbproto.FileHistoryEntry) FileHistoryEntry {
+	device, _ := protocol.DeviceIDFromBytes(w.GetDevice())
+	return FileHistoryEntry{
+		Time:       w.GetTime().AsTime(),
+		Device:     device,
+		Local:      device == protocol.LocalDeviceID,
+		Version:    protocol.VectorFromWire(w.GetVersion()),
+		ModifiedBy: protocol.ShortID(w.GetModifiedBy()),
+		Size:       w.GetSize(),
+		ModTime:    time.Unix(w.GetModifiedS(), int64(w.GetModifiedNs())),
+		BlocksHash: w.GetBlocksHash(),
+		Deleted:    w.GetDeleted(),
+	}
+}
+
+// getFileHistory returns the history of the file, newest first.
+func (t readOnlyTransaction) getFileHistory(key []byte) ([]FileHistoryEntry, error) {
+	hist, err := t.getFileHistoryWire(key)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]FileHistoryEntry, len(hist.Entries))
+	for i, w := range hist.Entries {
+		entries[i] = fileHistoryEntryFromWire(w)
+	}
+	return entries, nil
+}
+
+func (t readOnlyTransaction) getFileHistoryWire(key []byte) (*dbproto.FileHistory, error) {
+	var hist dbproto.FileHistory
+	bs, err := t.Get(key)
+	if backend.IsNotFound(err) {
+		return &hist, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(bs, &hist); err != nil {
+		return nil, err
+	}
+	return &hist, nil
+}
+
+// recordFileHistory adds the new version of the file to its history, unless
+// the device already had that version. existing is the previous entry of
+// the device, if any.
+func (t readWriteTransaction) recordFileHistory(keyBuf, folder []byte, device protocol.DeviceID, existing protocol.FileInfo, hadExisting bool, f protocol.FileInfo) ([]byte, error) {
+	if f.IsInvalid() || hadExisting && existing.Version.Equal(f.Version) {
+		return keyBuf, nil
+	}
+
+	key, err := t.keyer.GenerateFileHistoryKey(keyBuf, folder, []byte(f.Name))
+	if err != nil {
+		return nil, err
+	}
+	hist, err := t.getFileHistoryWire(key)
+	if err != nil {
+		return nil, err
+	}
+	hist.Entries = append([]*dbproto.FileHistoryEntry{newFileHistoryEntryWire(device, f)}, hist.Entries...)
+	if len(hist.Entries) > maxFileHistory {
+		hist.Entries = hist.Entries[:maxFileHistory]
+	}
+	return key, t.Put(key, mustMarshal(hist))
+}
+
+// removeFileHistory removes the entries of the given device from the
+// history of every file in the folder, or the whole history of the folder
+// if device is nil.
+func (t readWriteTransaction) removeFileHistory(folder, device []byte) error {
+	key, err := t.keyer.GenerateFileHistoryKey(nil, folder, nil)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		return t.deleteKeyPrefix(key.WithoutName())
+	}
+
+	dbi, err := t.NewPrefixIterator(key.WithoutName())
+	if err != nil {
+		return err
+	}
+	defer dbi.Release()
+	for dbi.Next() {
+		var hist dbproto.FileHistory
+		if err := proto.Unmarshal(dbi.Value(), &hist); err != nil {
+			return err
+		}
+		kept := hist.Entries[:0]
+		for _, e := range hist.Entries {
+			if !bytes.Equal(e.Device, device) {
+				kept = append(kept, e)
+			}
+		}
+		switch {
+		case len(kept) == len(hist.Entries):
+			continue
+		case len(kept) == 0:
+			err = t.Delete(dbi.Key())
+		default:
+			hist.Entries = kept
+			err = t.Put(dbi.Key(), mustMarshal(&hist))
+		}
+		if err != nil {
+			return err
+		}
+		if err := t.Checkpoint(); err != nil {
+			return err
+		}
+	}
+	return dbi.Error()
+}
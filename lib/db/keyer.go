@@ -74,6 +74,15 @@ const (
 
 	// KeyTypePendingDevice <device ID in wire format> = ObservedDevice
 	KeyTypePendingDevice byte = 17
+
+	// KeyTypeWebhookDelivery <int64 sequence number> = webhook delivery
+	KeyTypeWebhookDelivery byte = 18
+
+	// KeyTypeFileHistory <int32 folder ID> <file name> = file history
+	KeyTypeFileHistory byte = 19
+
+	// KeyTypeTransferStatistic <period> <kind> <int64 period start> <device or folder ID as string> = int64 in, int64 out
+	KeyTypeTransferStatistic byte = 20
 )
 
 type keyer interface {
@@ -124,6 +133,9 @@ type keyer interface {
 
 	GeneratePendingDeviceKey(key, device []byte) pendingDeviceKey
 	DeviceFromPendingDeviceKey(key []byte) []byte
+
+	// Past versions of files
+	GenerateFileHistoryKey(key, folder, name []byte) (fileHistoryKey, error)
 }
 
 // defaultKeyer implements our key scheme. It needs folder and device
@@ -400,6 +412,24 @@ func (defaultKeyer) DeviceFromPendingDeviceKey(key []byte) []byte {
 	return key[keyPrefixLen:]
 }
 
+type fileHistoryKey []byte
+
+func (k fileHistoryKey) WithoutName() []byte {
+	return k[:keyPrefixLen+keyFolderLen]
+}
+
+func (k defaultKeyer) GenerateFileHistoryKey(key, folder, name []byte) (fileHistoryKey, error) {
+	folderID, err := k.folderIdx.ID(folder)
+	if err != nil {
+		return nil, err
+	}
+	key = resize(key, keyPrefixLen+keyFolderLen+len(name))
+	key[0] = KeyTypeFileHistory
+	binary.BigEndian.PutUint32(key[keyPrefixLen:], folderID)
+	copy(key[keyPrefixLen+keyFolderLen:], name)
+	return key, nil
+}
+
 // resize returns a byte slice of the specified size, reusing bs if possible
 func resize(bs []byte, size int) []byte {
 	if cap(bs) < size {
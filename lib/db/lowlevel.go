@@ -136,7 +136,7 @@ func (db *Lowlevel) ListFolders() []string {
 
 // updateRemoteFiles adds a list of fileinfos to the database and updates the
 // global versionlist and metadata.
-func (db *Lowlevel) updateRemoteFiles(folder, device []byte, fs []protocol.FileInfo, meta *metadataTracker) error {
+func (db *Lowlevel) updateRemoteFiles(folder, device []byte, fs []protocol.FileInfo, meta *metadataTracker, recordHistory bool) error {
 	db.gcMut.RLock()
 	defer db.gcMut.RUnlock()
 
@@ -172,6 +172,12 @@ func (db *Lowlevel) updateRemoteFiles(folder, device []byte, fs []protocol.FileI
 		if err := t.putFile(dk, f); err != nil {
 			return err
 		}
+		if recordHistory {
+			keyBuf, err = t.recordFileHistory(keyBuf, folder, devID, ef, ok, f)
+			if err != nil {
+				return err
+			}
+		}
 
 		gk, err = db.keyer.GenerateGlobalVersionKey(gk, folder, name)
 		if err != nil {
@@ -192,7 +198,7 @@ func (db *Lowlevel) updateRemoteFiles(folder, device []byte, fs []protocol.FileI
 
 // updateLocalFiles adds fileinfos to the db, and updates the global versionlist,
 // metadata, sequence and blockmap buckets.
-func (db *Lowlevel) updateLocalFiles(folder []byte, fs []protocol.FileInfo, meta *metadataTracker) error {
+func (db *Lowlevel) updateLocalFiles(folder []byte, fs []protocol.FileInfo, meta *metadataTracker, recordHistory bool) error {
 	db.gcMut.RLock()
 	defer db.gcMut.RUnlock()
 
@@ -235,6 +241,12 @@ func (db *Lowlevel) updateLocalFiles(folder []byte, fs []protocol.FileInfo, meta
 		if err := t.putFile(dk, f); err != nil {
 			return err
 		}
+		if recordHistory {
+			keyBuf, err = t.recordFileHistory(keyBuf, folder, protocol.LocalDeviceID, ef, ok, f)
+			if err != nil {
+				return err
+			}
+		}
 
 		gk, err = db.keyer.GenerateGlobalVersionKey(gk, folder, []byte(f.Name))
 		if err != nil {
@@ -437,6 +449,10 @@ func (db *Lowlevel) dropFolder(folder []byte) error {
 		return err
 	}
 
+	if err := t.removeFileHistory(folder, nil); err != nil {
+		return err
+	}
+
 	return t.Commit()
 }
 
@@ -483,6 +499,10 @@ func (db *Lowlevel) dropDeviceFolder(device, folder []byte, meta *metadataTracke
 		return err
 	}
 
+	if err := t.removeFileHistory(folder, device); err != nil {
+		return err
+	}
+
 	if bytes.Equal(device, protocol.LocalDeviceID[:]) {
 		key, err := db.keyer.GenerateBlockMapKey(nil, folder, nil, nil)
 		if err != nil {
@@ -236,7 +236,7 @@ func (db *schemaUpdater) migration17(prev int) error {
 			return err
 		}
 		batch := NewFileInfoBatch(func(fs []protocol.FileInfo) error {
-			return db.updateLocalFiles(folder, fs, meta)
+			return db.updateLocalFiles(folder, fs, meta, false)
 		})
 		var innerErr error
 		err = t.withHave(folder, protocol.LocalDeviceID[:], nil, false, func(fi protocol.FileInfo) bool {
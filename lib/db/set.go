@@ -29,7 +29,8 @@ type FileSet struct {
 	db     *Lowlevel
 	meta   *metadataTracker
 
-	updateMutex sync.Mutex // protects database updates and the corresponding metadata changes
+	updateMutex   sync.Mutex // protects database updates and the corresponding metadata changes
+	recordHistory bool       // protected by updateMutex
 }
 
 // The Iterator is called with either a protocol.FileInfo or a
@@ -113,6 +114,40 @@ func (s *FileSet) Drop(device protocol.DeviceID) {
 	}
 }
 
+// SetRecordHistory sets whether updates are recorded in the history of the
+// files. Turning it off removes the history recorded so far.
+func (s *FileSet) SetRecordHistory(record bool) {
+	opStr := fmt.Sprintf("%s SetRecordHistory(%v)", s.folder, record)
+	l.Debugf(opStr)
+
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	s.recordHistory = record
+	if record {
+		return
+	}
+
+	t, err := s.db.newReadWriteTransaction()
+	if backend.IsClosed(err) {
+		return
+	} else if err != nil {
+		fatalError(err, opStr, s.db)
+	}
+	defer t.close()
+
+	if err := t.removeFileHistory([]byte(s.folder), nil); backend.IsClosed(err) {
+		return
+	} else if err != nil {
+		fatalError(err, opStr, s.db)
+	}
+	if err := t.Commit(); backend.IsClosed(err) {
+		return
+	} else if err != nil {
+		fatalError(err, opStr, s.db)
+	}
+}
+
 func (s *FileSet) Update(device protocol.DeviceID, fs []protocol.FileInfo) {
 	opStr := fmt.Sprintf("%s Update(%v, [%d])", s.folder, device, len(fs))
 	l.Debugf(opStr)
@@ -133,13 +168,13 @@ func (s *FileSet) Update(device protocol.DeviceID, fs []protocol.FileInfo) {
 
 	if device == protocol.LocalDeviceID {
 		// For the local device we have a bunch of metadata to track.
-		if err := s.db.updateLocalFiles([]byte(s.folder), fs, s.meta); err != nil && !backend.IsClosed(err) {
+		if err := s.db.updateLocalFiles([]byte(s.folder), fs, s.meta, s.recordHistory); err != nil && !backend.IsClosed(err) {
 			fatalError(err, opStr, s.db)
 		}
 		return
 	}
 	// Easy case, just update the files and we're done.
-	if err := s.db.updateRemoteFiles([]byte(s.folder), device[:], fs, s.meta); err != nil && !backend.IsClosed(err) {
+	if err := s.db.updateRemoteFiles([]byte(s.folder), device[:], fs, s.meta, s.recordHistory); err != nil && !backend.IsClosed(err) {
 		fatalError(err, opStr, s.db)
 	}
 }
@@ -326,6 +361,26 @@ func (s *Snapshot) Availability(file string) []protocol.DeviceID {
 	return av
 }
 
+// FileHistory returns the recent changes to the file on all devices, newest
+// first.
+func (s *Snapshot) FileHistory(file string) []FileHistoryEntry {
+	opStr := fmt.Sprintf("%s FileHistory(%v)", s.folder, file)
+	l.Debugf(opStr)
+	key, err := s.t.keyer.GenerateFileHistoryKey(nil, []byte(s.folder), []byte(osutil.NormalizedFilename(file)))
+	if backend.IsClosed(err) {
+		return nil
+	} else if err != nil {
+		s.fatalError(err, opStr)
+	}
+	entries, err := s.t.getFileHistory(key)
+	if backend.IsClosed(err) {
+		return nil
+	} else if err != nil {
+		s.fatalError(err, opStr)
+	}
+	return entries
+}
+
 func (s *Snapshot) DebugGlobalVersions(file string) *DebugVersionList {
 	opStr := fmt.Sprintf("%s DebugGlobalVersions(%v)", s.folder, file)
 	l.Debugf(opStr)
@@ -1899,3 +1899,105 @@ func snapshot(t testing.TB, fset *db.FileSet) *db.Snapshot {
 	}
 	return snap
 }
+
+func TestFileHistory(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	s := newFileSet(t, "test", ldb)
+
+	v1 := protocol.Vector{}.Update(myID)
+	v2 := v1.Update(remoteDevice0.Short())
+	file := protocol.FileInfo{Name: "a", Version: v1, ModifiedBy: myID, Blocks: genBlocks(1)}
+
+	// Nothing is recorded until enabled.
+	s.Update(protocol.LocalDeviceID, []protocol.FileInfo{{Name: "b", Version: v1}})
+	s.SetRecordHistory(true)
+
+	s.Update(protocol.LocalDeviceID, []protocol.FileInfo{file})
+	// Same version again, e.g. after a rescan, isn't a change
+	s.Update(protocol.LocalDeviceID, []protocol.FileInfo{file})
+	s.Update(remoteDevice0, []protocol.FileInfo{file})
+
+	changed := protocol.FileInfo{Name: "a", Version: v2, ModifiedBy: remoteDevice0.Short(), Blocks: genBlocks(2)}
+	changed.BlocksHash = protocol.BlocksHash(changed.Blocks)
+	s.Update(remoteDevice0, []protocol.FileInfo{changed})
+	s.Update(protocol.LocalDeviceID, []protocol.FileInfo{changed})
+
+	snap := snapshot(t, s)
+	history := snap.FileHistory("a")
+	if len(history) != 4 {
+		t.Fatalf("expected four changes, got %d: %v", len(history), history)
+	}
+	expected := []struct {
+		device  protocol.DeviceID
+		version protocol.Vector
+	}{
+		{protocol.LocalDeviceID, v2},
+		{remoteDevice0, v2},
+		{remoteDevice0, v1},
+		{protocol.LocalDeviceID, v1},
+	}
+	for i, e := range expected {
+		h := history[i]
+		if h.Device != e.device || !h.Version.Equal(e.version) || h.Local != (e.device == protocol.LocalDeviceID) {
+			t.Errorf("entry %d: got %v %v, expected %v %v", i, h.Device, h.Version, e.device, e.version)
+		}
+	}
+	if h := history[0]; h.ModifiedBy != remoteDevice0.Short() || h.Size != changed.FileSize() || !bytes.Equal(h.BlocksHash, changed.BlocksHash) {
+		t.Error("unexpected latest entry", h)
+	}
+	if history := snap.FileHistory("b"); len(history) != 0 {
+		t.Error("expected no history while not recording, got", history)
+	}
+	snap.Release()
+
+	// Dropping a device removes its entries.
+	s.Drop(remoteDevice0)
+	snap = snapshot(t, s)
+	history = snap.FileHistory("a")
+	snap.Release()
+	if len(history) != 2 || history[0].Device != protocol.LocalDeviceID || history[1].Device != protocol.LocalDeviceID {
+		t.Fatalf("expected only the local changes after dropping the device, got %v", history)
+	}
+
+	// Turning recording off removes the history.
+	s.SetRecordHistory(false)
+	snap = snapshot(t, s)
+	defer snap.Release()
+	if history := snap.FileHistory("a"); len(history) != 0 {
+		t.Error("expected the history to be removed, got", history)
+	}
+}
+
+func TestFileHistoryBounded(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	s := newFileSet(t, "test", ldb)
+	s.SetRecordHistory(true)
+
+	var version protocol.Vector
+	for i := 0; i < 60; i++ {
+		version = version.Update(myID)
+		s.Update(protocol.LocalDeviceID, []protocol.FileInfo{{Name: "a", Version: version}})
+	}
+
+	snap := snapshot(t, s)
+	history := snap.FileHistory("a")
+	snap.Release()
+	if len(history) != 50 {
+		t.Fatal("expected the history to be bounded, got", len(history))
+	}
+	if !history[0].Version.Equal(version) {
+		t.Error("expected the latest version first, got", history[0].Version)
+	}
+
+	db.DropFolder(ldb, "test")
+	s = newFileSet(t, "test", ldb)
+	snap = snapshot(t, s)
+	defer snap.Release()
+	if history := snap.FileHistory("a"); len(history) != 0 {
+		t.Error("expected the history to be dropped with the folder, got", len(history))
+	}
+}
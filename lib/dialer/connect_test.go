@@ -0,0 +1,106 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package dialer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/proxy"
+)
+
+func TestConnectDialer(t *testing.T) {
+	t.Parallel()
+
+	echo := listen(t, func(conn net.Conn) {
+		_, _ = io.Copy(conn, conn)
+	})
+	httpProxy := listen(t, func(conn net.Conn) {
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		if req.Method != http.MethodConnect || req.Host != echo {
+			_, _ = io.WriteString(conn, "HTTP/1.1 400 Bad Request\r\n\r\n")
+			return
+		}
+		if req.Header.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNz" { // user:pass
+			_, _ = io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+			return
+		}
+		target, err := net.Dial("tcp", echo)
+		if err != nil {
+			return
+		}
+		defer target.Close()
+		_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() { _, _ = io.Copy(target, conn) }()
+		_, _ = io.Copy(conn, target)
+	})
+
+	dial := func(proxyURL string) (net.Conn, error) {
+		t.Helper()
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := proxy.FromURL(u, proxy.Direct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d.(proxy.ContextDialer).DialContext(context.Background(), "tcp", echo)
+	}
+
+	conn, err := dial("http://user:pass@" + httpProxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "hello" {
+		t.Errorf("got %q through the proxy, expected hello", buf)
+	}
+
+	if _, err := dial("http://user:wrong@" + httpProxy); err == nil {
+		t.Error("expected an error for rejected credentials")
+	}
+}
+
+// listen serves each connection to a local listener with handle, and
+// returns the address of the listener.
+func listen(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
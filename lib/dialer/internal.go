@@ -20,6 +20,8 @@ var noFallback = os.Getenv("ALL_PROXY_NO_FALLBACK") != ""
 
 func init() {
 	proxy.RegisterDialerType("socks", socksDialerFunction)
+	proxy.RegisterDialerType("http", connectDialerFunction)
+	proxy.RegisterDialerType("https", connectDialerFunction)
 
 	if proxyDialer := proxy.FromEnvironment(); proxyDialer != proxy.Direct {
 		http.DefaultTransport = &http.Transport{
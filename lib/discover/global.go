@@ -69,6 +69,7 @@ type serverOptions struct {
 	insecure   bool   // don't check certificate
 	noAnnounce bool   // don't announce
 	noLookup   bool   // don't use for lookups
+	authLookup bool   // present our certificate on lookups
 	id         string // expected server device ID
 }
 
@@ -126,7 +127,12 @@ func NewGlobal(server string, cert tls.Certificate, addrList AddressLister, evLo
 	}
 
 	// The http.Client used for queries. We don't need to present our
-	// certificate here, so lets not include it. May be insecure if requested.
+	// certificate here, so lets not include it, unless the server requires
+	// it to answer. May be insecure if requested.
+	var queryCerts []tls.Certificate
+	if opts.authLookup {
+		queryCerts = []tls.Certificate{cert}
+	}
 	var queryClient httpClient = &contextClient{&http.Client{
 		Timeout: requestTimeout,
 		Transport: http2EnabledTransport(&http.Transport{
@@ -135,6 +141,7 @@ func NewGlobal(server string, cert tls.Certificate, addrList AddressLister, evLo
 			IdleConnTimeout: time.Second,
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: opts.insecure,
+				Certificates:       queryCerts,
 				MinVersion:         tls.VersionTLS12,
 				ClientSessionCache: tls.NewLRUClientSessionCache(0),
 			},
@@ -338,6 +345,7 @@ func parseOptions(dsn string) (server string, opts serverOptions, err error) {
 	opts.insecure = opts.id != "" || queryBool(q, "insecure")
 	opts.noAnnounce = queryBool(q, "noannounce")
 	opts.noLookup = queryBool(q, "nolookup")
+	opts.authLookup = queryBool(q, "authlookup")
 
 	// Check for disallowed combinations
 	if p.Scheme == "http" {
@@ -58,6 +58,7 @@ const (
 	ListenAddressesChanged
 	LoginAttempt
 	Failure
+	MassChangeDetected
 
 	AllEvents = (1 << iota) - 1
 )
@@ -135,6 +136,8 @@ func (t EventType) String() string {
 		return "FolderWatchStateChanged"
 	case Failure:
 		return "Failure"
+	case MassChangeDetected:
+		return "MassChangeDetected"
 	default:
 		return "Unknown"
 	}
@@ -222,6 +225,8 @@ func UnmarshalEventType(s string) EventType {
 		return FolderWatchStateChanged
 	case "Failure":
 		return Failure
+	case "MassChangeDetected":
+		return MassChangeDetected
 	default:
 		return 0
 	}
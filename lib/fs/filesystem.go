@@ -252,6 +252,8 @@ func NewFilesystem(fsType FilesystemType, uri string, opts ...Option) Filesystem
 		fs = newBasicFilesystem(uri, opts...)
 	case FilesystemTypeFake:
 		fs = newFakeFilesystem(uri, opts...)
+	case FilesystemTypeS3:
+		fs = newS3Filesystem(uri, opts...)
 	default:
 		l.Debugln("Unknown filesystem", fsType, uri)
 		fs = &errorFilesystem{
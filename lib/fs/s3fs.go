@@ -0,0 +1,815 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/s3"
+)
+
+const (
+	s3MetaMtime = "mtime" // modification time, in nanoseconds since the epoch
+	s3MetaMode  = "mode"  // permission bits, octal
+
+	s3DefaultFileMode = 0o644
+	s3DefaultDirMode  = 0o755
+)
+
+var (
+	errS3NotSupported      = errors.New("not supported by the S3 filesystem")
+	errS3CredentialsInPath = errors.New("S3 credentials must be given in the environment or the shared credentials file, not in the folder path")
+	errNotADirectory       = errors.New("not a directory")
+	errIsADirectory        = errors.New("is a directory")
+	errDirNotEmpty         = errors.New("directory not empty")
+)
+
+// s3FS stores files as objects in an S3 compatible bucket. It has the
+// following properties:
+//
+//   - The folder root is a bucket, optionally with a key prefix. A file
+//     "a/b/c" is stored as the object "<prefix>/a/b/c".
+//
+//   - Directories are key prefixes. They are made explicit by a zero length
+//     marker object with a trailing slash ("<prefix>/a/b/"), which also
+//     carries their metadata, but any prefix that has objects under it is
+//     considered a directory.
+//
+//   - Modification times and permission bits are kept in object metadata.
+//     Ownership, extended attributes and symlinks are not supported.
+//
+//   - Files opened for writing are spooled to a local temporary file and
+//     uploaded on Sync or Close. Files opened for reading are read with
+//     ranged requests.
+//
+//   - Rename is emulated by a server side copy followed by a delete, and is
+//     hence not atomic. Chmod and Chtimes copy the object onto itself to
+//     replace its metadata.
+//
+// The root URI has the form
+//
+//	[s3://]bucket[/prefix][?parameters]
+//
+// with the following optional parameters:
+//
+//	endpoint=url      the S3 service to use, e.g. "http://localhost:9000"
+//	region=name       the region of the bucket (default "us-east-1")
+//	profile=name      the profile of the shared AWS credentials file to use
+//
+// The credentials come from the default AWS credential chain: the
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables, the
+// shared credentials file, and so on. They are never taken from the URI, as
+// folder paths are shown and logged in many places.
+type s3FS struct {
+	sess   *s3.Session
+	bucket string
+	prefix string // with trailing slash, or empty for the bucket root
+}
+
+func newS3Filesystem(rootURI string, _ ...Option) Filesystem {
+	uri, err := url.Parse(strings.TrimPrefix(rootURI, "s3://"))
+	if err != nil {
+		return &errorFilesystem{fsType: FilesystemTypeS3, uri: rootURI, err: err}
+	}
+	params := uri.Query()
+	if params.Has("accessKeyID") || params.Has("secretKey") {
+		return &errorFilesystem{fsType: FilesystemTypeS3, uri: rootURI, err: errS3CredentialsInPath}
+	}
+
+	bucket, prefix, _ := strings.Cut(strings.Trim(uri.Path, "/"), "/")
+	if bucket == "" {
+		return &errorFilesystem{fsType: FilesystemTypeS3, uri: rootURI, err: errors.New("missing bucket name")}
+	}
+	if prefix != "" {
+		prefix = path.Clean(prefix) + "/"
+	}
+
+	region := params.Get("region")
+	if region == "" {
+		region = "us-east-1"
+	}
+	sess, err := s3.NewPathStyleSession(params.Get("endpoint"), region, bucket, params.Get("profile"))
+	if err != nil {
+		return &errorFilesystem{fsType: FilesystemTypeS3, uri: rootURI, err: err}
+	}
+
+	return &s3FS{
+		sess:   sess,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// key returns the object key for the given name, without trailing slash.
+// The root directory maps to the prefix without the trailing slash, which
+// is the empty string when the folder is the whole bucket.
+func (f *s3FS) key(name string) string {
+	name = strings.Trim(filepath.ToSlash(name), "/")
+	if name == "." {
+		name = ""
+	}
+	return strings.TrimSuffix(f.prefix+name, "/")
+}
+
+// dirPrefix returns the key prefix under which the contents of the
+// directory with the given key are stored.
+func dirPrefix(key string) string {
+	if key == "" {
+		return ""
+	}
+	return key + "/"
+}
+
+func (f *s3FS) Chmod(name string, mode FileMode) error {
+	return f.updateMetadata(name, func(md map[string]string) {
+		md[s3MetaMode] = strconv.FormatUint(uint64(mode&ModePerm), 8)
+	})
+}
+
+func (*s3FS) Lchown(_, _, _ string) error {
+	return nil
+}
+
+func (f *s3FS) Chtimes(name string, _ time.Time, mtime time.Time) error {
+	return f.updateMetadata(name, func(md map[string]string) {
+		md[s3MetaMtime] = strconv.FormatInt(mtime.UnixNano(), 10)
+	})
+}
+
+// updateMetadata replaces the metadata of the object, or directory marker,
+// for the given name with the result of calling fn on the current metadata.
+func (f *s3FS) updateMetadata(name string, fn func(map[string]string)) error {
+	key := f.key(name)
+	if key == "" {
+		// There is nowhere to store metadata for the bucket itself.
+		return nil
+	}
+
+	if info, err := f.sess.Head(key); err == nil {
+		fn(info.Metadata)
+		return f.sess.Copy(key, key, info.Metadata)
+	} else if !IsNotExist(err) {
+		return err
+	}
+
+	// It's not a file, so try it as a directory.
+	fi, err := f.Lstat(name)
+	if err != nil {
+		return err
+	}
+	md := s3Metadata(fi.Mode(), fi.ModTime())
+	fn(md)
+	return f.sess.Put(strings.NewReader(""), dirPrefix(key), md)
+}
+
+func (f *s3FS) Create(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, s3DefaultFileMode)
+}
+
+func (*s3FS) CreateSymlink(_, _ string) error {
+	return errS3NotSupported
+}
+
+func (f *s3FS) DirNames(name string) ([]string, error) {
+	key := f.key(name)
+	prefix := dirPrefix(key)
+
+	var names []string
+	err := f.sess.ListPrefix(prefix, "/", func(key string, _ bool) bool {
+		child := strings.TrimSuffix(strings.TrimPrefix(key, prefix), "/")
+		if child != "" {
+			names = append(names, child)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		// Could be an empty directory, or not a directory at all.
+		fi, err := f.Lstat(name)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			return nil, errNotADirectory
+		}
+	}
+
+	// Files and subdirectories are listed separately, each in order.
+	slices.Sort(names)
+	return names, nil
+}
+
+func (f *s3FS) Lstat(name string) (FileInfo, error) {
+	key := f.key(name)
+	base := filepath.Base(name)
+	if key == "" {
+		// The bucket itself is always there, if we can reach it at all.
+		return &s3FileInfo{name: base, mode: s3DefaultDirMode, isDir: true}, nil
+	}
+
+	info, err := f.sess.Head(key)
+	if err == nil {
+		return s3FileInfoFromObject(base, info, false), nil
+	} else if !IsNotExist(err) {
+		return nil, err
+	}
+
+	info, err = f.sess.Head(dirPrefix(key))
+	if err == nil {
+		return s3FileInfoFromObject(base, info, true), nil
+	} else if !IsNotExist(err) {
+		return nil, err
+	}
+
+	// No marker, but the directory exists implicitly if anything is
+	// stored under it.
+	found := false
+	if err := f.sess.ListPrefix(dirPrefix(key), "", func(string, bool) bool {
+		found = true
+		return false
+	}); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotExist
+	}
+	return &s3FileInfo{name: base, mode: s3DefaultDirMode, isDir: true}, nil
+}
+
+func (f *s3FS) Mkdir(name string, perm FileMode) error {
+	parent, err := f.Lstat(filepath.Dir(name))
+	if err != nil {
+		return err
+	}
+	if !parent.IsDir() {
+		return errNotADirectory
+	}
+	if _, err := f.Lstat(name); err == nil {
+		return ErrExist
+	} else if !IsNotExist(err) {
+		return err
+	}
+	return f.sess.Put(strings.NewReader(""), dirPrefix(f.key(name)), s3Metadata(perm, time.Now()))
+}
+
+func (f *s3FS) MkdirAll(name string, perm FileMode) error {
+	name = strings.Trim(filepath.ToSlash(name), "/")
+	comps := []string{"."}
+	if name != "." && name != "" {
+		comps = strings.Split(name, "/")
+	}
+
+	for i := range comps {
+		dir := filepath.Join(comps[:i+1]...)
+		fi, err := f.Lstat(dir)
+		if err == nil {
+			if !fi.IsDir() {
+				return errNotADirectory
+			}
+			continue
+		} else if !IsNotExist(err) {
+			return err
+		}
+		if err := f.sess.Put(strings.NewReader(""), dirPrefix(f.key(dir)), s3Metadata(perm, time.Now())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *s3FS) Open(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (f *s3FS) OpenFile(name string, flags int, mode FileMode) (File, error) {
+	key := f.key(name)
+	fi, err := f.Lstat(name)
+	switch {
+	case err == nil && flags&os.O_CREATE != 0 && flags&os.O_EXCL != 0:
+		return nil, ErrExist
+	case err == nil && fi.IsDir():
+		if flags&(os.O_WRONLY|os.O_RDWR) != 0 {
+			return nil, errIsADirectory
+		}
+		return &s3File{fs: f, name: name, key: key, info: fi.(*s3FileInfo)}, nil
+	case err != nil && !IsNotExist(err):
+		return nil, err
+	case err != nil && flags&os.O_CREATE == 0:
+		return nil, err
+	case err != nil:
+		parent, err := f.Lstat(filepath.Dir(name))
+		if err != nil {
+			return nil, err
+		}
+		if !parent.IsDir() {
+			return nil, errNotADirectory
+		}
+		fi = &s3FileInfo{name: filepath.Base(name), mode: mode & ModePerm}
+	}
+
+	file := &s3File{fs: f, name: name, key: key, info: fi.(*s3FileInfo)}
+	if flags&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return file, nil
+	}
+
+	// Writable files are spooled locally and uploaded when done.
+	spool, err := os.CreateTemp("", "syncthing-s3-*")
+	if err != nil {
+		return nil, err
+	}
+	file.spool = spool
+	switch {
+	case file.info.size == 0 || flags&os.O_TRUNC != 0:
+		file.info.size = 0
+		file.dirty = true // needs to exist after close, even if empty
+	default:
+		if err := file.download(); err != nil {
+			file.discardSpool()
+			return nil, err
+		}
+	}
+	if flags&os.O_APPEND != 0 {
+		file.offset = file.info.size
+	}
+	return file, nil
+}
+
+func (*s3FS) ReadSymlink(_ string) (string, error) {
+	return "", errS3NotSupported
+}
+
+func (f *s3FS) Remove(name string) error {
+	fi, err := f.Lstat(name)
+	if err != nil {
+		return err
+	}
+	key := f.key(name)
+	if !fi.IsDir() {
+		return f.sess.Delete(key)
+	}
+
+	prefix := dirPrefix(key)
+	empty := true
+	if err := f.sess.ListPrefix(prefix, "", func(k string, _ bool) bool {
+		if k != prefix {
+			empty = false
+			return false
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+	if !empty {
+		return errDirNotEmpty
+	}
+	return f.sess.Delete(prefix)
+}
+
+func (f *s3FS) RemoveAll(name string) error {
+	key := f.key(name)
+	var keys []string
+	if key != "" {
+		keys = append(keys, key)
+	}
+	if err := f.sess.ListPrefix(dirPrefix(key), "", func(k string, _ bool) bool {
+		keys = append(keys, k)
+		return true
+	}); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := f.sess.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *s3FS) Rename(oldname, newname string) error {
+	src, err := f.Lstat(oldname)
+	if err != nil {
+		return err
+	}
+	if dst, err := f.Lstat(newname); err == nil && dst.IsDir() {
+		return errIsADirectory
+	} else if err != nil && !IsNotExist(err) {
+		return err
+	}
+
+	oldKey, newKey := f.key(oldname), f.key(newname)
+	if oldKey == newKey {
+		return nil
+	}
+	if !src.IsDir() {
+		if err := f.sess.Copy(oldKey, newKey, nil); err != nil {
+			return err
+		}
+		return f.sess.Delete(oldKey)
+	}
+
+	// Directories are renamed by moving every object below them,
+	// including the marker object, if any.
+	var keys []string
+	if err := f.sess.ListPrefix(dirPrefix(oldKey), "", func(k string, _ bool) bool {
+		keys = append(keys, k)
+		return true
+	}); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := f.sess.Copy(k, newKey+strings.TrimPrefix(k, oldKey), nil); err != nil {
+			return err
+		}
+	}
+	for _, k := range keys {
+		if err := f.sess.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *s3FS) Stat(name string) (FileInfo, error) {
+	return f.Lstat(name)
+}
+
+func (*s3FS) SymlinksSupported() bool {
+	return false
+}
+
+func (*s3FS) Walk(_ string, _ WalkFunc) error {
+	return errors.New("not implemented")
+}
+
+func (*s3FS) Watch(_ string, _ Matcher, _ context.Context, _ bool) (<-chan Event, <-chan error, error) {
+	return nil, nil, ErrWatchNotSupported
+}
+
+func (*s3FS) Hide(_ string) error {
+	return nil
+}
+
+func (*s3FS) Unhide(_ string) error {
+	return nil
+}
+
+func (f *s3FS) Glob(pattern string) ([]string, error) {
+	dir := filepath.Dir(pattern)
+	file := filepath.Base(pattern)
+
+	names, err := f.DirNames(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, n := range names {
+		matched, err := filepath.Match(file, n)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, filepath.Join(dir, n))
+		}
+	}
+	return matches, nil
+}
+
+func (*s3FS) Roots() ([]string, error) {
+	return []string{"/"}, nil
+}
+
+func (*s3FS) Usage(_ string) (Usage, error) {
+	return Usage{}, errS3NotSupported
+}
+
+func (*s3FS) Type() FilesystemType {
+	return FilesystemTypeS3
+}
+
+func (f *s3FS) URI() string {
+	return "s3://" + f.bucket + "/" + f.prefix
+}
+
+func (*s3FS) Options() []Option {
+	return nil
+}
+
+func (*s3FS) SameFile(fi1, fi2 FileInfo) bool {
+	return fi1.Name() == fi2.Name() && fi1.Size() == fi2.Size() && fi1.ModTime().Equal(fi2.ModTime()) && fi1.IsDir() == fi2.IsDir()
+}
+
+func (*s3FS) PlatformData(_ string, _, _ bool, _ XattrFilter) (protocol.PlatformData, error) {
+	return protocol.PlatformData{}, nil
+}
+
+func (*s3FS) GetXattr(_ string, _ XattrFilter) ([]protocol.Xattr, error) {
+	return nil, ErrXattrsNotSupported
+}
+
+func (*s3FS) SetXattr(_ string, _ []protocol.Xattr, _ XattrFilter) error {
+	return ErrXattrsNotSupported
+}
+
+func (*s3FS) underlying() (Filesystem, bool) {
+	return nil, false
+}
+
+func (*s3FS) wrapperType() filesystemWrapperType {
+	return filesystemWrapperTypeNone
+}
+
+func s3Metadata(mode FileMode, mtime time.Time) map[string]string {
+	return map[string]string{
+		s3MetaMode:  strconv.FormatUint(uint64(mode&ModePerm), 8),
+		s3MetaMtime: strconv.FormatInt(mtime.UnixNano(), 10),
+	}
+}
+
+// s3File is an open file. Files opened read only are read directly from
+// the bucket, writable ones have a local spool file.
+type s3File struct {
+	fs   *s3FS
+	name string
+	key  string
+
+	mut    sync.Mutex
+	info   *s3FileInfo
+	offset int64
+	spool  *os.File
+	dirty  bool
+}
+
+func (f *s3File) download() error {
+	r, err := f.fs.sess.GetRange(f.key, 0, f.info.size)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if _, err := io.Copy(f.spool, r); err != nil {
+		return err
+	}
+	_, err = f.spool.Seek(0, io.SeekStart)
+	return err
+}
+
+// upload stores the spool file contents as the object, if it has changed.
+// Must be called with the lock held.
+func (f *s3File) upload() error {
+	if f.spool == nil || !f.dirty {
+		return nil
+	}
+	size, err := f.spool.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := f.spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	f.info.size = size
+	f.info.mtime = time.Now()
+	if err := f.fs.sess.Put(io.LimitReader(f.spool, size), f.key, s3Metadata(f.info.mode, f.info.mtime)); err != nil {
+		return err
+	}
+	f.dirty = false
+	return nil
+}
+
+func (f *s3File) discardSpool() {
+	f.spool.Close()
+	os.Remove(f.spool.Name())
+	f.spool = nil
+}
+
+func (f *s3File) Close() error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if f.spool == nil {
+		return nil
+	}
+	err := f.upload()
+	f.discardSpool()
+	return err
+}
+
+func (f *s3File) Read(p []byte) (int, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	n, err := f.readAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *s3File) ReadAt(p []byte, offs int64) (int, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.readAt(p, offs)
+}
+
+func (f *s3File) readAt(p []byte, offs int64) (int, error) {
+	if f.info.isDir {
+		return 0, errIsADirectory
+	}
+	if f.spool != nil {
+		return f.spool.ReadAt(p, offs)
+	}
+	if offs >= f.info.size {
+		return 0, io.EOF
+	}
+	length := int64(len(p))
+	if offs+length > f.info.size {
+		length = f.info.size - offs
+	}
+	r, err := f.fs.sess.GetRange(f.key, offs, length)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	n, err := io.ReadFull(r, p[:length])
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (f *s3File) Seek(offset int64, whence int) (int64, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if f.info.isDir {
+		return 0, errIsADirectory
+	}
+
+	size := f.info.size
+	if f.spool != nil {
+		fi, err := f.spool.Stat()
+		if err != nil {
+			return 0, err
+		}
+		size = fi.Size()
+	}
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("seek before start")
+	}
+	f.offset = offset
+	return f.offset, nil
+}
+
+func (f *s3File) Write(p []byte) (int, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	n, err := f.writeAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *s3File) WriteAt(p []byte, off int64) (int, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.writeAt(p, off)
+}
+
+func (f *s3File) writeAt(p []byte, off int64) (int, error) {
+	if f.spool == nil {
+		return 0, fmt.Errorf("%s: file not open for writing", f.name)
+	}
+	f.dirty = true
+	return f.spool.WriteAt(p, off)
+}
+
+func (f *s3File) Name() string {
+	return f.name
+}
+
+func (f *s3File) Truncate(size int64) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if f.spool == nil {
+		return fmt.Errorf("%s: file not open for writing", f.name)
+	}
+	f.dirty = true
+	return f.spool.Truncate(size)
+}
+
+func (f *s3File) Stat() (FileInfo, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	info := *f.info
+	if f.spool != nil {
+		fi, err := f.spool.Stat()
+		if err != nil {
+			return nil, err
+		}
+		info.size = fi.Size()
+	}
+	return &info, nil
+}
+
+func (f *s3File) Sync() error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.upload()
+}
+
+// s3FileInfo is the stat result.
+type s3FileInfo struct {
+	name  string
+	size  int64
+	mode  FileMode
+	mtime time.Time
+	isDir bool
+}
+
+func s3FileInfoFromObject(name string, info s3.ObjectInfo, isDir bool) *s3FileInfo {
+	fi := &s3FileInfo{
+		name:  name,
+		mtime: info.LastModified,
+		mode:  s3DefaultFileMode,
+		isDir: isDir,
+	}
+	if isDir {
+		fi.mode = s3DefaultDirMode
+	} else {
+		fi.size = info.Size
+	}
+	if mode, err := strconv.ParseUint(info.Metadata[s3MetaMode], 8, 32); err == nil {
+		fi.mode = FileMode(mode) & ModePerm
+	}
+	if nanos, err := strconv.ParseInt(info.Metadata[s3MetaMtime], 10, 64); err == nil {
+		fi.mtime = time.Unix(0, nanos)
+	}
+	return fi
+}
+
+func (f *s3FileInfo) Name() string {
+	return f.name
+}
+
+func (f *s3FileInfo) Mode() FileMode {
+	return f.mode
+}
+
+func (f *s3FileInfo) Size() int64 {
+	return f.size
+}
+
+func (f *s3FileInfo) ModTime() time.Time {
+	return f.mtime
+}
+
+func (f *s3FileInfo) IsDir() bool {
+	return f.isDir
+}
+
+func (*s3FileInfo) Sys() interface{} {
+	return nil
+}
+
+func (f *s3FileInfo) IsRegular() bool {
+	return !f.isDir
+}
+
+func (*s3FileInfo) IsSymlink() bool {
+	return false
+}
+
+func (*s3FileInfo) Owner() int {
+	return -1
+}
+
+func (*s3FileInfo) Group() int {
+	return -1
+}
+
+func (*s3FileInfo) InodeChangeTime() time.Time {
+	return time.Time{}
+}
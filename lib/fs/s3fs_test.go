@@ -0,0 +1,412 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package fs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3Server is an in-process stand-in for an S3 service, supporting
+// the path style subset of the API used by the S3 filesystem for a single
+// bucket.
+type fakeS3Server struct {
+	mut     sync.Mutex
+	objects map[string]fakeS3Object
+}
+
+type fakeS3Object struct {
+	data     []byte
+	meta     map[string]string
+	modified time.Time
+}
+
+func newFakeS3Server(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(&fakeS3Server{objects: make(map[string]fakeS3Object)})
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (s *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	_, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		s.list(w, r)
+
+	case r.Method == http.MethodHead, r.Method == http.MethodGet:
+		obj, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		for k, v := range obj.meta {
+			w.Header().Set("X-Amz-Meta-"+k, v)
+		}
+		w.Header().Set("Last-Modified", obj.modified.UTC().Format(http.TimeFormat))
+		data := obj.data
+		status := http.StatusOK
+		if rng := r.Header.Get("Range"); rng != "" && r.Method == http.MethodGet {
+			var start, end int
+			if _, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			end = min(end+1, len(data))
+			data = data[start:end]
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(obj.data)))
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		src, _ := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+		_, srcKey, _ := strings.Cut(strings.TrimPrefix(src, "/"), "/")
+		obj, ok := s.objects[srcKey]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+			obj.meta = requestMetadata(r)
+		}
+		obj.modified = time.Now()
+		s.objects[key] = obj
+		fmt.Fprintf(w, "<CopyObjectResult><LastModified>%s</LastModified></CopyObjectResult>", obj.modified.UTC().Format(time.RFC3339))
+
+	case r.Method == http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.objects[key] = fakeS3Object{data: data, meta: requestMetadata(r), modified: time.Now()}
+
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "not implemented", http.StatusNotImplemented)
+	}
+}
+
+func (s *fakeS3Server) list(w http.ResponseWriter, r *http.Request) {
+	type content struct {
+		Key  string
+		Size int
+	}
+	type commonPrefix struct {
+		Prefix string
+	}
+	type result struct {
+		XMLName        xml.Name `xml:"ListBucketResult"`
+		KeyCount       int
+		IsTruncated    bool
+		Contents       []content
+		CommonPrefixes []commonPrefix
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	delimiter := r.URL.Query().Get("delimiter")
+
+	var res result
+	seenPrefixes := make(map[string]bool)
+	for key, obj := range s.objects {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		if delimiter != "" {
+			if idx := strings.Index(rest, delimiter); idx >= 0 {
+				cp := prefix + rest[:idx+len(delimiter)]
+				if !seenPrefixes[cp] {
+					seenPrefixes[cp] = true
+					res.CommonPrefixes = append(res.CommonPrefixes, commonPrefix{cp})
+				}
+				continue
+			}
+		}
+		res.Contents = append(res.Contents, content{key, len(obj.data)})
+	}
+	sort.Slice(res.Contents, func(a, b int) bool { return res.Contents[a].Key < res.Contents[b].Key })
+	sort.Slice(res.CommonPrefixes, func(a, b int) bool { return res.CommonPrefixes[a].Prefix < res.CommonPrefixes[b].Prefix })
+	res.KeyCount = len(res.Contents) + len(res.CommonPrefixes)
+
+	xml.NewEncoder(w).Encode(res)
+}
+
+func requestMetadata(r *http.Request) map[string]string {
+	meta := make(map[string]string)
+	for k := range r.Header {
+		if name, ok := strings.CutPrefix(k, "X-Amz-Meta-"); ok {
+			meta[name] = r.Header.Get(k)
+		}
+	}
+	return meta
+}
+
+func newTestS3Filesystem(t *testing.T, prefix string) Filesystem {
+	t.Helper()
+	srv := newFakeS3Server(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "id")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	uri := fmt.Sprintf("s3://bucket/%s?endpoint=%s", prefix, url.QueryEscape(srv.URL))
+	return NewFilesystem(FilesystemTypeS3, uri)
+}
+
+func TestS3FSCredentialsInPath(t *testing.T) {
+	fs := NewFilesystem(FilesystemTypeS3, "s3://bucket?endpoint=http%3A%2F%2Flocalhost&accessKeyID=id&secretKey=secret")
+	if _, err := fs.Lstat("."); !errors.Is(err, errS3CredentialsInPath) {
+		t.Error("expected credentials in the path to be refused, got", err)
+	}
+}
+
+func TestS3FS(t *testing.T) {
+	for _, prefix := range []string{"", "some/prefix"} {
+		t.Run(fmt.Sprintf("prefix=%q", prefix), func(t *testing.T) {
+			testS3FS(t, newTestS3Filesystem(t, prefix))
+		})
+	}
+}
+
+func testS3FS(t *testing.T, fs Filesystem) {
+	if fs.Type() != FilesystemTypeS3 {
+		t.Fatal("unexpected type", fs.Type())
+	}
+
+	if err := fs.MkdirAll("dira/dirb", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Mkdir("dira/dirb/dirc", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Mkdir("dira/dirb/dirc", 0o700); !IsExist(err) {
+		t.Fatal("expected exist error, got", err)
+	}
+	if err := fs.Mkdir("missing/dir", 0o700); !IsNotExist(err) {
+		t.Fatal("expected not exist error, got", err)
+	}
+	if info, err := fs.Stat("dira/dirb/dirc"); err != nil {
+		t.Fatal(err)
+	} else if !info.IsDir() || info.Mode() != 0o700 {
+		t.Fatalf("unexpected dir info: dir=%v mode=%v", info.IsDir(), info.Mode())
+	}
+
+	// Write a file in a couple of steps and read it back
+
+	fd, err := fs.Create("dira/dirb/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.WriteAt([]byte(", world"), 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := fd.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := fs.Lstat("dira/dirb/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 12 || !info.IsRegular() || info.Name() != "test" {
+		t.Fatalf("unexpected file info: size=%d regular=%v name=%q", info.Size(), info.IsRegular(), info.Name())
+	}
+
+	fd, err = fs.Open("dira/dirb/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello, world" {
+		t.Fatalf("unexpected contents %q", data)
+	}
+	buf := make([]byte, 10)
+	if n, err := fd.ReadAt(buf, 7); err != io.EOF || string(buf[:n]) != "world" {
+		t.Fatalf("unexpected ReadAt result %q, %v", buf[:n], err)
+	}
+	fd.Close()
+
+	// Appending keeps the existing contents
+
+	fd, err = fs.OpenFile("dira/dirb/test", OptWriteOnly|OptAppend, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.Write([]byte("!")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fd.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := fs.Lstat("dira/dirb/test"); err != nil {
+		t.Fatal(err)
+	} else if info.Size() != 13 {
+		t.Fatal("unexpected size after append", info.Size())
+	}
+
+	// Metadata
+
+	mtime := time.Date(2020, 4, 5, 6, 7, 8, 9, time.UTC)
+	if err := fs.Chtimes("dira/dirb/test", mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Chmod("dira/dirb/test", 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Chtimes("dira", mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"dira/dirb/test", "dira"} {
+		info, err := fs.Lstat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("%s: unexpected mtime %v", name, info.ModTime())
+		}
+	}
+	if info, err := fs.Lstat("dira/dirb/test"); err != nil {
+		t.Fatal(err)
+	} else if info.Mode() != 0o600 {
+		t.Error("unexpected mode", info.Mode())
+	}
+
+	// Listing
+
+	if err := WriteFile(fs, "dira/dirb/other", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	names, err := fs.DirNames("dira/dirb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"dirc", "other", "test"}) {
+		t.Fatal("unexpected dir names", names)
+	}
+	if _, err := fs.DirNames("nonexistent"); !IsNotExist(err) {
+		t.Fatal("expected not exist error, got", err)
+	}
+	matches, err := fs.Glob("dira/dirb/t*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(matches, []string{"dira/dirb/test"}) {
+		t.Fatal("unexpected glob matches", matches)
+	}
+
+	// Renames preserve contents and metadata
+
+	if err := fs.Rename("dira/dirb/test", "dira/renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Lstat("dira/dirb/test"); !IsNotExist(err) {
+		t.Fatal("expected old name to be gone, got", err)
+	}
+	if info, err := fs.Lstat("dira/renamed"); err != nil {
+		t.Fatal(err)
+	} else if !info.ModTime().Equal(mtime) || info.Size() != 13 {
+		t.Fatal("unexpected renamed file info", info.ModTime(), info.Size())
+	}
+	if err := fs.Rename("dira/dirb", "dirx"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := fs.Lstat("dirx/dirc"); err != nil {
+		t.Fatal(err)
+	} else if !info.IsDir() {
+		t.Fatal("expected directory")
+	}
+	if _, err := fs.Lstat("dirx/other"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Walking is provided by the walkfs wrapper
+
+	var walked []string
+	if err := fs.Walk(".", func(path string, _ FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, path)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{".", "dira", "dira/renamed", "dirx", "dirx/dirc", "dirx/other"}
+	if !slices.Equal(walked, expected) {
+		t.Fatal("unexpected walk result", walked)
+	}
+
+	// Removal
+
+	if err := fs.Remove("dirx"); err == nil {
+		t.Fatal("expected error removing non-empty directory")
+	}
+	if err := fs.Remove("dirx/dirc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.RemoveAll("dirx"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Lstat("dirx"); !IsNotExist(err) {
+		t.Fatal("expected removed directory to be gone, got", err)
+	}
+	if err := fs.Remove("dirx"); !IsNotExist(err) {
+		t.Fatal("expected not exist error, got", err)
+	}
+}
+
+func TestS3FSLargeFile(t *testing.T) {
+	fs := newTestS3Filesystem(t, "")
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<16)
+	if err := WriteFile(fs, "large", data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fd, err := fs.Open("large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	buf := make([]byte, 1000)
+	if _, err := fd.ReadAt(buf, 123456); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data[123456:124456]) {
+		t.Fatal("data mismatch")
+	}
+}
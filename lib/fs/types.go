@@ -11,6 +11,7 @@ type FilesystemType int32
 const (
 	FilesystemTypeBasic FilesystemType = 0
 	FilesystemTypeFake  FilesystemType = 1
+	FilesystemTypeS3    FilesystemType = 2
 )
 
 func (t FilesystemType) String() string {
@@ -19,6 +20,8 @@ func (t FilesystemType) String() string {
 		return "basic"
 	case FilesystemTypeFake:
 		return "fake"
+	case FilesystemTypeS3:
+		return "s3"
 	default:
 		return "unknown"
 	}
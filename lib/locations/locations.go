@@ -28,9 +28,12 @@ const (
 	HTTPSCertFile LocationEnum = "httpsCertFile"
 	HTTPSKeyFile  LocationEnum = "httpsKeyFile"
 	Database      LocationEnum = "database"
+	SQLiteDB      LocationEnum = "sqliteDatabase"
+	Snapshots     LocationEnum = "snapshots"
 	LogFile       LocationEnum = "logFile"
 	PanicLog      LocationEnum = "panicLog"
 	AuditLog      LocationEnum = "auditLog"
+	ChangeHistory LocationEnum = "changeHistory"
 	GUIAssets     LocationEnum = "guiAssets"
 	DefFolder     LocationEnum = "defFolder"
 )
@@ -47,6 +50,7 @@ const (
 	UserHomeBaseDir BaseDirEnum = "userHome"
 
 	LevelDBDir          = "index-v0.14.0.db"
+	SQLiteDBFile        = "index.sqlite"
 	configFileName      = "config.xml"
 	defaultStateDir     = ".local/state/syncthing"
 	oldDefaultConfigDir = ".config/syncthing"
@@ -119,9 +123,12 @@ var locationTemplates = map[LocationEnum]string{
 	HTTPSCertFile: "${config}/https-cert.pem",
 	HTTPSKeyFile:  "${config}/https-key.pem",
 	Database:      "${data}/" + LevelDBDir,
+	SQLiteDB:      "${data}/" + SQLiteDBFile,
+	Snapshots:     "${data}/snapshots",
 	LogFile:       "${data}/syncthing.log", // --logfile on Windows
 	PanicLog:      "${data}/panic-%{timestamp}.log",
 	AuditLog:      "${data}/audit-%{timestamp}.log",
+	ChangeHistory: "${data}/change-history.jsonl",
 	GUIAssets:     "${config}/gui",
 	DefFolder:     "${userHome}/Sync",
 }
@@ -165,8 +172,9 @@ func PrettyPaths() string {
 	fmt.Fprintf(&b, "Configuration file:\n\t%s\n\n", Get(ConfigFile))
 	fmt.Fprintf(&b, "Device private key & certificate files:\n\t%s\n\t%s\n\n", Get(KeyFile), Get(CertFile))
 	fmt.Fprintf(&b, "GUI / API HTTPS private key & certificate files:\n\t%s\n\t%s\n\n", Get(HTTPSKeyFile), Get(HTTPSCertFile))
-	fmt.Fprintf(&b, "Database location:\n\t%s\n\n", Get(Database))
+	fmt.Fprintf(&b, "Database location:\n\t%s\n\t%s (SQLite)\n\n", Get(Database), Get(SQLiteDB))
 	fmt.Fprintf(&b, "Log file:\n\t%s\n\n", Get(LogFile))
+	fmt.Fprintf(&b, "History of changes made through the GUI / API:\n\t%s\n\n", Get(ChangeHistory))
 	fmt.Fprintf(&b, "GUI override directory:\n\t%s\n\n", Get(GUIAssets))
 	fmt.Fprintf(&b, "Default sync folder directory:\n\t%s\n\n", Get(DefFolder))
 	return b.String()
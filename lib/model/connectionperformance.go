@@ -0,0 +1,169 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"time"
+
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+// The weight of a new measurement in the moving averages of latency and
+// bandwidth.
+const connectionPerformanceWeight = 0.2
+
+// The expected time of a request over a connection doubles with each
+// consecutive failed request, up to this many times.
+const maxConnectionFailureBackoff = 10
+
+// connectionPerformance tracks the latency and bandwidth of the requests
+// sent over a connection, so that requests can be spread over the
+// connections to a device according to how fast they are. It is safe for
+// use from multiple goroutines.
+type connectionPerformance struct {
+	pending      int
+	pendingBytes int64
+	requests     int64
+	failures     int           // consecutive failed requests
+	latency      time.Duration // moving average, excluding transfer time
+	bandwidth    float64       // moving average, bytes per second
+	lastDone     time.Time
+	mut          sync.Mutex
+}
+
+// ConnectionRequestStats describes the requests sent over a connection, and
+// what was measured of them.
+type ConnectionRequestStats struct {
+	PendingRequests int     `json:"pendingRequests"`
+	PendingBytes    int64   `json:"pendingBytes"`
+	Requests        int64   `json:"requests"`
+	LatencyMs       float64 `json:"latencyMs"`
+	BytesPerSecond  int64   `json:"bytesPerSecond"`
+}
+
+func newConnectionPerformance() *connectionPerformance {
+	return &connectionPerformance{
+		mut: sync.NewMutex(),
+	}
+}
+
+// fastestConnection returns the index of the connection on which a request
+// of the given size is expected to complete first. Connections that have
+// not completed any request yet get one request at a time until they have,
+// to be measured, unless their requests have failed.
+func fastestConnection(perfs []*connectionPerformance, size int) int {
+	best, unmeasured := -1, -1
+	var bestExpected, leastPending time.Duration
+	for i, p := range perfs {
+		expected, measured := p.expected(size)
+		switch {
+		case !measured && expected == 0:
+			return i
+		case !measured:
+			if unmeasured == -1 || expected < leastPending {
+				unmeasured, leastPending = i, expected
+			}
+		case best == -1 || expected < bestExpected:
+			best, bestExpected = i, expected
+		}
+	}
+	if best == -1 {
+		return unmeasured
+	}
+	return best
+}
+
+// expected returns how long a request of the given size would take to
+// complete, given the requests already pending and backing off after failed
+// requests. Without measurements it returns the number of pending and failed
+// requests instead, and false.
+func (p *connectionPerformance) expected(size int) (time.Duration, bool) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	switch {
+	case p.requests == 0:
+		return time.Duration(p.pending + p.failures), false
+	case p.bandwidth == 0:
+		// Only seen one request at a time so far.
+		return p.backoff(p.latency * time.Duration(p.pending+1)), true
+	default:
+		return p.backoff(p.latency + p.transferTime(p.pendingBytes+int64(size))), true
+	}
+}
+
+func (p *connectionPerformance) backoff(expected time.Duration) time.Duration {
+	return expected << min(p.failures, maxConnectionFailureBackoff)
+}
+
+// started records a request of the given size being sent. The returned
+// function must be called with the number of bytes received, or an error,
+// when the request completes.
+func (p *connectionPerformance) started(size int) func(received int, err error) {
+	p.mut.Lock()
+	start := time.Now()
+	wasIdle := p.pending == 0
+	p.pending++
+	p.pendingBytes += int64(size)
+	p.mut.Unlock()
+
+	return func(received int, err error) {
+		p.mut.Lock()
+		defer p.mut.Unlock()
+		now := time.Now()
+		p.pending--
+		p.pendingBytes -= int64(size)
+		if err != nil {
+			p.failures++
+			return
+		}
+		p.requests++
+		p.failures = 0
+
+		// Latency is measured on requests that didn't queue behind others,
+		// less the time it took to transfer the response.
+		if wasIdle {
+			latency := now.Sub(start)
+			if p.bandwidth > 0 {
+				latency -= p.transferTime(int64(received))
+			}
+			p.latency = time.Duration(movingAverage(float64(p.latency), float64(max(latency, 0))))
+		}
+
+		// A response that arrives while others are underway follows right
+		// after the previous one, so the time in between was spent
+		// transferring it.
+		if p.lastDone.After(start) && received > 0 {
+			if busy := now.Sub(p.lastDone); busy > 0 {
+				p.bandwidth = movingAverage(p.bandwidth, float64(received)/busy.Seconds())
+			}
+		}
+		p.lastDone = now
+	}
+}
+
+func (p *connectionPerformance) transferTime(bytes int64) time.Duration {
+	return time.Duration(float64(bytes) / p.bandwidth * float64(time.Second))
+}
+
+func (p *connectionPerformance) stats() ConnectionRequestStats {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	return ConnectionRequestStats{
+		PendingRequests: p.pending,
+		PendingBytes:    p.pendingBytes,
+		Requests:        p.requests,
+		LatencyMs:       float64(p.latency) / float64(time.Millisecond),
+		BytesPerSecond:  int64(p.bandwidth),
+	}
+}
+
+func movingAverage(avg, sample float64) float64 {
+	if avg == 0 {
+		return sample
+	}
+	return avg + connectionPerformanceWeight*(sample-avg)
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFastestConnection(t *testing.T) {
+	measured := func(latency time.Duration, bandwidth float64, pendingBytes int64) *connectionPerformance {
+		p := newConnectionPerformance()
+		p.requests = 10
+		p.latency = latency
+		p.bandwidth = bandwidth
+		p.pendingBytes = pendingBytes
+		if pendingBytes > 0 {
+			p.pending = 1
+		}
+		return p
+	}
+	unmeasured := func(pending int) *connectionPerformance {
+		p := newConnectionPerformance()
+		p.pending = pending
+		return p
+	}
+	failing := func(requests int64, latency time.Duration, failures int) *connectionPerformance {
+		p := newConnectionPerformance()
+		p.requests = requests
+		p.latency = latency
+		p.failures = failures
+		return p
+	}
+	const block = 128 << 10
+
+	lan := measured(time.Millisecond, 100<<20, 0)
+	wan := measured(50*time.Millisecond, 1<<20, 0)
+	cases := []struct {
+		name     string
+		perfs    []*connectionPerformance
+		expected int
+	}{
+		{"faster connection", []*connectionPerformance{wan, lan}, 1},
+		{"busy fast connection", []*connectionPerformance{measured(time.Millisecond, 1<<20, 10<<20), wan}, 1},
+		{"probe idle unmeasured connection", []*connectionPerformance{lan, unmeasured(0)}, 1},
+		{"skip pending unmeasured connection", []*connectionPerformance{unmeasured(1), wan}, 1},
+		{"least pending when nothing is measured", []*connectionPerformance{unmeasured(2), unmeasured(1)}, 1},
+		{"latency only", []*connectionPerformance{measured(10*time.Millisecond, 0, 0), measured(20*time.Millisecond, 0, 0)}, 0},
+		{"skip failing unmeasured connection", []*connectionPerformance{failing(0, 0, 1), wan}, 1},
+		{"fewest failures when nothing is measured", []*connectionPerformance{failing(0, 0, 3), failing(0, 0, 1)}, 1},
+		{"back off failing measured connection", []*connectionPerformance{failing(10, time.Millisecond, 8), measured(20*time.Millisecond, 0, 0)}, 1},
+	}
+	for _, tc := range cases {
+		if idx := fastestConnection(tc.perfs, block); idx != tc.expected {
+			t.Errorf("%s: got connection %d, expected %d", tc.name, idx, tc.expected)
+		}
+	}
+}
+
+func TestConnectionPerformance(t *testing.T) {
+	p := newConnectionPerformance()
+
+	done1 := p.started(100)
+	done2 := p.started(200)
+	if st := p.stats(); st.PendingRequests != 2 || st.PendingBytes != 300 {
+		t.Fatal("unexpected pending requests", st)
+	}
+	time.Sleep(10 * time.Millisecond)
+	done1(100, nil)
+	time.Sleep(10 * time.Millisecond)
+	done2(0, errors.New("failed"))
+
+	st := p.stats()
+	if st.PendingRequests != 0 || st.PendingBytes != 0 || st.Requests != 1 {
+		t.Fatal("unexpected request counts", st)
+	}
+	if st.LatencyMs < 10 {
+		t.Error("expected the latency of the first request to be measured, got", st.LatencyMs)
+	}
+	if st.BytesPerSecond != 0 {
+		t.Error("expected no bandwidth measurement from a failed request, got", st.BytesPerSecond)
+	}
+
+	// Failed requests count against an unmeasured connection, so that it
+	// isn't probed over and over.
+	failed := newConnectionPerformance()
+	failed.started(100)(0, errors.New("failed"))
+	if expected, measured := failed.expected(100); measured || expected == 0 {
+		t.Error("expected a failed unmeasured connection not to be idle, got", expected, measured)
+	}
+
+	// The second of two pipelined responses measures bandwidth.
+	done1 = p.started(1000)
+	done2 = p.started(1000)
+	done1(1000, nil)
+	time.Sleep(10 * time.Millisecond)
+	done2(1000, nil)
+	if bps := p.stats().BytesPerSecond; bps <= 0 || bps > 1000*100 {
+		t.Error("unexpected bandwidth", bps)
+	}
+
+	// Failures back off a measured connection until a request succeeds.
+	before, _ := p.expected(1000)
+	p.started(1000)(0, errors.New("failed"))
+	p.started(1000)(0, errors.New("failed"))
+	if after, _ := p.expected(1000); after != before<<2 {
+		t.Errorf("expected %v after two failures, got %v", before<<2, after)
+	}
+	p.started(1000)(1000, nil)
+	if p.failures != 0 {
+		t.Error("expected a successful request to reset the failures, got", p.failures)
+	}
+}
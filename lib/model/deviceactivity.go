@@ -7,32 +7,51 @@
 package model
 
 import (
+	"time"
+
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/syncthing/syncthing/lib/sync"
 )
 
+// A device that failed a request, for example because it is serving as
+// many devices as it may, is only asked again before others for this long
+// afterwards.
+const deviceFailureBackoff = 10 * time.Second
+
 // deviceActivity tracks the number of outstanding requests per device and can
 // answer which device is least busy. It is safe for use from multiple
 // goroutines.
 type deviceActivity struct {
-	act map[protocol.DeviceID]int
-	mut sync.Mutex
+	act      map[protocol.DeviceID]int
+	failures map[protocol.DeviceID]time.Time
+	mut      sync.Mutex
 }
 
 func newDeviceActivity() *deviceActivity {
 	return &deviceActivity{
-		act: make(map[protocol.DeviceID]int),
-		mut: sync.NewMutex(),
+		act:      make(map[protocol.DeviceID]int),
+		failures: make(map[protocol.DeviceID]time.Time),
+		mut:      sync.NewMutex(),
 	}
 }
 
 // Returns the index of the least busy device, or -1 if all are too busy.
+// Devices that recently failed a request count as busier than all others.
 func (m *deviceActivity) leastBusy(availability []Availability) int {
 	m.mut.Lock()
+	now := time.Now()
 	low := 2<<30 - 1
 	best := -1
 	for i := range availability {
-		if usage := m.act[availability[i].ID]; usage < low {
+		usage := m.act[availability[i].ID]
+		if failed, ok := m.failures[availability[i].ID]; ok {
+			if now.Sub(failed) < deviceFailureBackoff {
+				usage += 1 << 20
+			} else {
+				delete(m.failures, availability[i].ID)
+			}
+		}
+		if usage < low {
 			low = usage
 			best = i
 		}
@@ -52,3 +71,9 @@ func (m *deviceActivity) done(availability Availability) {
 	m.act[availability.ID]--
 	m.mut.Unlock()
 }
+
+func (m *deviceActivity) failed(availability Availability) {
+	m.mut.Lock()
+	m.failures[availability.ID] = time.Now()
+	m.mut.Unlock()
+}
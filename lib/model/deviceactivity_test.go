@@ -8,6 +8,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/syncthing/syncthing/lib/protocol"
 )
@@ -58,3 +59,25 @@ func TestDeviceActivity(t *testing.T) {
 		t.Errorf("Least busy device should be n0 (%v) not %v", n0, lb)
 	}
 }
+
+func TestDeviceActivityFailure(t *testing.T) {
+	n0 := Availability{protocol.DeviceID([32]byte{1, 2, 3, 4}), false}
+	n1 := Availability{protocol.DeviceID([32]byte{5, 6, 7, 8}), false}
+	devices := []Availability{n0, n1}
+	na := newDeviceActivity()
+
+	na.using(n1)
+	na.using(n1)
+	na.failed(n0)
+	if lb := na.leastBusy(devices); lb != 1 {
+		t.Errorf("Device n1 (%v) should be preferred over the failed n0, not %v", n1, lb)
+	}
+	if lb := na.leastBusy(devices[:1]); lb != 0 {
+		t.Errorf("Failed device n0 (%v) should still be used when alone, not %v", n0, lb)
+	}
+
+	na.failures[n0.ID] = time.Now().Add(-deviceFailureBackoff)
+	if lb := na.leastBusy(devices); lb != 0 {
+		t.Errorf("Device n0 (%v) should be least busy after the backoff, not %v", n0, lb)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"os"
 	"path/filepath"
 	"sort"
 	"time"
@@ -20,7 +22,6 @@ import (
 	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/fs"
 	"github.com/syncthing/syncthing/lib/ignore"
-	"github.com/syncthing/syncthing/lib/locations"
 	"github.com/syncthing/syncthing/lib/osutil"
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/syncthing/syncthing/lib/scanner"
@@ -60,6 +61,8 @@ type folder struct {
 	scanScheduled          chan struct{}
 	versionCleanupInterval time.Duration
 	versionCleanupTimer    *time.Timer
+	snapshotInterval       time.Duration
+	snapshotTimer          *time.Timer
 
 	pullScheduled chan struct{}
 	pullPause     time.Duration
@@ -118,6 +121,8 @@ func newFolder(model *model, fset *db.FileSet, ignores *ignore.Matcher, cfg conf
 		scanScheduled:          make(chan struct{}, 1),
 		versionCleanupInterval: time.Duration(cfg.Versioning.CleanupIntervalS) * time.Second,
 		versionCleanupTimer:    time.NewTimer(time.Duration(cfg.Versioning.CleanupIntervalS) * time.Second),
+		snapshotInterval:       time.Duration(cfg.SnapshotIntervalS) * time.Second,
+		snapshotTimer:          time.NewTimer(time.Duration(cfg.SnapshotIntervalS) * time.Second),
 
 		pullScheduled: make(chan struct{}, 1), // This needs to be 1-buffered so that we queue a pull if we're busy when it comes.
 
@@ -156,6 +161,7 @@ func (f *folder) Serve(ctx context.Context) error {
 	defer func() {
 		f.scanTimer.Stop()
 		f.versionCleanupTimer.Stop()
+		f.snapshotTimer.Stop()
 		f.setState(FolderIdle)
 	}()
 
@@ -171,6 +177,13 @@ func (f *folder) Serve(ctx context.Context) error {
 		}
 	}
 
+	// Likewise for periodic snapshots.
+	if f.snapshotInterval == 0 {
+		if !f.snapshotTimer.Stop() {
+			<-f.snapshotTimer.C
+		}
+	}
+
 	initialCompleted := f.initialScanFinished
 
 	for {
@@ -228,6 +241,10 @@ func (f *folder) Serve(ctx context.Context) error {
 		case <-f.versionCleanupTimer.C:
 			l.Debugln(f, "Doing version cleanup")
 			f.versionCleanupTimerFired()
+
+		case <-f.snapshotTimer.C:
+			l.Debugln(f, "Taking periodic snapshot")
+			f.snapshotTimerFired()
 		}
 
 		if err != nil {
@@ -334,7 +351,11 @@ func (f *folder) getHealthErrorWithoutIgnores() error {
 	}
 
 	if minFree := f.model.cfg.Options().MinHomeDiskFree; minFree.Value > 0 {
-		dbPath := locations.Get(locations.Database)
+		// The LevelDB database is a directory, the SQLite one a file.
+		dbPath := f.model.db.Location()
+		if info, err := os.Stat(dbPath); err == nil && !info.IsDir() {
+			dbPath = filepath.Dir(dbPath)
+		}
 		if usage, err := fs.NewFilesystem(fs.FilesystemTypeBasic, dbPath).Usage("."); err == nil {
 			if err = config.CheckFreeSpace(minFree, usage); err != nil {
 				return fmt.Errorf("insufficient space on disk for database (%v): %w", dbPath, err)
@@ -642,23 +663,24 @@ func (f *folder) scanSubdirsChangedAndNew(subDirs []string, batch *scanBatch) (i
 	defer scanCancel()
 
 	scanConfig := scanner.Config{
-		Folder:                f.ID,
-		Subs:                  subDirs,
-		Matcher:               f.ignores,
-		TempLifetime:          time.Duration(f.model.cfg.Options().KeepTemporariesH) * time.Hour,
-		CurrentFiler:          cFiler{snap},
-		Filesystem:            f.mtimefs,
-		IgnorePerms:           f.IgnorePerms,
-		AutoNormalize:         f.AutoNormalize,
-		Hashers:               f.model.numHashers(f.ID),
-		ShortID:               f.shortID,
-		ProgressTickIntervalS: f.ScanProgressIntervalS,
-		LocalFlags:            f.localFlags,
-		ModTimeWindow:         f.modTimeWindow,
-		EventLogger:           f.evLogger,
-		ScanOwnership:         f.SendOwnership || f.SyncOwnership,
-		ScanXattrs:            f.SendXattrs || f.SyncXattrs,
-		XattrFilter:           f.XattrFilter,
+		Folder:                 f.ID,
+		Subs:                   subDirs,
+		Matcher:                f.ignores,
+		TempLifetime:           time.Duration(f.model.cfg.Options().KeepTemporariesH) * time.Hour,
+		CurrentFiler:           cFiler{snap},
+		Filesystem:             f.mtimefs,
+		IgnorePerms:            f.IgnorePerms,
+		AutoNormalize:          f.AutoNormalize,
+		Hashers:                f.model.numHashers(f.ID),
+		ShortID:                f.shortID,
+		ProgressTickIntervalS:  f.ScanProgressIntervalS,
+		LocalFlags:             f.localFlags,
+		ModTimeWindow:          f.modTimeWindow,
+		EventLogger:            f.evLogger,
+		ScanOwnership:          f.SendOwnership || f.SyncOwnership,
+		ScanXattrs:             f.SendXattrs || f.SyncXattrs,
+		XattrFilter:            f.XattrFilter,
+		ContentDefinedChunking: f.model.useContentDefinedChunking(f.FolderConfiguration),
 	}
 	var fchan chan scanner.ScanResult
 	if f.Type == config.FolderTypeReceiveEncrypted {
@@ -688,7 +710,7 @@ func (f *folder) scanSubdirsChangedAndNew(subDirs []string, batch *scanBatch) (i
 		}
 
 		switch f.Type {
-		case config.FolderTypeReceiveOnly, config.FolderTypeReceiveEncrypted:
+		case config.FolderTypeReceiveOnly, config.FolderTypeReceiveEncrypted, config.FolderTypeAppendOnly:
 		default:
 			if nf, ok := f.findRename(snap, res.File, alreadyUsedOrExisting); ok {
 				if batch.Update(nf, snap) {
@@ -791,6 +813,12 @@ func (f *folder) scanSubdirsDeletedAndIgnored(subDirs []string, batch *scanBatch
 					// the deleted file. Setting to an empty version makes
 					// sure the file gets in sync on the following pull.
 					nf.Version = protocol.Vector{}
+				} else if f.Type == config.FolderTypeAppendOnly {
+					// Deletions are refused in append-only folders. With
+					// an empty version the deletion isn't propagated and
+					// the file is pulled again from the other devices.
+					l.Infof("Folder %v: refusing local deletion of %q in append-only folder", f.Description(), fi.Name)
+					nf.Version = protocol.Vector{}
 				}
 				l.Debugln("marking file as deleted", nf)
 				if batch.Update(nf, snap) {
@@ -949,6 +977,188 @@ func (f *folder) versionCleanupTimerFired() {
 	f.versionCleanupTimer.Reset(f.versionCleanupInterval)
 }
 
+func (f *folder) snapshotTimerFired() {
+	if _, err := f.CreateSnapshot(); err != nil {
+		l.Infof("Failed to take snapshot of %s: %v", f.Description(), err)
+	}
+	f.snapshotTimer.Reset(f.snapshotInterval)
+}
+
+// CreateSnapshot records the current state of the folder in a new
+// snapshot, removing the oldest ones beyond the configured maximum.
+func (f *folder) CreateSnapshot() (SnapshotInfo, error) {
+	fsnap, err := takeFolderSnapshot(f.ID, f.fset, time.Now())
+	if err != nil {
+		return SnapshotInfo{}, err
+	}
+	store := newSnapshotStore(f.ID)
+	if err := store.save(fsnap); err != nil {
+		return SnapshotInfo{}, err
+	}
+	if err := store.prune(f.MaxSnapshots); err != nil {
+		l.Infof("Failed to remove old snapshots of %s: %v", f.Description(), err)
+	}
+	l.Debugf("%v took snapshot %s of %d files", f, fsnap.ID, len(fsnap.Files))
+	return fsnap.SnapshotInfo, nil
+}
+
+// RestoreSnapshot brings the folder back to the state recorded in the given
+// snapshot. Files that changed or were deleted since are restored from the
+// versioner, while files that were added since are archived by it. Nothing
+// is changed if the versioner no longer has the contents of every file in
+// the snapshot. The returned map contains the files that could not be
+// restored.
+func (f *folder) RestoreSnapshot(id string) (map[string]error, error) {
+	if f.versioner == nil {
+		return nil, errNoVersioner
+	}
+	fsnap, err := newSnapshotStore(f.ID).load(id)
+	if err != nil {
+		return nil, err
+	}
+	var restoreErrors map[string]error
+	err = f.doInSync(func() error {
+		var err error
+		restoreErrors, err = f.restoreSnapshot(fsnap)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	f.ScheduleScan()
+	return restoreErrors, nil
+}
+
+func (f *folder) restoreSnapshot(fsnap FolderSnapshot) (map[string]error, error) {
+	versions, err := f.versioner.GetVersions()
+	if err != nil {
+		return nil, err
+	}
+	snap, err := f.fset.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer snap.Release()
+
+	// Find the archived versions to restore before changing anything, so
+	// that a snapshot that can't be restored completely is refused.
+	restoreVersions, err := f.findSnapshotVersions(fsnap, snap, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	restoreErrors := make(map[string]error)
+
+	// Files that don't exist in the snapshot are archived, in reverse
+	// order so that directories are emptied before they are removed.
+	var added []protocol.FileInfo
+	for _, d := range diffFolderSnapshot(fsnap, snap) {
+		if d.Change == "added" {
+			cf, _ := snap.Get(protocol.LocalDeviceID, d.Name)
+			added = append(added, cf)
+		}
+	}
+	for i := len(added) - 1; i >= 0; i-- {
+		if err := f.removeForSnapshot(added[i]); err != nil {
+			restoreErrors[added[i].Name] = err
+		}
+	}
+
+	// The snapshot is sorted by name, so directories come before their
+	// contents.
+	for _, sf := range fsnap.Files {
+		if cf, ok := snap.Get(protocol.LocalDeviceID, sf.Name); ok && sf.isEquivalent(cf) {
+			continue
+		}
+		if err := f.restoreSnapshotFile(sf, restoreVersions[sf.Name]); err != nil {
+			restoreErrors[sf.Name] = err
+		}
+	}
+
+	return restoreErrors, nil
+}
+
+// findSnapshotVersions returns the archived versions of the files that
+// changed since the snapshot was taken, or an error naming the files that
+// have none.
+func (f *folder) findSnapshotVersions(fsnap FolderSnapshot, snap *db.Snapshot, versions map[string][]versioner.FileVersion) (map[string]versioner.FileVersion, error) {
+	opener, _ := f.versioner.(versioner.VersionOpener)
+	found := make(map[string]versioner.FileVersion)
+	var missing []string
+	for _, sf := range fsnap.Files {
+		if sf.Type != protocol.FileInfoTypeFile {
+			continue
+		}
+		if cf, ok := snap.Get(protocol.LocalDeviceID, sf.Name); ok && sf.isEquivalent(cf) {
+			continue
+		}
+		open := func(versionTime time.Time) (io.ReadCloser, error) {
+			if opener == nil {
+				return nil, versioner.ErrRestorationNotSupported
+			}
+			return opener.OpenVersion(sf.Name, versionTime)
+		}
+		v, err := findSnapshotVersion(f.ctx, sf, versions[sf.Name], open)
+		if err != nil {
+			if f.ctx.Err() != nil {
+				return nil, f.ctx.Err()
+			}
+			l.Debugf("%v snapshot %s: %s: %v", f, fsnap.ID, sf.Name, err)
+			missing = append(missing, sf.Name)
+			continue
+		}
+		found[sf.Name] = v
+	}
+	switch len(missing) {
+	case 0:
+		return found, nil
+	case 1:
+		return nil, fmt.Errorf("%w: %s", errSnapshotNotRestorable, missing[0])
+	default:
+		return nil, fmt.Errorf("%w: %s and %d other files", errSnapshotNotRestorable, missing[0], len(missing)-1)
+	}
+}
+
+func (f *folder) removeForSnapshot(file protocol.FileInfo) error {
+	switch file.Type {
+	case protocol.FileInfoTypeFile:
+		return f.versioner.Archive(file.Name)
+	default:
+		if err := f.mtimefs.Remove(file.Name); err != nil && !fs.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *folder) restoreSnapshotFile(sf SnapshotFile, version versioner.FileVersion) error {
+	switch sf.Type {
+	case protocol.FileInfoTypeDirectory:
+		if info, err := f.mtimefs.Lstat(sf.Name); err == nil && !info.IsDir() {
+			if err := f.versioner.Archive(sf.Name); err != nil {
+				return err
+			}
+		}
+		return f.mtimefs.MkdirAll(sf.Name, fs.FileMode(sf.Permissions&0o777)|0o700)
+
+	case protocol.FileInfoTypeSymlink:
+		if info, err := f.mtimefs.Lstat(sf.Name); err == nil {
+			if info.IsRegular() {
+				err = f.versioner.Archive(sf.Name)
+			} else {
+				err = f.mtimefs.Remove(sf.Name)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return f.mtimefs.CreateSymlink(string(sf.SymlinkTarget), sf.Name)
+
+	default:
+		return f.versioner.Restore(sf.Name, version.VersionTime)
+	}
+}
+
 func (f *folder) WatchError() error {
 	f.watchMut.Lock()
 	defer f.watchMut.Unlock()
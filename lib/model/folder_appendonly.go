@@ -0,0 +1,110 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/ignore"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/semaphore"
+	"github.com/syncthing/syncthing/lib/versioner"
+)
+
+var (
+	errAppendOnlyDelete = errors.New("refusing to delete existing item in append-only folder")
+	errAppendOnlyModify = errors.New("refusing to modify existing item in append-only folder")
+)
+
+func init() {
+	folderFactories[config.FolderTypeAppendOnly] = newAppendOnlyFolder
+}
+
+/*
+appendOnlyFolder is a folder where items can be added, but never changed or
+removed once they exist ("write once, read many"):
+
+  - New items from other devices are pulled as usual. Remote modifications
+    and deletions of items we have are rejected by the index handler, which
+    records them as invalid so that they are never needed, and reports them
+    as folder errors. The puller checks again before applying anything, for
+    items that only became ours after the remote change was recorded.
+
+  - Local additions are scanned and sent to the cluster as usual. Local
+    deletions are refused: they are recorded with an empty version, which
+    keeps them from being sent to other devices and makes us pull the item
+    again.
+
+Implementation wise an appendOnlyFolder is just a sendReceiveFolder, with
+the checks for the folder type in the puller, the scanner and the index
+handler.
+*/
+type appendOnlyFolder struct {
+	*sendReceiveFolder
+
+	// The remote changes rejected by the index handler, by path. Protected
+	// by errorsMut.
+	rejected map[string]FileError
+}
+
+func newAppendOnlyFolder(model *model, fset *db.FileSet, ignores *ignore.Matcher, cfg config.FolderConfiguration, ver versioner.Versioner, evLogger events.Logger, ioLimiter *semaphore.Semaphore) service {
+	sr := newSendReceiveFolder(model, fset, ignores, cfg, ver, evLogger, ioLimiter).(*sendReceiveFolder)
+	return &appendOnlyFolder{
+		sendReceiveFolder: sr,
+		rejected:          make(map[string]FileError),
+	}
+}
+
+// rejectRemoteChanges records the remote changes rejected by the index
+// handler as folder errors. They remain until the folder restarts.
+func (f *appendOnlyFolder) rejectRemoteChanges(errs []FileError) {
+	f.errorsMut.Lock()
+	for _, fe := range errs {
+		f.rejected[fe.Path] = fe
+	}
+	f.errorsMut.Unlock()
+
+	f.evLogger.Log(events.FolderErrors, map[string]interface{}{
+		"folder": f.folderID,
+		"errors": f.Errors(),
+	})
+}
+
+func (f *appendOnlyFolder) Errors() []FileError {
+	errs := f.sendReceiveFolder.Errors()
+	f.errorsMut.Lock()
+	defer f.errorsMut.Unlock()
+	for _, fe := range f.rejected {
+		errs = append(errs, fe)
+	}
+	sort.Sort(fileErrorList(errs))
+	return errs
+}
+
+// appendOnlyViolation returns an error if applying the given file would
+// modify or delete the current, existing item in an append-only folder.
+func appendOnlyViolation(cur protocol.FileInfo, hasCur bool, file protocol.FileInfo) error {
+	if !hasCur || cur.IsDeleted() || cur.IsInvalid() || file.IsInvalid() {
+		return nil
+	}
+	switch {
+	case file.IsDeleted():
+		return errAppendOnlyDelete
+	case file.Type != cur.Type:
+		return errAppendOnlyModify
+	case file.Type == protocol.FileInfoTypeFile && !file.BlocksEqual(cur):
+		return errAppendOnlyModify
+	case file.IsSymlink() && !bytes.Equal(file.SymlinkTarget, cur.SymlinkTarget):
+		return errAppendOnlyModify
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestAppendOnlyViolation(t *testing.T) {
+	cur := setupFile("file", []int{1, 2})
+	modified := setupFile("file", []int{1, 3})
+	deleted := cur
+	deleted.SetDeleted(device1.Short())
+	invalid := modified
+	invalid.SetIgnored()
+	dir := protocol.FileInfo{Name: "file", Type: protocol.FileInfoTypeDirectory}
+
+	cases := []struct {
+		name   string
+		cur    protocol.FileInfo
+		hasCur bool
+		file   protocol.FileInfo
+		err    error
+	}{
+		{"new file", protocol.FileInfo{}, false, cur, nil},
+		{"recreated file", deleted, true, cur, nil},
+		{"metadata change", cur, true, cur, nil},
+		{"deletion", cur, true, deleted, errAppendOnlyDelete},
+		{"modification", cur, true, modified, errAppendOnlyModify},
+		{"type change", cur, true, dir, errAppendOnlyModify},
+		{"invalid", cur, true, invalid, nil},
+	}
+	for _, tc := range cases {
+		if err := appendOnlyViolation(tc.cur, tc.hasCur, tc.file); !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.err, err)
+		}
+	}
+}
+
+func setupAppendOnlyFolder(t *testing.T) (*testModel, *appendOnlyFolder, context.CancelFunc) {
+	t.Helper()
+	w, fcfg, wCancel := newDefaultCfgWrapper()
+	fcfg.Type = config.FolderTypeAppendOnly
+	waiter, err := w.Modify(func(cfg *config.Configuration) {
+		cfg.SetFolder(fcfg)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiter.Wait()
+	m := setupModel(t, w)
+	m.cancel()
+	<-m.stopped
+	r, _ := m.folderRunners.Get(fcfg.ID)
+	f := r.(*appendOnlyFolder)
+	f.tempPullErrors = make(map[string]string)
+	f.ctx = context.Background()
+	return m, f, wCancel
+}
+
+func TestAppendOnlyPull(t *testing.T) {
+	_, f, wCancel := setupAppendOnlyFolder(t)
+	defer wCancel()
+
+	writeFile(t, f.mtimefs, "existing", []byte("data"))
+	must(t, f.scanSubdirs(nil))
+	snap := fsetSnapshot(t, f.fset)
+	existing, ok := snap.Get(protocol.LocalDeviceID, "existing")
+	snap.Release()
+	if !ok {
+		t.Fatal("existing file not scanned")
+	}
+
+	existing.SetDeleted(device1.Short())
+	f.fset.Update(device1, []protocol.FileInfo{existing, {
+		Name:    "new",
+		Type:    protocol.FileInfoTypeDirectory,
+		Version: protocol.Vector{}.Update(device1.Short()),
+	}})
+
+	f.pull()
+
+	if _, err := f.mtimefs.Lstat("existing"); err != nil {
+		t.Error("existing file was deleted:", err)
+	}
+	if _, err := f.mtimefs.Lstat("new"); err != nil {
+		t.Error("new directory was not pulled:", err)
+	}
+	errs := f.Errors()
+	if len(errs) != 1 || errs[0].Path != "existing" {
+		t.Error("expected a folder error for the remote deletion, got", errs)
+	}
+}
+
+func TestAppendOnlyLocalDeletion(t *testing.T) {
+	_, f, wCancel := setupAppendOnlyFolder(t)
+	defer wCancel()
+
+	writeFile(t, f.mtimefs, "existing", []byte("data"))
+	must(t, f.scanSubdirs(nil))
+	must(t, f.mtimefs.Remove("existing"))
+	must(t, f.scanSubdirs(nil))
+
+	snap := fsetSnapshot(t, f.fset)
+	defer snap.Release()
+	cur, ok := snap.Get(protocol.LocalDeviceID, "existing")
+	if !ok || !cur.IsDeleted() {
+		t.Fatal("expected deleted file in db, got", cur, ok)
+	}
+	if len(cur.Version.Counters) != 0 {
+		t.Error("expected local deletion to get an empty version, got", cur.Version)
+	}
+}
+
+func TestAppendOnlyIndexRejection(t *testing.T) {
+	m, f, wCancel := setupAppendOnlyFolder(t)
+	defer wCancel()
+
+	writeFile(t, f.mtimefs, "existing", []byte("data"))
+	must(t, f.scanSubdirs(nil))
+	snap := fsetSnapshot(t, f.fset)
+	existing, ok := snap.Get(protocol.LocalDeviceID, "existing")
+	snap.Release()
+	if !ok {
+		t.Fatal("existing file not scanned")
+	}
+
+	s := &indexHandler{
+		conn:               newFakeConnection(device1, m),
+		downloads:          newDeviceDownloadState(),
+		folder:             f.ID,
+		folderIsAppendOnly: true,
+		evLogger:           events.NoopLogger,
+		fset:               f.fset,
+		runner:             f,
+		cond:               sync.NewCond(new(sync.Mutex)),
+	}
+	existing.SetDeleted(device1.Short())
+	newDir := protocol.FileInfo{
+		Name:    "new",
+		Type:    protocol.FileInfoTypeDirectory,
+		Version: protocol.Vector{}.Update(device1.Short()),
+	}
+	must(t, s.receive([]protocol.FileInfo{existing, newDir}, true, "Index update", 0, 0))
+
+	// The deletion is recorded, but never needed
+	snap = fsetSnapshot(t, f.fset)
+	defer snap.Release()
+	if fi, ok := snap.Get(device1, "existing"); !ok || !fi.IsInvalid() {
+		t.Error("expected the remote deletion to be recorded as invalid, got", fi, ok)
+	}
+	var needed []string
+	snap.WithNeed(protocol.LocalDeviceID, func(fi protocol.FileInfo) bool {
+		needed = append(needed, fi.Name)
+		return true
+	})
+	if len(needed) != 1 || needed[0] != "new" {
+		t.Error("expected only the new directory to be needed, got", needed)
+	}
+
+	errs := f.Errors()
+	if len(errs) != 1 || errs[0].Path != "existing" {
+		t.Error("expected a folder error for the remote deletion, got", errs)
+	}
+}
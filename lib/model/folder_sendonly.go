@@ -94,7 +94,7 @@ func (f *sendOnlyFolder) Override() {
 	f.doInSync(f.override)
 }
 
-func (f *sendOnlyFolder) override() error {
+func (f *folder) override() error {
 	l.Infoln("Overriding global state on folder", f.Description())
 
 	f.setState(FolderScanning)
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -161,6 +162,10 @@ func newSendReceiveFolder(model *model, fset *db.FileSet, ignores *ignore.Matche
 func (f *sendReceiveFolder) pull() (bool, error) {
 	l.Debugf("%v pulling", f)
 
+	if err := f.checkMassChange(); err != nil {
+		return false, err
+	}
+
 	scanChan := make(chan string)
 	go f.pullScannerRoutine(scanChan)
 	defer func() {
@@ -229,6 +234,10 @@ func (f *sendReceiveFolder) pull() (bool, error) {
 		})
 	}
 
+	if changed == 0 {
+		f.model.clearMassChangeApproval(f.ID)
+	}
+
 	return changed == 0, nil
 }
 
@@ -321,6 +330,8 @@ func (f *sendReceiveFolder) processNeeded(snap *db.Snapshot, dbUpdateChan chan<-
 	var dirDeletions []protocol.FileInfo
 	fileDeletions := map[string]protocol.FileInfo{}
 	buckets := map[string][]protocol.FileInfo{}
+	pins, selective := f.model.folderPins(f.folderID)
+	var unpinned db.Counts
 
 	// Iterate the list of items that we need and sort them into piles.
 	// Regular files to pull goes into the file queue, everything else
@@ -338,6 +349,21 @@ func (f *sendReceiveFolder) processNeeded(snap *db.Snapshot, dbUpdateChan chan<-
 			return true
 		}
 
+		if selective && !file.IsDeleted() && !pins.pinned(file.Name, file.IsDirectory()) {
+			l.Debugln(f, "not pulling unpinned item (selective sync)", file.FileName())
+			addUnpinned(&unpinned, file)
+			return true
+		}
+
+		if f.Type == config.FolderTypeAppendOnly {
+			cur, hasCur := snap.Get(protocol.LocalDeviceID, file.Name)
+			if err := appendOnlyViolation(cur, hasCur, file); err != nil {
+				// No reason to retry for this
+				f.newPullError(file.Name, err)
+				return true
+			}
+		}
+
 		changed++
 
 		switch {
@@ -431,6 +457,10 @@ func (f *sendReceiveFolder) processNeeded(snap *db.Snapshot, dbUpdateChan chan<-
 	default:
 	}
 
+	if selective {
+		f.model.setUnpinnedNeed(f.folderID, unpinned)
+	}
+
 	// Now do the file queue. Reorder it according to configuration.
 
 	switch f.Order {
@@ -1136,12 +1166,18 @@ func (f *sendReceiveFolder) handleFile(file protocol.FileInfo, snap *db.Snapshot
 func (f *sendReceiveFolder) reuseBlocks(blocks []protocol.BlockInfo, reused []int, file protocol.FileInfo, tempName string) ([]protocol.BlockInfo, []int) {
 	// Check for an old temporary file which might have some blocks we could
 	// reuse.
-	tempBlocks, err := scanner.HashFile(f.ctx, f.ID, f.mtimefs, tempName, file.BlockSize(), nil, false)
+	hashTemp := func() ([]protocol.BlockInfo, error) {
+		if file.HasVariableBlocks() {
+			return scanner.HashFileChunked(f.ctx, f.ID, f.mtimefs, tempName, file.BlockSize(), nil)
+		}
+		return scanner.HashFile(f.ctx, f.ID, f.mtimefs, tempName, file.BlockSize(), nil, false)
+	}
+	tempBlocks, err := hashTemp()
 	if err != nil {
 		var caseErr *fs.ErrCaseConflict
 		if errors.As(err, &caseErr) {
 			if rerr := f.mtimefs.Rename(caseErr.Real, tempName); rerr == nil {
-				tempBlocks, err = scanner.HashFile(f.ctx, f.ID, f.mtimefs, tempName, file.BlockSize(), nil, false)
+				tempBlocks, err = hashTemp()
 			}
 		}
 	}
@@ -1273,6 +1309,11 @@ func (f *sendReceiveFolder) shortcutFile(file protocol.FileInfo, dbUpdateChan ch
 	dbUpdateChan <- dbUpdateJob{file, dbUpdateShortcutFile}
 }
 
+// copySource identifies a file blocks are copied from.
+type copySource struct {
+	folder, path string
+}
+
 // copierRoutine reads copierStates until the in channel closes and performs
 // the relevant copies when possible, or passes it to the puller routine.
 func (f *sendReceiveFolder) copierRoutine(in <-chan copyBlocksState, pullChan chan<- pullBlockState, out chan<- *sharedPullerState) {
@@ -1311,6 +1352,13 @@ func (f *sendReceiveFolder) copierRoutine(in <-chan copyBlocksState, pullChan ch
 		}
 
 		weakHashFinder, file := f.initWeakHashFinder(state)
+		// With content defined chunking the source of a block may be a
+		// file with variable block sizes, so we look up its offset
+		// instead of calculating it from the index.
+		lookupOffsets := f.ContentDefinedChunking || state.file.HasVariableBlocks()
+		// The blocks of the source files looked up so far, nil for those
+		// that couldn't be.
+		srcBlocks := make(map[copySource][]protocol.BlockInfo)
 
 	blocks:
 		for _, block := range state.blocks {
@@ -1370,6 +1418,19 @@ func (f *sendReceiveFolder) copierRoutine(in <-chan copyBlocksState, pullChan ch
 					defer fd.Close()
 
 					srcOffset := int64(state.file.BlockSize()) * int64(index)
+					if lookupOffsets {
+						src := copySource{folder, path}
+						blocks, ok := srcBlocks[src]
+						if !ok {
+							if cf, ok, err := f.model.CurrentFolderFile(folder, path); err == nil && ok {
+								blocks = cf.Blocks
+							}
+							srcBlocks[src] = blocks
+						}
+						if int(index) < len(blocks) {
+							srcOffset = blocks[index].Offset
+						}
+					}
 					_, err = fd.ReadAt(buf, srcOffset)
 					if err != nil {
 						return false
@@ -1543,6 +1604,18 @@ func (f *sendReceiveFolder) pullBlock(state pullBlockState, snap *db.Snapshot, o
 
 	var lastError error
 	candidates := f.model.blockAvailability(f.FolderConfiguration, snap, state.file, state.block)
+	// Among equally busy devices, prefer those that are still downloading
+	// the file themselves over those that have all of it, so that the
+	// blocks spread between the downloading devices rather than all coming
+	// from the few that have the file.
+	slices.SortStableFunc(candidates, func(a, b Availability) int {
+		if a.FromTemporary == b.FromTemporary {
+			return 0
+		} else if a.FromTemporary {
+			return -1
+		}
+		return 1
+	})
 loop:
 	for {
 		select {
@@ -1573,11 +1646,12 @@ loop:
 		// leastBusy can select another device when someone else asks.
 		activity.using(selected)
 		var buf []byte
-		blockNo := int(state.block.Offset / int64(state.file.BlockSize()))
+		blockNo := state.file.BlockIndex(state.block.Offset)
 		buf, lastError = f.model.RequestGlobal(f.ctx, selected.ID, f.folderID, state.file.Name, blockNo, state.block.Offset, int(state.block.Size), state.block.Hash, state.block.WeakHash, selected.FromTemporary)
 		activity.done(selected)
 		if lastError != nil {
 			l.Debugln("request:", f.folderID, state.file.Name, state.block.Offset, state.block.Size, selected.ID.Short(), "returned error:", lastError)
+			activity.failed(selected)
 			continue
 		}
 
@@ -28,6 +28,7 @@ import (
 type FolderSummaryService interface {
 	suture.Service
 	Summary(folder string) (*FolderSummary, error)
+	Completions(folder string) (map[protocol.DeviceID]FolderCompletion, error)
 }
 
 // The folderSummaryService adds summary information events (FolderSummary and
@@ -97,6 +98,10 @@ type FolderSummary struct {
 	NeedBytes       int64 `json:"needBytes"`
 	NeedTotalItems  int   `json:"needTotalItems"`
 
+	// Items not pinned in a folder with selective sync, which aren't pulled.
+	RemoteAvailableItems int   `json:"remoteAvailableItems"`
+	RemoteAvailableBytes int64 `json:"remoteAvailableBytes"`
+
 	ReceiveOnlyChangedFiles       int   `json:"receiveOnlyChangedFiles"`
 	ReceiveOnlyChangedDirectories int   `json:"receiveOnlyChangedDirectories"`
 	ReceiveOnlyChangedSymlinks    int   `json:"receiveOnlyChangedSymlinks"`
@@ -160,6 +165,21 @@ func (c *folderSummaryService) Summary(folder string) (*FolderSummary, error) {
 		need.Deleted = 0
 	}
 
+	// Items that aren't pinned in a folder with selective sync are not
+	// going to be pulled, so they are available remotely rather than
+	// needed, as in the completion.
+	var unpinned db.Counts
+	if haveFcfg && fcfg.SelectiveSync {
+		if counts, err := c.model.UnpinnedNeedSize(folder); err == nil {
+			unpinned = counts
+			need.Files -= unpinned.Files
+			need.Directories -= unpinned.Directories
+			need.Symlinks -= unpinned.Symlinks
+			need.Bytes -= unpinned.Bytes
+		}
+	}
+	res.RemoteAvailableItems, res.RemoteAvailableBytes = unpinned.TotalItems(), unpinned.Bytes
+
 	need.Bytes -= c.model.FolderProgressBytesCompleted(folder)
 	// This may happen if we are in progress of pulling files that were
 	// deleted globally after the pull started.
@@ -179,7 +199,7 @@ func (c *folderSummaryService) Summary(folder string) (*FolderSummary, error) {
 		res.ReceiveOnlyTotalItems = ro.TotalItems()
 	}
 
-	res.InSyncFiles, res.InSyncBytes = global.Files-need.Files, global.Bytes-need.Bytes
+	res.InSyncFiles, res.InSyncBytes = global.Files-need.Files-unpinned.Files, global.Bytes-need.Bytes-unpinned.Bytes
 
 	res.State, res.StateChanged, err = c.model.State(folder)
 	if err != nil {
@@ -392,6 +412,28 @@ func (c *folderSummaryService) sendSummary(ctx context.Context, folder string) {
 	metricFolderSummary.WithLabelValues(folder, metricScopeNeed, metricTypeDeleted).Set(float64(data.NeedDeletes))
 	metricFolderSummary.WithLabelValues(folder, metricScopeNeed, metricTypeBytes).Set(float64(data.NeedBytes))
 
+	c.completions(ctx, folder, func(device protocol.DeviceID, comp FolderCompletion) {
+		ev := comp.Map()
+		ev["folder"] = folder
+		ev["device"] = device.String()
+		c.evLogger.Log(events.FolderCompletion, ev)
+	})
+}
+
+// Completions returns the completion of the folder for the remote devices
+// it's shared with.
+func (c *folderSummaryService) Completions(folder string) (map[protocol.DeviceID]FolderCompletion, error) {
+	if _, ok := c.cfg.Folder(folder); !ok {
+		return nil, ErrFolderMissing
+	}
+	res := make(map[protocol.DeviceID]FolderCompletion)
+	c.completions(context.Background(), folder, func(device protocol.DeviceID, comp FolderCompletion) {
+		res[device] = comp
+	})
+	return res, nil
+}
+
+func (c *folderSummaryService) completions(ctx context.Context, folder string, fn func(protocol.DeviceID, FolderCompletion)) {
 	for _, devCfg := range c.cfg.Folders()[folder].Devices {
 		select {
 		case <-ctx.Done():
@@ -411,9 +453,6 @@ func (c *folderSummaryService) sendSummary(ctx context.Context, folder string) {
 			l.Debugf("Error getting completion for folder %v, device %v: %v", folder, devCfg.DeviceID, err)
 			continue
 		}
-		ev := comp.Map()
-		ev["folder"] = folder
-		ev["device"] = devCfg.DeviceID.String()
-		c.evLogger.Log(events.FolderCompletion, ev)
+		fn(devCfg.DeviceID, comp)
 	}
 }
@@ -0,0 +1,147 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"context"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/connections"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+// While transfers of a higher priority folder are going on with a device,
+// those of lower priority folders may still have one in every
+// lowPriorityShare of the transfers in progress, and always at least one.
+const lowPriorityShare = 4
+
+// folderBandwidth applies the per folder rate limits and priorities to the
+// blocks we request from and send to other devices. The rate limits of the
+// connections apply on top of this.
+type folderBandwidth struct {
+	limits *connections.FolderLimiter
+	mut    sync.Mutex
+	// The number of block transfers per device and priority that are
+	// waiting to start, and that are in progress.
+	waiting map[protocol.DeviceID]map[int]int
+	active  map[protocol.DeviceID]map[int]int
+	// Closed and replaced whenever a transfer starts or is done.
+	changed chan struct{}
+}
+
+func newFolderBandwidth(cfg config.Configuration) *folderBandwidth {
+	return &folderBandwidth{
+		limits:  connections.NewFolderLimiter(cfg),
+		mut:     sync.NewMutex(),
+		waiting: make(map[protocol.DeviceID]map[int]int),
+		active:  make(map[protocol.DeviceID]map[int]int),
+		changed: make(chan struct{}),
+	}
+}
+
+// waitSend waits until size bytes of the folder may be sent to the device
+// over a LAN or WAN connection. The returned function must be called once
+// the data has been sent.
+func (b *folderBandwidth) waitSend(ctx context.Context, fcfg config.FolderConfiguration, device protocol.DeviceID, isLAN bool, size int) (func(), error) {
+	return b.wait(ctx, b.limits.WaitSend, fcfg, device, isLAN, size)
+}
+
+// waitRecv waits until size bytes of the folder may be requested from the
+// device over a LAN or WAN connection. The returned function must be called
+// once the data has been received.
+func (b *folderBandwidth) waitRecv(ctx context.Context, fcfg config.FolderConfiguration, device protocol.DeviceID, isLAN bool, size int) (func(), error) {
+	return b.wait(ctx, b.limits.WaitRecv, fcfg, device, isLAN, size)
+}
+
+func (b *folderBandwidth) wait(ctx context.Context, waitLimit func(context.Context, string, bool, int) error, fcfg config.FolderConfiguration, device protocol.DeviceID, isLAN bool, size int) (func(), error) {
+	done, err := b.waitPriority(ctx, device, fcfg.Priority)
+	if err != nil {
+		return nil, err
+	}
+	if err := waitLimit(ctx, fcfg.ID, isLAN, size); err != nil {
+		done()
+		return nil, err
+	}
+	return done, nil
+}
+
+// waitPriority waits until a transfer of the given priority may start
+// with the device, and registers it as in progress.
+func (b *folderBandwidth) waitPriority(ctx context.Context, device protocol.DeviceID, priority int) (func(), error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	addCount(b.waiting, device, priority, 1)
+	for !b.mayStartLocked(device, priority) {
+		changed := b.changed
+		b.mut.Unlock()
+		select {
+		case <-ctx.Done():
+			b.mut.Lock()
+			addCount(b.waiting, device, priority, -1)
+			b.changedLocked()
+			return nil, ctx.Err()
+		case <-changed:
+		}
+		b.mut.Lock()
+	}
+	addCount(b.waiting, device, priority, -1)
+	addCount(b.active, device, priority, 1)
+	b.changedLocked()
+
+	return func() {
+		b.mut.Lock()
+		addCount(b.active, device, priority, -1)
+		b.changedLocked()
+		b.mut.Unlock()
+	}, nil
+}
+
+// mayStartLocked returns whether a transfer of the given priority may
+// start with the device: when there are no transfers of a higher priority,
+// or when starting it keeps its priority within its minimum share.
+func (b *folderBandwidth) mayStartLocked(device protocol.DeviceID, priority int) bool {
+	higher := false
+	for p, n := range b.waiting[device] {
+		if p > priority && n > 0 {
+			higher = true
+		}
+	}
+	total := 0
+	for p, n := range b.active[device] {
+		if p > priority && n > 0 {
+			higher = true
+		}
+		total += n
+	}
+	if !higher {
+		return true
+	}
+	active := b.active[device][priority]
+	return active == 0 || (active+1)*lowPriorityShare <= total+1
+}
+
+func (b *folderBandwidth) changedLocked() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func addCount(counts map[protocol.DeviceID]map[int]int, device protocol.DeviceID, priority, delta int) {
+	perPriority, ok := counts[device]
+	if !ok {
+		perPriority = make(map[int]int)
+		counts[device] = perPriority
+	}
+	perPriority[priority] += delta
+	if perPriority[priority] == 0 {
+		delete(perPriority, priority)
+		if len(perPriority) == 0 {
+			delete(counts, device)
+		}
+	}
+}
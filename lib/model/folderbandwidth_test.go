@@ -0,0 +1,133 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+)
+
+func TestFolderBandwidthPriority(t *testing.T) {
+	b := newFolderBandwidth(config.Configuration{})
+	urgent := config.FolderConfiguration{ID: "urgent", Priority: 10}
+	media := config.FolderConfiguration{ID: "media"}
+
+	urgentDone, err := b.waitRecv(context.Background(), urgent, device1, false, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other devices and folders of the same priority aren't held up
+	done, err := b.waitRecv(context.Background(), media, device2, false, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done()
+	done, err = b.waitSend(context.Background(), urgent, device1, false, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done()
+
+	// A lower priority folder always gets its minimum share
+	mediaDone, err := b.waitRecv(context.Background(), media, device1, false, 100)
+	if err != nil {
+		t.Fatal("expected lower priority transfer to get its minimum share, got", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := b.waitRecv(ctx, media, device1, false, 100); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected lower priority transfer beyond its share to wait, got", err)
+	}
+
+	mediaStarted := make(chan func())
+	go func() {
+		done, err := b.waitRecv(context.Background(), media, device1, false, 100)
+		if err != nil {
+			t.Error(err)
+		}
+		mediaStarted <- done
+	}()
+	select {
+	case <-mediaStarted:
+		t.Fatal("lower priority transfer beyond its share started while a higher priority one is in progress")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	urgentDone()
+	select {
+	case done := <-mediaStarted:
+		done()
+	case <-time.After(time.Second):
+		t.Fatal("lower priority transfer didn't start after the higher priority one was done")
+	}
+	mediaDone()
+
+	if len(b.active) != 0 || len(b.waiting) != 0 {
+		t.Error("expected no transfers to remain, got", b.active, b.waiting)
+	}
+}
+
+func TestFolderBandwidthShare(t *testing.T) {
+	b := newFolderBandwidth(config.Configuration{})
+	urgent := config.FolderConfiguration{ID: "urgent", Priority: 1}
+	media := config.FolderConfiguration{ID: "media"}
+
+	for i := 0; i < 7; i++ {
+		if _, err := b.waitSend(context.Background(), urgent, device1, false, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// With seven higher priority transfers going on, a quarter of the
+	// transfers makes for two of the lower priority.
+	for i := 0; i < 2; i++ {
+		if _, err := b.waitSend(context.Background(), media, device1, false, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := b.waitSend(ctx, media, device1, false, 100); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected a third lower priority transfer to wait, got", err)
+	}
+	if n := b.waiting[device1][media.Priority]; n != 0 {
+		t.Error("expected the cancelled transfer not to be waiting, got", n)
+	}
+}
+
+func TestFolderBandwidthLimit(t *testing.T) {
+	fcfg := config.FolderConfiguration{ID: "folder", MaxRecvKbps: 1}
+	cfg := config.Configuration{Folders: []config.FolderConfiguration{fcfg}}
+	b := newFolderBandwidth(cfg)
+
+	// The limit holds up the transfer, which gives up its place when
+	// cancelled.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := b.waitRecv(ctx, fcfg, device1, false, 1<<20); err == nil {
+		t.Fatal("expected limited transfer to fail")
+	}
+	if len(b.active) != 0 {
+		t.Error("expected no transfers to remain, got", b.active)
+	}
+
+	// The limit is followed as the config changes
+	to := cfg.Copy()
+	to.Folders[0].MaxRecvKbps = 0
+	b.limits.CommitConfiguration(cfg, to)
+	done, err := b.waitRecv(context.Background(), to.Folders[0], device1, false, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done()
+}
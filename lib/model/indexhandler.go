@@ -25,6 +25,7 @@ type indexHandler struct {
 	downloads                *deviceDownloadState
 	folder                   string
 	folderIsReceiveEncrypted bool
+	folderIsAppendOnly       bool
 	evLogger                 events.Logger
 
 	// We track the latest / highest sequence number in two ways for two
@@ -115,6 +116,7 @@ func newIndexHandler(conn protocol.Connection, downloads *deviceDownloadState, f
 		downloads:                downloads,
 		folder:                   folder.ID,
 		folderIsReceiveEncrypted: folder.Type == config.FolderTypeReceiveEncrypted,
+		folderIsAppendOnly:       folder.Type == config.FolderTypeAppendOnly,
 		localPrevSequence:        startSequence,
 		sentPrevSequence:         startSequence,
 		evLogger:                 evLogger,
@@ -444,6 +446,10 @@ func (s *indexHandler) receive(fs []protocol.FileInfo, update bool, op string, p
 		})
 	}
 
+	if s.folderIsAppendOnly {
+		s.rejectAppendOnlyViolations(fset, runner, fs)
+	}
+
 	fset.Update(deviceID, fs)
 	seq := fset.Sequence(deviceID)
 
@@ -469,6 +475,37 @@ func (s *indexHandler) receive(fs []protocol.FileInfo, update bool, op string, p
 	return nil
 }
 
+// rejectAppendOnlyViolations marks the files from the remote device that
+// modify or delete existing items in an append-only folder as invalid, so
+// that they never become the global version and are never needed, and
+// reports them as folder errors.
+func (s *indexHandler) rejectAppendOnlyViolations(fset *db.FileSet, runner service, fs []protocol.FileInfo) {
+	snap, err := fset.Snapshot()
+	if err != nil {
+		return
+	}
+	defer snap.Release()
+	var rejected []FileError
+	for i, fi := range fs {
+		cur, ok := snap.Get(protocol.LocalDeviceID, fi.Name)
+		if err := appendOnlyViolation(cur, ok, fi); err != nil {
+			l.Debugf("%v: %s from %v: %v", s, fi.Name, s.conn.DeviceID().Short(), err)
+			fs[i].RawInvalid = true
+			rejected = append(rejected, FileError{
+				Path: fi.Name,
+				Err:  fmt.Sprintf("%v (from device %v)", err, s.conn.DeviceID().Short()),
+			})
+		}
+	}
+	if len(rejected) == 0 {
+		return
+	}
+	l.Warnf("Device %v modified or deleted %d existing items in append-only folder %q; these changes will not be applied", s.conn.DeviceID().Short(), len(rejected), s.folder)
+	if f, ok := runner.(*appendOnlyFolder); ok {
+		f.rejectRemoteChanges(rejected)
+	}
+}
+
 func (s *indexHandler) logSequenceAnomaly(msg string, extra map[string]any) {
 	extraStrs := make(map[string]string, len(extra))
 	for k, v := range extra {
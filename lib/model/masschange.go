@@ -0,0 +1,211 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+var errMassChange = errors.New("pausing folder: incoming changes would delete or rewrite too many files, approve or override to continue")
+
+type massChangeState int
+
+const (
+	massChangeNone     massChangeState = iota
+	massChangePending                  // detected, folder paused and waiting for the user
+	massChangeApproved                 // apply the changes on the next pull
+	massChangeRejected                 // override the changes with our local state
+)
+
+// massChange counts the local files that a set of needed changes would
+// delete or rewrite in place, as is the case when they are encrypted.
+type massChange struct {
+	Deletions  int
+	Rewrites   int
+	LocalFiles int
+}
+
+func (c massChange) exceeds(thresholdPct, minFiles int) bool {
+	if thresholdPct <= 0 {
+		return false
+	}
+	changed := c.Deletions + c.Rewrites
+	return changed >= minFiles && changed*100 >= thresholdPct*c.LocalFiles
+}
+
+func countMassChange(snap *db.Snapshot, ignoreDelete bool) massChange {
+	c := massChange{
+		LocalFiles: snap.LocalSize().Files,
+	}
+	snap.WithNeed(protocol.LocalDeviceID, func(need protocol.FileInfo) bool {
+		if need.IsInvalid() || (need.IsDeleted() && ignoreDelete) {
+			return true
+		}
+		cur, ok := snap.Get(protocol.LocalDeviceID, need.Name)
+		if !ok || cur.IsDeleted() || cur.IsInvalid() || cur.Type != protocol.FileInfoTypeFile {
+			// Changes to directories, symlinks and new files can't
+			// destroy data.
+			return true
+		}
+		switch {
+		case need.IsDeleted():
+			c.Deletions++
+		case need.Type == protocol.FileInfoTypeFile && isRewrite(cur, need):
+			c.Rewrites++
+		}
+		return true
+	})
+	return c
+}
+
+// A file encrypted in place keeps its size, give or take a header or
+// padding, while none of its blocks survive. The size may differ by this
+// much, or by rewriteSizeSlackPct of the file, whichever is larger.
+const (
+	rewriteSizeSlack    = 4 << 10
+	rewriteSizeSlackPct = 1
+)
+
+// isRewrite returns whether need replaces every block of cur while keeping
+// its size. Files of a single block are never counted, as any edit of them
+// replaces their only block; the same goes for files that grow or shrink,
+// which is what ordinary edits and replacements do.
+func isRewrite(cur, need protocol.FileInfo) bool {
+	if len(cur.Blocks) < 2 || len(need.Blocks) != len(cur.Blocks) {
+		return false
+	}
+	slack := max(cur.Size*rewriteSizeSlackPct/100, rewriteSizeSlack)
+	if diff := need.Size - cur.Size; diff > slack || -diff > slack {
+		return false
+	}
+	hashes := make(map[string]struct{}, len(cur.Blocks))
+	for _, b := range cur.Blocks {
+		hashes[string(b.Hash)] = struct{}{}
+	}
+	for _, b := range need.Blocks {
+		if _, ok := hashes[string(b.Hash)]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// checkMassChange is called before pulling and returns an error if the
+// needed changes should not be applied. If they would delete or rewrite
+// more than the configured share of the local files, the folder is paused
+// until the user approves or overrides the changes.
+func (f *sendReceiveFolder) checkMassChange() error {
+	switch f.model.massChangeState(f.ID) {
+	case massChangeApproved:
+		return nil
+	case massChangeRejected:
+		f.model.setMassChangeState(f.ID, massChangeNone)
+		return f.override()
+	}
+
+	if f.MassChangeThresholdPct <= 0 {
+		return nil
+	}
+	snap, err := f.dbSnapshot()
+	if err != nil {
+		return err
+	}
+	c := countMassChange(snap, f.IgnoreDelete)
+	snap.Release()
+	if !c.exceeds(f.MassChangeThresholdPct, f.MassChangeMinFiles) {
+		return nil
+	}
+
+	l.Warnf("Folder %v: incoming changes would delete %d and rewrite %d of %d files; pausing the folder until approved", f.Description(), c.Deletions, c.Rewrites, c.LocalFiles)
+	f.model.setMassChangeState(f.ID, massChangePending)
+	f.evLogger.Log(events.MassChangeDetected, map[string]interface{}{
+		"folder":     f.ID,
+		"deletions":  c.Deletions,
+		"rewrites":   c.Rewrites,
+		"localFiles": c.LocalFiles,
+	})
+	// The folder is stopped as a result of the config change, which waits
+	// for us to return, so we must not wait for it here.
+	f.model.setFolderPaused(f.ID, true)
+	return errMassChange
+}
+
+func (m *model) massChangeState(folder string) massChangeState {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return m.folderMassChanges[folder]
+}
+
+func (m *model) setMassChangeState(folder string, state massChangeState) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	if state == massChangeNone {
+		delete(m.folderMassChanges, folder)
+		return
+	}
+	m.folderMassChanges[folder] = state
+}
+
+// clearMassChangeApproval forgets an approval once the approved changes
+// have been applied.
+func (m *model) clearMassChangeApproval(folder string) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	if m.folderMassChanges[folder] == massChangeApproved {
+		delete(m.folderMassChanges, folder)
+	}
+}
+
+// resolveMassChange records the user's decision about a pending mass change
+// and resumes the folder. It returns false if there is no pending mass
+// change for the folder.
+func (m *model) resolveMassChange(folder string, state massChangeState) bool {
+	m.mut.Lock()
+	pending := m.folderMassChanges[folder] == massChangePending
+	if pending {
+		m.folderMassChanges[folder] = state
+	}
+	m.mut.Unlock()
+	if pending {
+		m.setFolderPaused(folder, false)
+	}
+	return pending
+}
+
+// ApproveMassChange lets the folder apply the changes that made it pause,
+// and resumes it. Approval is kept until the folder has fully synced.
+func (m *model) ApproveMassChange(folder string) error {
+	if _, ok := m.cfg.Folder(folder); !ok {
+		return ErrFolderMissing
+	}
+	if !m.resolveMassChange(folder, massChangeApproved) {
+		// We may have been restarted since the folder was paused, in
+		// which case we take the user's word for it.
+		m.setMassChangeState(folder, massChangeApproved)
+		m.setFolderPaused(folder, false)
+	}
+	return nil
+}
+
+func (m *model) setFolderPaused(folder string, paused bool) {
+	_, err := m.cfg.Modify(func(cfg *config.Configuration) {
+		fcfg, _, ok := cfg.Folder(folder)
+		if !ok || fcfg.Paused == paused {
+			return
+		}
+		fcfg.Paused = paused
+		cfg.SetFolder(fcfg)
+	})
+	if err != nil {
+		l.Warnf("Failed to set paused state of folder %s: %v", folder, err)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestMassChangeExceeds(t *testing.T) {
+	c := massChange{Deletions: 30, Rewrites: 20, LocalFiles: 100}
+	cases := []struct {
+		pct, min int
+		exceeds  bool
+	}{
+		{0, 0, false},
+		{50, 10, true},
+		{51, 10, false},
+		{50, 50, true},
+		{50, 51, false},
+		{10, 100, false},
+	}
+	for _, tc := range cases {
+		if res := c.exceeds(tc.pct, tc.min); res != tc.exceeds {
+			t.Errorf("exceeds(%d, %d) = %v, expected %v", tc.pct, tc.min, res, tc.exceeds)
+		}
+	}
+}
+
+func TestMassChangeDetection(t *testing.T) {
+	var local []protocol.FileInfo
+	for i, name := range []string{"deleted", "rewritten", "modified", "unchanged"} {
+		file := setupFile(name, []int{i*2 + 1, i*2 + 2})
+		file.Size = 2 * protocol.MinBlockSize
+		file.Version = protocol.Vector{}.Update(myID.Short())
+		local = append(local, file)
+	}
+	m, f, wcfgCancel := setupSendReceiveFolder(t, local...)
+	defer wcfgCancel()
+
+	deleted := local[0]
+	deleted.SetDeleted(device1.Short())
+	rewritten := local[1]
+	rewritten.Blocks = []protocol.BlockInfo{blocks[0], blocks[0]}
+	rewritten.Size += 512
+	rewritten.Version = rewritten.Version.Update(device1.Short())
+	modified := local[2]
+	modified.Blocks = []protocol.BlockInfo{modified.Blocks[0], blocks[0]}
+	modified.Version = modified.Version.Update(device1.Short())
+	f.fset.Update(device1, []protocol.FileInfo{deleted, rewritten, modified, local[3]})
+
+	snap := fsetSnapshot(t, f.fset)
+	c := countMassChange(snap, false)
+	snap.Release()
+	if c.Deletions != 1 || c.Rewrites != 1 || c.LocalFiles != 4 {
+		t.Fatalf("unexpected mass change count %+v", c)
+	}
+
+	f.MassChangeThresholdPct = 60
+	f.MassChangeMinFiles = 1
+	if err := f.checkMassChange(); err != nil {
+		t.Fatal("unexpected error below threshold:", err)
+	}
+
+	f.MassChangeThresholdPct = 50
+	if err := f.checkMassChange(); !errors.Is(err, errMassChange) {
+		t.Fatal("expected mass change error, got", err)
+	}
+	if state := m.massChangeState(f.ID); state != massChangePending {
+		t.Error("expected pending mass change, got", state)
+	}
+	if fcfg, _ := m.cfg.Folder(f.ID); !fcfg.Paused {
+		t.Error("expected folder to be paused")
+	}
+
+	if err := m.ApproveMassChange(f.ID); err != nil {
+		t.Fatal(err)
+	}
+	if fcfg, _ := m.cfg.Folder(f.ID); fcfg.Paused {
+		t.Error("expected folder to be resumed after approval")
+	}
+	if err := f.checkMassChange(); err != nil {
+		t.Error("unexpected error after approval:", err)
+	}
+	m.clearMassChangeApproval(f.ID)
+	if state := m.massChangeState(f.ID); state != massChangeNone {
+		t.Error("expected approval to be cleared, got", state)
+	}
+}
+
+func TestMassChangeIsRewrite(t *testing.T) {
+	file := func(size int64, hashes ...byte) protocol.FileInfo {
+		f := protocol.FileInfo{Type: protocol.FileInfoTypeFile, Size: size}
+		for _, h := range hashes {
+			f.Blocks = append(f.Blocks, protocol.BlockInfo{Hash: []byte{h}})
+		}
+		return f
+	}
+	const bs = protocol.MinBlockSize
+
+	cases := []struct {
+		name      string
+		cur, need protocol.FileInfo
+		rewrite   bool
+	}{
+		{"small file edited", file(1000, 1), file(1000, 2), false},
+		{"small file edited, same size", file(bs, 1), file(bs, 2), false},
+		{"block appended", file(2*bs, 1, 2), file(3*bs, 3, 4, 5), false},
+		{"one block edited", file(2*bs, 1, 2), file(2*bs, 1, 3), false},
+		{"replaced by smaller file", file(4*bs, 1, 2, 3, 4), file(2*bs, 5, 6), false},
+		{"replaced, size changed", file(4*bs, 1, 2, 3, 4), file(4*bs-bs/2, 5, 6, 7, 8), false},
+		{"encrypted in place", file(4*bs, 1, 2, 3, 4), file(4*bs, 5, 6, 7, 8), true},
+		{"encrypted with header", file(2*bs, 1, 2), file(2*bs+512, 5, 6), true},
+	}
+	for _, tc := range cases {
+		if res := isRewrite(tc.cur, tc.need); res != tc.rewrite {
+			t.Errorf("%s: isRewrite = %v, expected %v", tc.name, res, tc.rewrite)
+		}
+	}
+}
@@ -6,9 +6,23 @@ import (
 	"sync"
 
 	"github.com/syncthing/syncthing/lib/model"
+	"github.com/syncthing/syncthing/lib/protocol"
 )
 
 type FolderSummaryService struct {
+	CompletionsStub        func(string) (map[protocol.DeviceID]model.FolderCompletion, error)
+	completionsMutex       sync.RWMutex
+	completionsArgsForCall []struct {
+		arg1 string
+	}
+	completionsReturns struct {
+		result1 map[protocol.DeviceID]model.FolderCompletion
+		result2 error
+	}
+	completionsReturnsOnCall map[int]struct {
+		result1 map[protocol.DeviceID]model.FolderCompletion
+		result2 error
+	}
 	ServeStub        func(context.Context) error
 	serveMutex       sync.RWMutex
 	serveArgsForCall []struct {
@@ -37,6 +51,70 @@ type FolderSummaryService struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FolderSummaryService) Completions(arg1 string) (map[protocol.DeviceID]model.FolderCompletion, error) {
+	fake.completionsMutex.Lock()
+	ret, specificReturn := fake.completionsReturnsOnCall[len(fake.completionsArgsForCall)]
+	fake.completionsArgsForCall = append(fake.completionsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CompletionsStub
+	fakeReturns := fake.completionsReturns
+	fake.recordInvocation("Completions", []interface{}{arg1})
+	fake.completionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FolderSummaryService) CompletionsCallCount() int {
+	fake.completionsMutex.RLock()
+	defer fake.completionsMutex.RUnlock()
+	return len(fake.completionsArgsForCall)
+}
+
+func (fake *FolderSummaryService) CompletionsCalls(stub func(string) (map[protocol.DeviceID]model.FolderCompletion, error)) {
+	fake.completionsMutex.Lock()
+	defer fake.completionsMutex.Unlock()
+	fake.CompletionsStub = stub
+}
+
+func (fake *FolderSummaryService) CompletionsArgsForCall(i int) string {
+	fake.completionsMutex.RLock()
+	defer fake.completionsMutex.RUnlock()
+	argsForCall := fake.completionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FolderSummaryService) CompletionsReturns(result1 map[protocol.DeviceID]model.FolderCompletion, result2 error) {
+	fake.completionsMutex.Lock()
+	defer fake.completionsMutex.Unlock()
+	fake.CompletionsStub = nil
+	fake.completionsReturns = struct {
+		result1 map[protocol.DeviceID]model.FolderCompletion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FolderSummaryService) CompletionsReturnsOnCall(i int, result1 map[protocol.DeviceID]model.FolderCompletion, result2 error) {
+	fake.completionsMutex.Lock()
+	defer fake.completionsMutex.Unlock()
+	fake.CompletionsStub = nil
+	if fake.completionsReturnsOnCall == nil {
+		fake.completionsReturnsOnCall = make(map[int]struct {
+			result1 map[protocol.DeviceID]model.FolderCompletion
+			result2 error
+		})
+	}
+	fake.completionsReturnsOnCall[i] = struct {
+		result1 map[protocol.DeviceID]model.FolderCompletion
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FolderSummaryService) Serve(arg1 context.Context) error {
 	fake.serveMutex.Lock()
 	ret, specificReturn := fake.serveReturnsOnCall[len(fake.serveArgsForCall)]
@@ -165,6 +243,8 @@ func (fake *FolderSummaryService) SummaryReturnsOnCall(i int, result1 *model.Fol
 func (fake *FolderSummaryService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.completionsMutex.RLock()
+	defer fake.completionsMutex.RUnlock()
 	fake.serveMutex.RLock()
 	defer fake.serveMutex.RUnlock()
 	fake.summaryMutex.RLock()
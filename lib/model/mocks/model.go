@@ -23,6 +23,17 @@ type Model struct {
 		arg1 protocol.Connection
 		arg2 protocol.Hello
 	}
+	ApproveMassChangeStub        func(string) error
+	approveMassChangeMutex       sync.RWMutex
+	approveMassChangeArgsForCall []struct {
+		arg1 string
+	}
+	approveMassChangeReturns struct {
+		result1 error
+	}
+	approveMassChangeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	AvailabilityStub        func(string, protocol.FileInfo, protocol.BlockInfo) ([]model.Availability, error)
 	availabilityMutex       sync.RWMutex
 	availabilityArgsForCall []struct {
@@ -97,6 +108,19 @@ type Model struct {
 	connectionStatsReturnsOnCall map[int]struct {
 		result1 map[string]interface{}
 	}
+	CreateFolderSnapshotStub        func(string) (model.SnapshotInfo, error)
+	createFolderSnapshotMutex       sync.RWMutex
+	createFolderSnapshotArgsForCall []struct {
+		arg1 string
+	}
+	createFolderSnapshotReturns struct {
+		result1 model.SnapshotInfo
+		result2 error
+	}
+	createFolderSnapshotReturnsOnCall map[int]struct {
+		result1 model.SnapshotInfo
+		result2 error
+	}
 	CurrentFolderFileStub        func(string, string) (protocol.FileInfo, bool, error)
 	currentFolderFileMutex       sync.RWMutex
 	currentFolderFileArgsForCall []struct {
@@ -175,6 +199,20 @@ type Model struct {
 		result1 map[protocol.DeviceID]stats.DeviceStatistics
 		result2 error
 	}
+	DiffFolderSnapshotStub        func(string, string) ([]model.SnapshotDiffEntry, error)
+	diffFolderSnapshotMutex       sync.RWMutex
+	diffFolderSnapshotArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	diffFolderSnapshotReturns struct {
+		result1 []model.SnapshotDiffEntry
+		result2 error
+	}
+	diffFolderSnapshotReturnsOnCall map[int]struct {
+		result1 []model.SnapshotDiffEntry
+		result2 error
+	}
 	DismissPendingDeviceStub        func(protocol.DeviceID) error
 	dismissPendingDeviceMutex       sync.RWMutex
 	dismissPendingDeviceArgsForCall []struct {
@@ -234,6 +272,19 @@ type Model struct {
 	folderProgressBytesCompletedReturnsOnCall map[int]struct {
 		result1 int64
 	}
+	FolderSnapshotsStub        func(string) ([]model.SnapshotInfo, error)
+	folderSnapshotsMutex       sync.RWMutex
+	folderSnapshotsArgsForCall []struct {
+		arg1 string
+	}
+	folderSnapshotsReturns struct {
+		result1 []model.SnapshotInfo
+		result2 error
+	}
+	folderSnapshotsReturnsOnCall map[int]struct {
+		result1 []model.SnapshotInfo
+		result2 error
+	}
 	FolderStatisticsStub        func() (map[string]stats.FolderStatistics, error)
 	folderStatisticsMutex       sync.RWMutex
 	folderStatisticsArgsForCall []struct {
@@ -405,6 +456,31 @@ type Model struct {
 		result1 map[string]db.PendingFolder
 		result2 error
 	}
+	PinStub        func(string, string) error
+	pinMutex       sync.RWMutex
+	pinArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	pinReturns struct {
+		result1 error
+	}
+	pinReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PinsStub        func(string) ([]string, error)
+	pinsMutex       sync.RWMutex
+	pinsArgsForCall []struct {
+		arg1 string
+	}
+	pinsReturns struct {
+		result1 []string
+		result2 error
+	}
+	pinsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	RemoteNeedFolderFilesStub        func(string, protocol.DeviceID, int, int) ([]protocol.FileInfo, error)
 	remoteNeedFolderFilesMutex       sync.RWMutex
 	remoteNeedFolderFilesArgsForCall []struct {
@@ -468,6 +544,20 @@ type Model struct {
 	resetFolderReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RestoreFolderSnapshotStub        func(string, string) (map[string]error, error)
+	restoreFolderSnapshotMutex       sync.RWMutex
+	restoreFolderSnapshotArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	restoreFolderSnapshotReturns struct {
+		result1 map[string]error
+		result2 error
+	}
+	restoreFolderSnapshotReturnsOnCall map[int]struct {
+		result1 map[string]error
+		result2 error
+	}
 	RestoreFolderVersionsStub        func(string, map[string]time.Time) (map[string]error, error)
 	restoreFolderVersionsMutex       sync.RWMutex
 	restoreFolderVersionsArgsForCall []struct {
@@ -520,6 +610,19 @@ type Model struct {
 	scanFoldersReturnsOnCall map[int]struct {
 		result1 map[string]error
 	}
+	SearchStub        func(model.SearchQuery) (*model.SearchResults, error)
+	searchMutex       sync.RWMutex
+	searchArgsForCall []struct {
+		arg1 model.SearchQuery
+	}
+	searchReturns struct {
+		result1 *model.SearchResults
+		result2 error
+	}
+	searchReturnsOnCall map[int]struct {
+		result1 *model.SearchResults
+		result2 error
+	}
 	ServeStub        func(context.Context) error
 	serveMutex       sync.RWMutex
 	serveArgsForCall []struct {
@@ -558,6 +661,46 @@ type Model struct {
 		result2 time.Time
 		result3 error
 	}
+	TransferStatisticsStub        func(stats.TransferPeriod, time.Time, time.Time) (stats.TransferHistory, error)
+	transferStatisticsMutex       sync.RWMutex
+	transferStatisticsArgsForCall []struct {
+		arg1 stats.TransferPeriod
+		arg2 time.Time
+		arg3 time.Time
+	}
+	transferStatisticsReturns struct {
+		result1 stats.TransferHistory
+		result2 error
+	}
+	transferStatisticsReturnsOnCall map[int]struct {
+		result1 stats.TransferHistory
+		result2 error
+	}
+	UnpinStub        func(string, string) error
+	unpinMutex       sync.RWMutex
+	unpinArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	unpinReturns struct {
+		result1 error
+	}
+	unpinReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnpinnedNeedSizeStub        func(string) (db.Counts, error)
+	unpinnedNeedSizeMutex       sync.RWMutex
+	unpinnedNeedSizeArgsForCall []struct {
+		arg1 string
+	}
+	unpinnedNeedSizeReturns struct {
+		result1 db.Counts
+		result2 error
+	}
+	unpinnedNeedSizeReturnsOnCall map[int]struct {
+		result1 db.Counts
+		result2 error
+	}
 	UsageReportingStatsStub        func(*contract.Report, int, bool)
 	usageReportingStatsMutex       sync.RWMutex
 	usageReportingStatsArgsForCall []struct {
@@ -613,6 +756,67 @@ func (fake *Model) AddConnectionArgsForCall(i int) (protocol.Connection, protoco
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *Model) ApproveMassChange(arg1 string) error {
+	fake.approveMassChangeMutex.Lock()
+	ret, specificReturn := fake.approveMassChangeReturnsOnCall[len(fake.approveMassChangeArgsForCall)]
+	fake.approveMassChangeArgsForCall = append(fake.approveMassChangeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ApproveMassChangeStub
+	fakeReturns := fake.approveMassChangeReturns
+	fake.recordInvocation("ApproveMassChange", []interface{}{arg1})
+	fake.approveMassChangeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Model) ApproveMassChangeCallCount() int {
+	fake.approveMassChangeMutex.RLock()
+	defer fake.approveMassChangeMutex.RUnlock()
+	return len(fake.approveMassChangeArgsForCall)
+}
+
+func (fake *Model) ApproveMassChangeCalls(stub func(string) error) {
+	fake.approveMassChangeMutex.Lock()
+	defer fake.approveMassChangeMutex.Unlock()
+	fake.ApproveMassChangeStub = stub
+}
+
+func (fake *Model) ApproveMassChangeArgsForCall(i int) string {
+	fake.approveMassChangeMutex.RLock()
+	defer fake.approveMassChangeMutex.RUnlock()
+	argsForCall := fake.approveMassChangeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) ApproveMassChangeReturns(result1 error) {
+	fake.approveMassChangeMutex.Lock()
+	defer fake.approveMassChangeMutex.Unlock()
+	fake.ApproveMassChangeStub = nil
+	fake.approveMassChangeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Model) ApproveMassChangeReturnsOnCall(i int, result1 error) {
+	fake.approveMassChangeMutex.Lock()
+	defer fake.approveMassChangeMutex.Unlock()
+	fake.ApproveMassChangeStub = nil
+	if fake.approveMassChangeReturnsOnCall == nil {
+		fake.approveMassChangeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.approveMassChangeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Model) Availability(arg1 string, arg2 protocol.FileInfo, arg3 protocol.BlockInfo) ([]model.Availability, error) {
 	fake.availabilityMutex.Lock()
 	ret, specificReturn := fake.availabilityReturnsOnCall[len(fake.availabilityArgsForCall)]
@@ -986,6 +1190,70 @@ func (fake *Model) ConnectionStatsReturnsOnCall(i int, result1 map[string]interf
 	}{result1}
 }
 
+func (fake *Model) CreateFolderSnapshot(arg1 string) (model.SnapshotInfo, error) {
+	fake.createFolderSnapshotMutex.Lock()
+	ret, specificReturn := fake.createFolderSnapshotReturnsOnCall[len(fake.createFolderSnapshotArgsForCall)]
+	fake.createFolderSnapshotArgsForCall = append(fake.createFolderSnapshotArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CreateFolderSnapshotStub
+	fakeReturns := fake.createFolderSnapshotReturns
+	fake.recordInvocation("CreateFolderSnapshot", []interface{}{arg1})
+	fake.createFolderSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) CreateFolderSnapshotCallCount() int {
+	fake.createFolderSnapshotMutex.RLock()
+	defer fake.createFolderSnapshotMutex.RUnlock()
+	return len(fake.createFolderSnapshotArgsForCall)
+}
+
+func (fake *Model) CreateFolderSnapshotCalls(stub func(string) (model.SnapshotInfo, error)) {
+	fake.createFolderSnapshotMutex.Lock()
+	defer fake.createFolderSnapshotMutex.Unlock()
+	fake.CreateFolderSnapshotStub = stub
+}
+
+func (fake *Model) CreateFolderSnapshotArgsForCall(i int) string {
+	fake.createFolderSnapshotMutex.RLock()
+	defer fake.createFolderSnapshotMutex.RUnlock()
+	argsForCall := fake.createFolderSnapshotArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) CreateFolderSnapshotReturns(result1 model.SnapshotInfo, result2 error) {
+	fake.createFolderSnapshotMutex.Lock()
+	defer fake.createFolderSnapshotMutex.Unlock()
+	fake.CreateFolderSnapshotStub = nil
+	fake.createFolderSnapshotReturns = struct {
+		result1 model.SnapshotInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) CreateFolderSnapshotReturnsOnCall(i int, result1 model.SnapshotInfo, result2 error) {
+	fake.createFolderSnapshotMutex.Lock()
+	defer fake.createFolderSnapshotMutex.Unlock()
+	fake.CreateFolderSnapshotStub = nil
+	if fake.createFolderSnapshotReturnsOnCall == nil {
+		fake.createFolderSnapshotReturnsOnCall = make(map[int]struct {
+			result1 model.SnapshotInfo
+			result2 error
+		})
+	}
+	fake.createFolderSnapshotReturnsOnCall[i] = struct {
+		result1 model.SnapshotInfo
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) CurrentFolderFile(arg1 string, arg2 string) (protocol.FileInfo, bool, error) {
 	fake.currentFolderFileMutex.Lock()
 	ret, specificReturn := fake.currentFolderFileReturnsOnCall[len(fake.currentFolderFileArgsForCall)]
@@ -1342,6 +1610,71 @@ func (fake *Model) DeviceStatisticsReturnsOnCall(i int, result1 map[protocol.Dev
 	}{result1, result2}
 }
 
+func (fake *Model) DiffFolderSnapshot(arg1 string, arg2 string) ([]model.SnapshotDiffEntry, error) {
+	fake.diffFolderSnapshotMutex.Lock()
+	ret, specificReturn := fake.diffFolderSnapshotReturnsOnCall[len(fake.diffFolderSnapshotArgsForCall)]
+	fake.diffFolderSnapshotArgsForCall = append(fake.diffFolderSnapshotArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DiffFolderSnapshotStub
+	fakeReturns := fake.diffFolderSnapshotReturns
+	fake.recordInvocation("DiffFolderSnapshot", []interface{}{arg1, arg2})
+	fake.diffFolderSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) DiffFolderSnapshotCallCount() int {
+	fake.diffFolderSnapshotMutex.RLock()
+	defer fake.diffFolderSnapshotMutex.RUnlock()
+	return len(fake.diffFolderSnapshotArgsForCall)
+}
+
+func (fake *Model) DiffFolderSnapshotCalls(stub func(string, string) ([]model.SnapshotDiffEntry, error)) {
+	fake.diffFolderSnapshotMutex.Lock()
+	defer fake.diffFolderSnapshotMutex.Unlock()
+	fake.DiffFolderSnapshotStub = stub
+}
+
+func (fake *Model) DiffFolderSnapshotArgsForCall(i int) (string, string) {
+	fake.diffFolderSnapshotMutex.RLock()
+	defer fake.diffFolderSnapshotMutex.RUnlock()
+	argsForCall := fake.diffFolderSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Model) DiffFolderSnapshotReturns(result1 []model.SnapshotDiffEntry, result2 error) {
+	fake.diffFolderSnapshotMutex.Lock()
+	defer fake.diffFolderSnapshotMutex.Unlock()
+	fake.DiffFolderSnapshotStub = nil
+	fake.diffFolderSnapshotReturns = struct {
+		result1 []model.SnapshotDiffEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) DiffFolderSnapshotReturnsOnCall(i int, result1 []model.SnapshotDiffEntry, result2 error) {
+	fake.diffFolderSnapshotMutex.Lock()
+	defer fake.diffFolderSnapshotMutex.Unlock()
+	fake.DiffFolderSnapshotStub = nil
+	if fake.diffFolderSnapshotReturnsOnCall == nil {
+		fake.diffFolderSnapshotReturnsOnCall = make(map[int]struct {
+			result1 []model.SnapshotDiffEntry
+			result2 error
+		})
+	}
+	fake.diffFolderSnapshotReturnsOnCall[i] = struct {
+		result1 []model.SnapshotDiffEntry
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) DismissPendingDevice(arg1 protocol.DeviceID) error {
 	fake.dismissPendingDeviceMutex.Lock()
 	ret, specificReturn := fake.dismissPendingDeviceReturnsOnCall[len(fake.dismissPendingDeviceArgsForCall)]
@@ -1652,6 +1985,70 @@ func (fake *Model) FolderProgressBytesCompletedReturnsOnCall(i int, result1 int6
 	}{result1}
 }
 
+func (fake *Model) FolderSnapshots(arg1 string) ([]model.SnapshotInfo, error) {
+	fake.folderSnapshotsMutex.Lock()
+	ret, specificReturn := fake.folderSnapshotsReturnsOnCall[len(fake.folderSnapshotsArgsForCall)]
+	fake.folderSnapshotsArgsForCall = append(fake.folderSnapshotsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.FolderSnapshotsStub
+	fakeReturns := fake.folderSnapshotsReturns
+	fake.recordInvocation("FolderSnapshots", []interface{}{arg1})
+	fake.folderSnapshotsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) FolderSnapshotsCallCount() int {
+	fake.folderSnapshotsMutex.RLock()
+	defer fake.folderSnapshotsMutex.RUnlock()
+	return len(fake.folderSnapshotsArgsForCall)
+}
+
+func (fake *Model) FolderSnapshotsCalls(stub func(string) ([]model.SnapshotInfo, error)) {
+	fake.folderSnapshotsMutex.Lock()
+	defer fake.folderSnapshotsMutex.Unlock()
+	fake.FolderSnapshotsStub = stub
+}
+
+func (fake *Model) FolderSnapshotsArgsForCall(i int) string {
+	fake.folderSnapshotsMutex.RLock()
+	defer fake.folderSnapshotsMutex.RUnlock()
+	argsForCall := fake.folderSnapshotsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) FolderSnapshotsReturns(result1 []model.SnapshotInfo, result2 error) {
+	fake.folderSnapshotsMutex.Lock()
+	defer fake.folderSnapshotsMutex.Unlock()
+	fake.FolderSnapshotsStub = nil
+	fake.folderSnapshotsReturns = struct {
+		result1 []model.SnapshotInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) FolderSnapshotsReturnsOnCall(i int, result1 []model.SnapshotInfo, result2 error) {
+	fake.folderSnapshotsMutex.Lock()
+	defer fake.folderSnapshotsMutex.Unlock()
+	fake.FolderSnapshotsStub = nil
+	if fake.folderSnapshotsReturnsOnCall == nil {
+		fake.folderSnapshotsReturnsOnCall = make(map[int]struct {
+			result1 []model.SnapshotInfo
+			result2 error
+		})
+	}
+	fake.folderSnapshotsReturnsOnCall[i] = struct {
+		result1 []model.SnapshotInfo
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) FolderStatistics() (map[string]stats.FolderStatistics, error) {
 	fake.folderStatisticsMutex.Lock()
 	ret, specificReturn := fake.folderStatisticsReturnsOnCall[len(fake.folderStatisticsArgsForCall)]
@@ -2448,6 +2845,132 @@ func (fake *Model) PendingFoldersReturnsOnCall(i int, result1 map[string]db.Pend
 	}{result1, result2}
 }
 
+func (fake *Model) Pin(arg1 string, arg2 string) error {
+	fake.pinMutex.Lock()
+	ret, specificReturn := fake.pinReturnsOnCall[len(fake.pinArgsForCall)]
+	fake.pinArgsForCall = append(fake.pinArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.PinStub
+	fakeReturns := fake.pinReturns
+	fake.recordInvocation("Pin", []interface{}{arg1, arg2})
+	fake.pinMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Model) PinCallCount() int {
+	fake.pinMutex.RLock()
+	defer fake.pinMutex.RUnlock()
+	return len(fake.pinArgsForCall)
+}
+
+func (fake *Model) PinCalls(stub func(string, string) error) {
+	fake.pinMutex.Lock()
+	defer fake.pinMutex.Unlock()
+	fake.PinStub = stub
+}
+
+func (fake *Model) PinArgsForCall(i int) (string, string) {
+	fake.pinMutex.RLock()
+	defer fake.pinMutex.RUnlock()
+	argsForCall := fake.pinArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Model) PinReturns(result1 error) {
+	fake.pinMutex.Lock()
+	defer fake.pinMutex.Unlock()
+	fake.PinStub = nil
+	fake.pinReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Model) PinReturnsOnCall(i int, result1 error) {
+	fake.pinMutex.Lock()
+	defer fake.pinMutex.Unlock()
+	fake.PinStub = nil
+	if fake.pinReturnsOnCall == nil {
+		fake.pinReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pinReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Model) Pins(arg1 string) ([]string, error) {
+	fake.pinsMutex.Lock()
+	ret, specificReturn := fake.pinsReturnsOnCall[len(fake.pinsArgsForCall)]
+	fake.pinsArgsForCall = append(fake.pinsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.PinsStub
+	fakeReturns := fake.pinsReturns
+	fake.recordInvocation("Pins", []interface{}{arg1})
+	fake.pinsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) PinsCallCount() int {
+	fake.pinsMutex.RLock()
+	defer fake.pinsMutex.RUnlock()
+	return len(fake.pinsArgsForCall)
+}
+
+func (fake *Model) PinsCalls(stub func(string) ([]string, error)) {
+	fake.pinsMutex.Lock()
+	defer fake.pinsMutex.Unlock()
+	fake.PinsStub = stub
+}
+
+func (fake *Model) PinsArgsForCall(i int) string {
+	fake.pinsMutex.RLock()
+	defer fake.pinsMutex.RUnlock()
+	argsForCall := fake.pinsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) PinsReturns(result1 []string, result2 error) {
+	fake.pinsMutex.Lock()
+	defer fake.pinsMutex.Unlock()
+	fake.PinsStub = nil
+	fake.pinsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) PinsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.pinsMutex.Lock()
+	defer fake.pinsMutex.Unlock()
+	fake.PinsStub = nil
+	if fake.pinsReturnsOnCall == nil {
+		fake.pinsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.pinsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) RemoteNeedFolderFiles(arg1 string, arg2 protocol.DeviceID, arg3 int, arg4 int) ([]protocol.FileInfo, error) {
 	fake.remoteNeedFolderFilesMutex.Lock()
 	ret, specificReturn := fake.remoteNeedFolderFilesReturnsOnCall[len(fake.remoteNeedFolderFilesArgsForCall)]
@@ -2719,6 +3242,71 @@ func (fake *Model) ResetFolderReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Model) RestoreFolderSnapshot(arg1 string, arg2 string) (map[string]error, error) {
+	fake.restoreFolderSnapshotMutex.Lock()
+	ret, specificReturn := fake.restoreFolderSnapshotReturnsOnCall[len(fake.restoreFolderSnapshotArgsForCall)]
+	fake.restoreFolderSnapshotArgsForCall = append(fake.restoreFolderSnapshotArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RestoreFolderSnapshotStub
+	fakeReturns := fake.restoreFolderSnapshotReturns
+	fake.recordInvocation("RestoreFolderSnapshot", []interface{}{arg1, arg2})
+	fake.restoreFolderSnapshotMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) RestoreFolderSnapshotCallCount() int {
+	fake.restoreFolderSnapshotMutex.RLock()
+	defer fake.restoreFolderSnapshotMutex.RUnlock()
+	return len(fake.restoreFolderSnapshotArgsForCall)
+}
+
+func (fake *Model) RestoreFolderSnapshotCalls(stub func(string, string) (map[string]error, error)) {
+	fake.restoreFolderSnapshotMutex.Lock()
+	defer fake.restoreFolderSnapshotMutex.Unlock()
+	fake.RestoreFolderSnapshotStub = stub
+}
+
+func (fake *Model) RestoreFolderSnapshotArgsForCall(i int) (string, string) {
+	fake.restoreFolderSnapshotMutex.RLock()
+	defer fake.restoreFolderSnapshotMutex.RUnlock()
+	argsForCall := fake.restoreFolderSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Model) RestoreFolderSnapshotReturns(result1 map[string]error, result2 error) {
+	fake.restoreFolderSnapshotMutex.Lock()
+	defer fake.restoreFolderSnapshotMutex.Unlock()
+	fake.RestoreFolderSnapshotStub = nil
+	fake.restoreFolderSnapshotReturns = struct {
+		result1 map[string]error
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) RestoreFolderSnapshotReturnsOnCall(i int, result1 map[string]error, result2 error) {
+	fake.restoreFolderSnapshotMutex.Lock()
+	defer fake.restoreFolderSnapshotMutex.Unlock()
+	fake.RestoreFolderSnapshotStub = nil
+	if fake.restoreFolderSnapshotReturnsOnCall == nil {
+		fake.restoreFolderSnapshotReturnsOnCall = make(map[int]struct {
+			result1 map[string]error
+			result2 error
+		})
+	}
+	fake.restoreFolderSnapshotReturnsOnCall[i] = struct {
+		result1 map[string]error
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) RestoreFolderVersions(arg1 string, arg2 map[string]time.Time) (map[string]error, error) {
 	fake.restoreFolderVersionsMutex.Lock()
 	ret, specificReturn := fake.restoreFolderVersionsReturnsOnCall[len(fake.restoreFolderVersionsArgsForCall)]
@@ -2997,6 +3585,70 @@ func (fake *Model) ScanFoldersReturnsOnCall(i int, result1 map[string]error) {
 	}{result1}
 }
 
+func (fake *Model) Search(arg1 model.SearchQuery) (*model.SearchResults, error) {
+	fake.searchMutex.Lock()
+	ret, specificReturn := fake.searchReturnsOnCall[len(fake.searchArgsForCall)]
+	fake.searchArgsForCall = append(fake.searchArgsForCall, struct {
+		arg1 model.SearchQuery
+	}{arg1})
+	stub := fake.SearchStub
+	fakeReturns := fake.searchReturns
+	fake.recordInvocation("Search", []interface{}{arg1})
+	fake.searchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) SearchCallCount() int {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	return len(fake.searchArgsForCall)
+}
+
+func (fake *Model) SearchCalls(stub func(model.SearchQuery) (*model.SearchResults, error)) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = stub
+}
+
+func (fake *Model) SearchArgsForCall(i int) model.SearchQuery {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	argsForCall := fake.searchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) SearchReturns(result1 *model.SearchResults, result2 error) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = nil
+	fake.searchReturns = struct {
+		result1 *model.SearchResults
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) SearchReturnsOnCall(i int, result1 *model.SearchResults, result2 error) {
+	fake.searchMutex.Lock()
+	defer fake.searchMutex.Unlock()
+	fake.SearchStub = nil
+	if fake.searchReturnsOnCall == nil {
+		fake.searchReturnsOnCall = make(map[int]struct {
+			result1 *model.SearchResults
+			result2 error
+		})
+	}
+	fake.searchReturnsOnCall[i] = struct {
+		result1 *model.SearchResults
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) Serve(arg1 context.Context) error {
 	fake.serveMutex.Lock()
 	ret, specificReturn := fake.serveReturnsOnCall[len(fake.serveArgsForCall)]
@@ -3192,6 +3844,198 @@ func (fake *Model) StateReturnsOnCall(i int, result1 string, result2 time.Time,
 	}{result1, result2, result3}
 }
 
+func (fake *Model) TransferStatistics(arg1 stats.TransferPeriod, arg2 time.Time, arg3 time.Time) (stats.TransferHistory, error) {
+	fake.transferStatisticsMutex.Lock()
+	ret, specificReturn := fake.transferStatisticsReturnsOnCall[len(fake.transferStatisticsArgsForCall)]
+	fake.transferStatisticsArgsForCall = append(fake.transferStatisticsArgsForCall, struct {
+		arg1 stats.TransferPeriod
+		arg2 time.Time
+		arg3 time.Time
+	}{arg1, arg2, arg3})
+	stub := fake.TransferStatisticsStub
+	fakeReturns := fake.transferStatisticsReturns
+	fake.recordInvocation("TransferStatistics", []interface{}{arg1, arg2, arg3})
+	fake.transferStatisticsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) TransferStatisticsCallCount() int {
+	fake.transferStatisticsMutex.RLock()
+	defer fake.transferStatisticsMutex.RUnlock()
+	return len(fake.transferStatisticsArgsForCall)
+}
+
+func (fake *Model) TransferStatisticsCalls(stub func(stats.TransferPeriod, time.Time, time.Time) (stats.TransferHistory, error)) {
+	fake.transferStatisticsMutex.Lock()
+	defer fake.transferStatisticsMutex.Unlock()
+	fake.TransferStatisticsStub = stub
+}
+
+func (fake *Model) TransferStatisticsArgsForCall(i int) (stats.TransferPeriod, time.Time, time.Time) {
+	fake.transferStatisticsMutex.RLock()
+	defer fake.transferStatisticsMutex.RUnlock()
+	argsForCall := fake.transferStatisticsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Model) TransferStatisticsReturns(result1 stats.TransferHistory, result2 error) {
+	fake.transferStatisticsMutex.Lock()
+	defer fake.transferStatisticsMutex.Unlock()
+	fake.TransferStatisticsStub = nil
+	fake.transferStatisticsReturns = struct {
+		result1 stats.TransferHistory
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) TransferStatisticsReturnsOnCall(i int, result1 stats.TransferHistory, result2 error) {
+	fake.transferStatisticsMutex.Lock()
+	defer fake.transferStatisticsMutex.Unlock()
+	fake.TransferStatisticsStub = nil
+	if fake.transferStatisticsReturnsOnCall == nil {
+		fake.transferStatisticsReturnsOnCall = make(map[int]struct {
+			result1 stats.TransferHistory
+			result2 error
+		})
+	}
+	fake.transferStatisticsReturnsOnCall[i] = struct {
+		result1 stats.TransferHistory
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) Unpin(arg1 string, arg2 string) error {
+	fake.unpinMutex.Lock()
+	ret, specificReturn := fake.unpinReturnsOnCall[len(fake.unpinArgsForCall)]
+	fake.unpinArgsForCall = append(fake.unpinArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.UnpinStub
+	fakeReturns := fake.unpinReturns
+	fake.recordInvocation("Unpin", []interface{}{arg1, arg2})
+	fake.unpinMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Model) UnpinCallCount() int {
+	fake.unpinMutex.RLock()
+	defer fake.unpinMutex.RUnlock()
+	return len(fake.unpinArgsForCall)
+}
+
+func (fake *Model) UnpinCalls(stub func(string, string) error) {
+	fake.unpinMutex.Lock()
+	defer fake.unpinMutex.Unlock()
+	fake.UnpinStub = stub
+}
+
+func (fake *Model) UnpinArgsForCall(i int) (string, string) {
+	fake.unpinMutex.RLock()
+	defer fake.unpinMutex.RUnlock()
+	argsForCall := fake.unpinArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Model) UnpinReturns(result1 error) {
+	fake.unpinMutex.Lock()
+	defer fake.unpinMutex.Unlock()
+	fake.UnpinStub = nil
+	fake.unpinReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Model) UnpinReturnsOnCall(i int, result1 error) {
+	fake.unpinMutex.Lock()
+	defer fake.unpinMutex.Unlock()
+	fake.UnpinStub = nil
+	if fake.unpinReturnsOnCall == nil {
+		fake.unpinReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unpinReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Model) UnpinnedNeedSize(arg1 string) (db.Counts, error) {
+	fake.unpinnedNeedSizeMutex.Lock()
+	ret, specificReturn := fake.unpinnedNeedSizeReturnsOnCall[len(fake.unpinnedNeedSizeArgsForCall)]
+	fake.unpinnedNeedSizeArgsForCall = append(fake.unpinnedNeedSizeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.UnpinnedNeedSizeStub
+	fakeReturns := fake.unpinnedNeedSizeReturns
+	fake.recordInvocation("UnpinnedNeedSize", []interface{}{arg1})
+	fake.unpinnedNeedSizeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) UnpinnedNeedSizeCallCount() int {
+	fake.unpinnedNeedSizeMutex.RLock()
+	defer fake.unpinnedNeedSizeMutex.RUnlock()
+	return len(fake.unpinnedNeedSizeArgsForCall)
+}
+
+func (fake *Model) UnpinnedNeedSizeCalls(stub func(string) (db.Counts, error)) {
+	fake.unpinnedNeedSizeMutex.Lock()
+	defer fake.unpinnedNeedSizeMutex.Unlock()
+	fake.UnpinnedNeedSizeStub = stub
+}
+
+func (fake *Model) UnpinnedNeedSizeArgsForCall(i int) string {
+	fake.unpinnedNeedSizeMutex.RLock()
+	defer fake.unpinnedNeedSizeMutex.RUnlock()
+	argsForCall := fake.unpinnedNeedSizeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) UnpinnedNeedSizeReturns(result1 db.Counts, result2 error) {
+	fake.unpinnedNeedSizeMutex.Lock()
+	defer fake.unpinnedNeedSizeMutex.Unlock()
+	fake.UnpinnedNeedSizeStub = nil
+	fake.unpinnedNeedSizeReturns = struct {
+		result1 db.Counts
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) UnpinnedNeedSizeReturnsOnCall(i int, result1 db.Counts, result2 error) {
+	fake.unpinnedNeedSizeMutex.Lock()
+	defer fake.unpinnedNeedSizeMutex.Unlock()
+	fake.UnpinnedNeedSizeStub = nil
+	if fake.unpinnedNeedSizeReturnsOnCall == nil {
+		fake.unpinnedNeedSizeReturnsOnCall = make(map[int]struct {
+			result1 db.Counts
+			result2 error
+		})
+	}
+	fake.unpinnedNeedSizeReturnsOnCall[i] = struct {
+		result1 db.Counts
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) UsageReportingStats(arg1 *contract.Report, arg2 int, arg3 bool) {
 	fake.usageReportingStatsMutex.Lock()
 	fake.usageReportingStatsArgsForCall = append(fake.usageReportingStatsArgsForCall, struct {
@@ -3292,6 +4136,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.addConnectionMutex.RLock()
 	defer fake.addConnectionMutex.RUnlock()
+	fake.approveMassChangeMutex.RLock()
+	defer fake.approveMassChangeMutex.RUnlock()
 	fake.availabilityMutex.RLock()
 	defer fake.availabilityMutex.RUnlock()
 	fake.bringToFrontMutex.RLock()
@@ -3306,6 +4152,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.connectedToMutex.RUnlock()
 	fake.connectionStatsMutex.RLock()
 	defer fake.connectionStatsMutex.RUnlock()
+	fake.createFolderSnapshotMutex.RLock()
+	defer fake.createFolderSnapshotMutex.RUnlock()
 	fake.currentFolderFileMutex.RLock()
 	defer fake.currentFolderFileMutex.RUnlock()
 	fake.currentGlobalFileMutex.RLock()
@@ -3318,6 +4166,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.delayScanMutex.RUnlock()
 	fake.deviceStatisticsMutex.RLock()
 	defer fake.deviceStatisticsMutex.RUnlock()
+	fake.diffFolderSnapshotMutex.RLock()
+	defer fake.diffFolderSnapshotMutex.RUnlock()
 	fake.dismissPendingDeviceMutex.RLock()
 	defer fake.dismissPendingDeviceMutex.RUnlock()
 	fake.dismissPendingFolderMutex.RLock()
@@ -3328,6 +4178,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.folderErrorsMutex.RUnlock()
 	fake.folderProgressBytesCompletedMutex.RLock()
 	defer fake.folderProgressBytesCompletedMutex.RUnlock()
+	fake.folderSnapshotsMutex.RLock()
+	defer fake.folderSnapshotsMutex.RUnlock()
 	fake.folderStatisticsMutex.RLock()
 	defer fake.folderStatisticsMutex.RUnlock()
 	fake.getFolderVersionsMutex.RLock()
@@ -3354,6 +4206,10 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.pendingDevicesMutex.RUnlock()
 	fake.pendingFoldersMutex.RLock()
 	defer fake.pendingFoldersMutex.RUnlock()
+	fake.pinMutex.RLock()
+	defer fake.pinMutex.RUnlock()
+	fake.pinsMutex.RLock()
+	defer fake.pinsMutex.RUnlock()
 	fake.remoteNeedFolderFilesMutex.RLock()
 	defer fake.remoteNeedFolderFilesMutex.RUnlock()
 	fake.requestMutex.RLock()
@@ -3362,6 +4218,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.requestGlobalMutex.RUnlock()
 	fake.resetFolderMutex.RLock()
 	defer fake.resetFolderMutex.RUnlock()
+	fake.restoreFolderSnapshotMutex.RLock()
+	defer fake.restoreFolderSnapshotMutex.RUnlock()
 	fake.restoreFolderVersionsMutex.RLock()
 	defer fake.restoreFolderVersionsMutex.RUnlock()
 	fake.revertMutex.RLock()
@@ -3372,12 +4230,20 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.scanFolderSubdirsMutex.RUnlock()
 	fake.scanFoldersMutex.RLock()
 	defer fake.scanFoldersMutex.RUnlock()
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
 	fake.serveMutex.RLock()
 	defer fake.serveMutex.RUnlock()
 	fake.setIgnoresMutex.RLock()
 	defer fake.setIgnoresMutex.RUnlock()
 	fake.stateMutex.RLock()
 	defer fake.stateMutex.RUnlock()
+	fake.transferStatisticsMutex.RLock()
+	defer fake.transferStatisticsMutex.RUnlock()
+	fake.unpinMutex.RLock()
+	defer fake.unpinMutex.RUnlock()
+	fake.unpinnedNeedSizeMutex.RLock()
+	defer fake.unpinnedNeedSizeMutex.RUnlock()
 	fake.usageReportingStatsMutex.RLock()
 	defer fake.usageReportingStatsMutex.RUnlock()
 	fake.watchErrorMutex.RLock()
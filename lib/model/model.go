@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"strings"
 	stdsync "sync"
 	"sync/atomic"
@@ -37,7 +38,6 @@ import (
 	"github.com/syncthing/syncthing/lib/ignore"
 	"github.com/syncthing/syncthing/lib/osutil"
 	"github.com/syncthing/syncthing/lib/protocol"
-	"github.com/syncthing/syncthing/lib/rand"
 	"github.com/syncthing/syncthing/lib/scanner"
 	"github.com/syncthing/syncthing/lib/semaphore"
 	"github.com/syncthing/syncthing/lib/stats"
@@ -61,6 +61,8 @@ type service interface {
 	WatchError() error
 	ScheduleForceRescan(path string)
 	GetStatistics() (stats.FolderStatistics, error)
+	CreateSnapshot() (SnapshotInfo, error)
+	RestoreSnapshot(id string) (map[string]error, error)
 
 	getState() (folderState, time.Time, error)
 }
@@ -92,6 +94,15 @@ type Model interface {
 
 	GetFolderVersions(folder string) (map[string][]versioner.FileVersion, error)
 	RestoreFolderVersions(folder string, versions map[string]time.Time) (map[string]error, error)
+	FolderSnapshots(folder string) ([]SnapshotInfo, error)
+	CreateFolderSnapshot(folder string) (SnapshotInfo, error)
+	DiffFolderSnapshot(folder, id string) ([]SnapshotDiffEntry, error)
+	RestoreFolderSnapshot(folder, id string) (map[string]error, error)
+	ApproveMassChange(folder string) error
+	Pins(folder string) ([]string, error)
+	Pin(folder, name string) error
+	Unpin(folder, name string) error
+	UnpinnedNeedSize(folder string) (db.Counts, error)
 
 	DBSnapshot(folder string) (*db.Snapshot, error)
 	NeedFolderFiles(folder string, page, perpage int) ([]protocol.FileInfo, []protocol.FileInfo, []protocol.FileInfo, error)
@@ -108,6 +119,7 @@ type Model interface {
 	ConnectionStats() map[string]interface{}
 	DeviceStatistics() (map[protocol.DeviceID]stats.DeviceStatistics, error)
 	FolderStatistics() (map[string]stats.FolderStatistics, error)
+	TransferStatistics(period stats.TransferPeriod, since, until time.Time) (stats.TransferHistory, error)
 	UsageReportingStats(report *contract.Report, version int, preview bool)
 	ConnectedTo(remoteID protocol.DeviceID) bool
 
@@ -117,6 +129,7 @@ type Model interface {
 	DismissPendingFolder(device protocol.DeviceID, folder string) error
 
 	GlobalDirectoryTree(folder, prefix string, levels int, dirsOnly bool) ([]*TreeEntry, error)
+	Search(query SearchQuery) (*SearchResults, error)
 
 	RequestGlobal(ctx context.Context, deviceID protocol.DeviceID, folder, name string, blockNo int, offset int64, size int, hash []byte, weakHash uint32, fromTemporary bool) ([]byte, error)
 }
@@ -138,9 +151,18 @@ type model struct {
 	// globalRequestLimiter limits the amount of data in concurrent incoming
 	// requests
 	globalRequestLimiter *semaphore.Semaphore
+	// uploadFanOut limits the number of devices whose requests we serve at
+	// the same time.
+	uploadFanOut *uploadFanOut
+	// transferStats keeps the history of the file data transferred per
+	// device and folder.
+	transferStats *stats.TransferRecorder
 	// folderIOLimiter limits the number of concurrent I/O heavy operations,
 	// such as scans and pulls.
 	folderIOLimiter *semaphore.Semaphore
+	// folderBandwidth applies the folder rate limits and priorities to
+	// block transfers.
+	folderBandwidth *folderBandwidth
 	fatalChan       chan error
 	started         chan struct{}
 	keyGen          *protocol.KeyGenerator
@@ -157,7 +179,12 @@ type model struct {
 	folderVersioners               map[string]versioner.Versioner                         // folder -> versioner (may be nil)
 	folderEncryptionPasswordTokens map[string][]byte                                      // folder -> encryption token (may be missing, and only for encryption type folders)
 	folderEncryptionFailures       map[string]map[protocol.DeviceID]error                 // folder -> device -> error regarding encryption consistency (may be missing)
+	folderMassChanges              map[string]massChangeState                             // folder -> state of a detected mass change (may be missing)
+	folderChunkingDevices          map[string]map[protocol.DeviceID]bool                  // folder -> device -> whether it advertised content defined chunking
+	folderPinLists                 map[string]pinList                                     // folder -> pinned paths, cached from the database (may be missing)
+	folderUnpinnedNeed             map[string]db.Counts                                   // folder -> needed items that aren't pinned, as of the latest pull (may be missing)
 	connections                    map[string]protocol.Connection                         // connection ID -> connection
+	connPerformance                map[string]*connectionPerformance                      // connection ID -> request measurements
 	deviceConnIDs                  map[protocol.DeviceID][]string                         // device -> connection IDs (invariant: if the key exists, the value is len >= 1, with the primary connection at the start of the slice)
 	promotedConnID                 map[protocol.DeviceID]string                           // device -> latest promoted connection ID
 	connRequestLimiters            map[protocol.DeviceID]*semaphore.Semaphore
@@ -219,7 +246,10 @@ func NewModel(cfg config.Wrapper, id protocol.DeviceID, ldb *db.Lowlevel, protec
 		progressEmitter:      NewProgressEmitter(cfg, evLogger),
 		shortID:              id.Short(),
 		globalRequestLimiter: semaphore.New(1024 * cfg.Options().MaxConcurrentIncomingRequestKiB()),
+		uploadFanOut:         newUploadFanOut(cfg.Options().MaxUploadFanOut),
+		transferStats:        stats.NewTransferRecorder(ldb, transferRetention(cfg.Options())),
 		folderIOLimiter:      semaphore.New(cfg.Options().MaxFolderConcurrency()),
+		folderBandwidth:      newFolderBandwidth(cfg.RawCopy()),
 		fatalChan:            make(chan error),
 		started:              make(chan struct{}),
 		keyGen:               keyGen,
@@ -235,7 +265,12 @@ func NewModel(cfg config.Wrapper, id protocol.DeviceID, ldb *db.Lowlevel, protec
 		folderVersioners:               make(map[string]versioner.Versioner),
 		folderEncryptionPasswordTokens: make(map[string][]byte),
 		folderEncryptionFailures:       make(map[string]map[protocol.DeviceID]error),
+		folderMassChanges:              make(map[string]massChangeState),
+		folderChunkingDevices:          make(map[string]map[protocol.DeviceID]bool),
+		folderPinLists:                 make(map[string]pinList),
+		folderUnpinnedNeed:             make(map[string]db.Counts),
 		connections:                    make(map[string]protocol.Connection),
+		connPerformance:                make(map[string]*connectionPerformance),
 		deviceConnIDs:                  make(map[protocol.DeviceID][]string),
 		promotedConnID:                 make(map[protocol.DeviceID]string),
 		connRequestLimiters:            make(map[protocol.DeviceID]*semaphore.Semaphore),
@@ -251,6 +286,7 @@ func NewModel(cfg config.Wrapper, id protocol.DeviceID, ldb *db.Lowlevel, protec
 	}
 	m.Add(m.folderRunners)
 	m.Add(m.progressEmitter)
+	m.Add(m.transferStats)
 	m.Add(m.indexHandlers)
 	m.Add(svcutil.AsService(m.serve, m.String()))
 
@@ -367,6 +403,8 @@ func (m *model) addAndStartFolderLockedWithIgnores(cfg config.FolderConfiguratio
 		}
 	}
 
+	fset.SetRecordHistory(cfg.FileHistory)
+
 	v, ok := fset.Sequence(protocol.LocalDeviceID), true
 	indexHasFiles := ok && v > 0
 	if !indexHasFiles {
@@ -474,6 +512,8 @@ func (m *model) removeFolder(cfg config.FolderConfiguration) {
 	}
 
 	m.cleanupFolderLocked(cfg)
+	delete(m.folderMassChanges, cfg.ID)
+	delete(m.folderChunkingDevices, cfg.ID)
 	m.indexHandlers.Each(func(_ protocol.DeviceID, r *indexHandlerRegistry) error {
 		r.Remove(cfg.ID)
 		return nil
@@ -483,6 +523,12 @@ func (m *model) removeFolder(cfg config.FolderConfiguration) {
 
 	// Remove it from the database
 	db.DropFolder(m.db, cfg.ID)
+
+	m.removePins(cfg.ID)
+
+	if err := newSnapshotStore(cfg.ID).removeAll(); err != nil {
+		l.Infof("Failed to remove snapshots of removed folder %s: %v", cfg.Description(), err)
+	}
 }
 
 // Need to hold lock on m.mut when calling this.
@@ -495,6 +541,7 @@ func (m *model) cleanupFolderLocked(cfg config.FolderConfiguration) {
 	delete(m.folderVersioners, cfg.ID)
 	delete(m.folderEncryptionPasswordTokens, cfg.ID)
 	delete(m.folderEncryptionFailures, cfg.ID)
+	delete(m.folderUnpinnedNeed, cfg.ID)
 }
 
 func (m *model) restartFolder(from, to config.FolderConfiguration, cacheIgnoredFiles bool) error {
@@ -530,6 +577,14 @@ func (m *model) restartFolder(from, to config.FolderConfiguration, cacheIgnoredF
 	fsetNil := fset == nil
 
 	m.cleanupFolderLocked(from)
+	// Forget what devices the folder is no longer shared with told us
+	// about chunking, so that they renegotiate when shared with again. The
+	// scan on start rehashes files if chunking is no longer in use.
+	for dev := range m.folderChunkingDevices[folder] {
+		if !to.SharedWith(dev) {
+			delete(m.folderChunkingDevices[folder], dev)
+		}
+	}
 	if !to.Paused {
 		if fsetNil {
 			// Create a new fset. Might take a while and we do it under
@@ -698,14 +753,36 @@ type ConnectionStats struct {
 
 	Primary   ConnectionInfo   `json:"primary,omitempty"`
 	Secondary []ConnectionInfo `json:"secondary,omitempty"`
+
+	BandwidthLimits
+}
+
+// BandwidthLimits are the rate limits in KiB/s currently in effect, and the
+// bandwidth schedule window they are taken from, if any.
+type BandwidthLimits struct {
+	MaxSendKbps     int                     `json:"maxSendKbps"`
+	MaxRecvKbps     int                     `json:"maxRecvKbps"`
+	BandwidthWindow *config.BandwidthWindow `json:"bandwidthWindow,omitempty"`
+}
+
+func bandwidthLimits(schedule config.BandwidthSchedule, maxSendKbps, maxRecvKbps int, now time.Time) BandwidthLimits {
+	if w, ok := schedule.Active(now); ok {
+		return BandwidthLimits{
+			MaxSendKbps:     w.MaxSendKbps,
+			MaxRecvKbps:     w.MaxRecvKbps,
+			BandwidthWindow: &w,
+		}
+	}
+	return BandwidthLimits{MaxSendKbps: maxSendKbps, MaxRecvKbps: maxRecvKbps}
 }
 
 type ConnectionInfo struct {
 	protocol.Statistics
-	Address string `json:"address"`
-	Type    string `json:"type"`
-	IsLocal bool   `json:"isLocal"`
-	Crypto  string `json:"crypto"`
+	Address  string                 `json:"address"`
+	Type     string                 `json:"type"`
+	IsLocal  bool                   `json:"isLocal"`
+	Crypto   string                 `json:"crypto"`
+	Requests ConnectionRequestStats `json:"requests"`
 }
 
 // ConnectionStats returns a map with connection statistics for each device.
@@ -713,6 +790,7 @@ func (m *model) ConnectionStats() map[string]interface{} {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
+	now := time.Now()
 	res := make(map[string]interface{})
 	devs := m.cfg.Devices()
 	conns := make(map[string]ConnectionStats, len(devs))
@@ -730,6 +808,8 @@ func (m *model) ConnectionStats() map[string]interface{} {
 			Connected:     ok,
 			Paused:        deviceCfg.Paused,
 			ClientVersion: strings.TrimSpace(versionString),
+
+			BandwidthLimits: bandwidthLimits(deviceCfg.BandwidthSchedule, deviceCfg.MaxSendKbps, deviceCfg.MaxRecvKbps, now),
 		}
 		if ok {
 			conn := m.connections[connIDs[0]]
@@ -739,6 +819,7 @@ func (m *model) ConnectionStats() map[string]interface{} {
 			cs.Primary.Crypto = conn.Crypto()
 			cs.Primary.Statistics = conn.Statistics()
 			cs.Primary.Address = conn.RemoteAddr().String()
+			cs.Primary.Requests = m.connPerformance[connIDs[0]].stats()
 
 			cs.Type = cs.Primary.Type
 			cs.IsLocal = cs.Primary.IsLocal
@@ -754,6 +835,7 @@ func (m *model) ConnectionStats() map[string]interface{} {
 					Type:       conn.Type(),
 					IsLocal:    conn.IsLocal(),
 					Crypto:     conn.Crypto(),
+					Requests:   m.connPerformance[connID].stats(),
 				}
 				if sec.At.After(cs.At) {
 					cs.At = sec.At
@@ -773,10 +855,15 @@ func (m *model) ConnectionStats() map[string]interface{} {
 	res["connections"] = conns
 
 	in, out := protocol.TotalInOut()
+	opts := m.cfg.Options()
+	limits := bandwidthLimits(opts.BandwidthSchedule, opts.MaxSendKbps, opts.MaxRecvKbps, now)
 	res["total"] = map[string]interface{}{
-		"at":            time.Now().Truncate(time.Second),
-		"inBytesTotal":  in,
-		"outBytesTotal": out,
+		"at":              now.Truncate(time.Second),
+		"inBytesTotal":    in,
+		"outBytesTotal":   out,
+		"maxSendKbps":     limits.MaxSendKbps,
+		"maxRecvKbps":     limits.MaxRecvKbps,
+		"bandwidthWindow": limits.BandwidthWindow,
 	}
 
 	return res
@@ -821,15 +908,31 @@ func (m *model) FolderStatistics() (map[string]stats.FolderStatistics, error) {
 	return res, nil
 }
 
+// TransferStatistics returns the history of the file data transferred per
+// device and folder, between since and until.
+func (m *model) TransferStatistics(period stats.TransferPeriod, since, until time.Time) (stats.TransferHistory, error) {
+	return m.transferStats.History(period, since, until)
+}
+
+func transferRetention(opts config.OptionsConfiguration) stats.TransferRetention {
+	return stats.TransferRetention{
+		Hours:  opts.TransferHistoryHours,
+		Days:   opts.TransferHistoryDays,
+		Months: opts.TransferHistoryMonths,
+	}
+}
+
 type FolderCompletion struct {
-	CompletionPct float64
-	GlobalBytes   int64
-	NeedBytes     int64
-	GlobalItems   int
-	NeedItems     int
-	NeedDeletes   int
-	Sequence      int64
-	RemoteState   remoteFolderState
+	CompletionPct        float64
+	GlobalBytes          int64
+	NeedBytes            int64
+	GlobalItems          int
+	NeedItems            int
+	NeedDeletes          int
+	RemoteAvailableBytes int64 // not pinned in a folder with selective sync
+	RemoteAvailableItems int
+	Sequence             int64
+	RemoteState          remoteFolderState
 }
 
 func newFolderCompletion(global, need db.Counts, sequence int64, state remoteFolderState) FolderCompletion {
@@ -852,14 +955,18 @@ func (comp *FolderCompletion) add(other FolderCompletion) {
 	comp.GlobalItems += other.GlobalItems
 	comp.NeedItems += other.NeedItems
 	comp.NeedDeletes += other.NeedDeletes
+	comp.RemoteAvailableBytes += other.RemoteAvailableBytes
+	comp.RemoteAvailableItems += other.RemoteAvailableItems
 	comp.setCompletionPct()
 }
 
 func (comp *FolderCompletion) setCompletionPct() {
 	if comp.GlobalBytes == 0 {
 		comp.CompletionPct = 100
+	} else if comp.GlobalBytes == comp.RemoteAvailableBytes {
+		comp.CompletionPct = 100
 	} else {
-		needRatio := float64(comp.NeedBytes) / float64(comp.GlobalBytes)
+		needRatio := float64(comp.NeedBytes) / float64(comp.GlobalBytes-comp.RemoteAvailableBytes)
 		comp.CompletionPct = 100 * (1 - needRatio)
 	}
 
@@ -875,14 +982,16 @@ func (comp *FolderCompletion) setCompletionPct() {
 // Map returns the members as a map, e.g. used in api to serialize as JSON.
 func (comp *FolderCompletion) Map() map[string]interface{} {
 	return map[string]interface{}{
-		"completion":  comp.CompletionPct,
-		"globalBytes": comp.GlobalBytes,
-		"needBytes":   comp.NeedBytes,
-		"globalItems": comp.GlobalItems,
-		"needItems":   comp.NeedItems,
-		"needDeletes": comp.NeedDeletes,
-		"sequence":    comp.Sequence,
-		"remoteState": comp.RemoteState,
+		"completion":           comp.CompletionPct,
+		"globalBytes":          comp.GlobalBytes,
+		"needBytes":            comp.NeedBytes,
+		"globalItems":          comp.GlobalItems,
+		"needItems":            comp.NeedItems,
+		"needDeletes":          comp.NeedDeletes,
+		"remoteAvailableBytes": comp.RemoteAvailableBytes,
+		"remoteAvailableItems": comp.RemoteAvailableItems,
+		"sequence":             comp.Sequence,
+		"remoteState":          comp.RemoteState,
 	}
 }
 
@@ -943,6 +1052,21 @@ func (m *model) folderCompletion(device protocol.DeviceID, folder string) (Folde
 	m.mut.RUnlock()
 
 	need := snap.NeedSize(device)
+
+	// Items that aren't pinned in a folder with selective sync are not
+	// going to be pulled, so they are available remotely rather than
+	// needed.
+	var unpinned db.Counts
+	if device == protocol.LocalDeviceID {
+		if counts, ok := m.unpinnedNeedSize(folder, snap); ok {
+			unpinned = counts
+			need.Files -= unpinned.Files
+			need.Directories -= unpinned.Directories
+			need.Symlinks -= unpinned.Symlinks
+			need.Bytes -= unpinned.Bytes
+		}
+	}
+
 	need.Bytes -= downloaded
 	// This might be more than it really is, because some blocks can be of a smaller size.
 	if need.Bytes < 0 {
@@ -950,6 +1074,11 @@ func (m *model) folderCompletion(device protocol.DeviceID, folder string) (Folde
 	}
 
 	comp := newFolderCompletion(snap.GlobalSize(), need, snap.Sequence(device), state)
+	if unpinned.TotalItems() > 0 {
+		comp.RemoteAvailableBytes = unpinned.Bytes
+		comp.RemoteAvailableItems = unpinned.TotalItems()
+		comp.setCompletionPct()
+	}
 
 	l.Debugf("%v Completion(%s, %q): %v", m, device, folder, comp.Map())
 	return comp, nil
@@ -1464,8 +1593,22 @@ func (m *model) ccHandleFolders(folders []protocol.Folder, deviceCfg config.Devi
 				delete(m.folderEncryptionFailures[folder.ID], deviceID)
 			}
 		}
+		wasChunking := m.useContentDefinedChunkingRLocked(cfg)
+		if _, ok := m.folderChunkingDevices[folder.ID]; !ok {
+			m.folderChunkingDevices[folder.ID] = make(map[protocol.DeviceID]bool)
+		}
+		m.folderChunkingDevices[folder.ID][deviceID] = folder.ContentDefinedChunking
+		stoppedChunking := wasChunking && !m.useContentDefinedChunkingRLocked(cfg)
+		runner, _ := m.folderRunners.Get(folder.ID)
 		m.mut.Unlock()
 
+		if stoppedChunking && runner != nil {
+			// Have the files hashed with content defined chunking rehashed
+			// with fixed size blocks, which the device understands.
+			l.Infof("Device %v doesn't support content defined chunking in folder %s any more, rehashing", deviceID, cfg.Description())
+			runner.ScheduleScan()
+		}
+
 		// Handle indexes
 
 		if !folder.DisableTempIndexes {
@@ -1871,6 +2014,7 @@ func (m *model) Closed(conn protocol.Connection, err error) {
 	closed := m.closed[connID]
 	delete(m.closed, connID)
 	delete(m.connections, connID)
+	delete(m.connPerformance, connID)
 
 	removedIsPrimary := m.promotedConnID[deviceID] == connID
 	remainingConns := without(m.deviceConnIDs[deviceID], connID)
@@ -1946,6 +2090,20 @@ func (r *requestResponse) Wait() {
 	<-r.closed
 }
 
+// connContext returns a context that is cancelled when the connection is
+// closed, or when the returned function is called.
+func connContext(conn protocol.Connection) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-conn.Closed():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
 // Request returns the specified data segment by reading it from local disk.
 // Implements the protocol.Model interface.
 func (m *model) Request(conn protocol.Connection, req *protocol.Request) (out protocol.RequestResponse, err error) {
@@ -1997,6 +2155,23 @@ func (m *model) Request(conn protocol.Connection, req *protocol.Request) (out pr
 		return nil, protocol.ErrInvalid
 	}
 
+	// Leave the device to get the block elsewhere if we are already
+	// serving as many devices as we may, unless nobody else has it.
+	if deviceID != protocol.LocalDeviceID && !m.uploadFanOut.allow(deviceID, time.Now()) && m.hasOtherSources(req.Folder, deviceID, req.Name, req.Offset) {
+		l.Debugf("%v REQ(in) beyond upload fan-out: %s: %q / %q o=%d s=%d", m, deviceID.Short(), req.Folder, req.Name, req.Offset, req.Size)
+		return nil, protocol.ErrGeneric
+	}
+
+	// Give way to higher priority folders and respect the folder's rate
+	// limit, for as long as the connection is open.
+	ctx, cancel := connContext(conn)
+	sent, err := m.folderBandwidth.waitSend(ctx, folderCfg, deviceID, conn.IsLocal(), req.Size)
+	cancel()
+	if err != nil {
+		l.Debugf("%v REQ(in) on closed connection: %s: %q / %q o=%d s=%d", m, deviceID.Short(), req.Folder, req.Name, req.Offset, req.Size)
+		return nil, protocol.ErrGeneric
+	}
+
 	// Restrict parallel requests by connection/device
 
 	m.mut.RLock()
@@ -2006,12 +2181,19 @@ func (m *model) Request(conn protocol.Connection, req *protocol.Request) (out pr
 	// The requestResponse releases the bytes to the buffer pool and the
 	// limiters when its Close method is called.
 	res := newLimitedRequestResponse(int(req.Size), limiter, m.globalRequestLimiter)
+	go func() {
+		res.Wait()
+		sent()
+	}()
 
 	defer func() {
 		// Close it ourselves if it isn't returned due to an error
 		if err != nil {
 			res.Close()
+			return
 		}
+		m.transferStats.DeviceTransfer(deviceID, 0, int64(req.Size))
+		m.transferStats.FolderTransfer(req.Folder, 0, int64(req.Size))
 	}()
 
 	// Grab the FS after limiting, as it causes I/O and we want to minimize
@@ -2108,9 +2290,9 @@ func (m *model) recheckFile(deviceID protocol.DeviceID, folder, name string, off
 		return
 	}
 
-	blockIndex := int(offset / int64(cf.BlockSize()))
-	if blockIndex >= len(cf.Blocks) {
-		l.Debugf("%v recheckFile: %s: %q / %q i=%d: block index too far", m, deviceID, folder, name, blockIndex)
+	blockIndex := cf.BlockIndex(offset)
+	if blockIndex < 0 {
+		l.Debugf("%v recheckFile: %s: %q / %q o=%d: no block at offset", m, deviceID, folder, name, offset)
 		return
 	}
 
@@ -2326,6 +2508,7 @@ func (m *model) AddConnection(conn protocol.Connection, hello protocol.Hello) {
 	m.mut.Lock()
 
 	m.connections[connID] = conn
+	m.connPerformance[connID] = newConnectionPerformance()
 	m.closed[connID] = closed
 	m.helloMessages[deviceID] = hello
 	m.deviceConnIDs[deviceID] = append(m.deviceConnIDs[deviceID], connID)
@@ -2463,40 +2646,62 @@ func (m *model) deviceDidCloseRLocked(deviceID protocol.DeviceID, duration time.
 }
 
 func (m *model) RequestGlobal(ctx context.Context, deviceID protocol.DeviceID, folder, name string, blockNo int, offset int64, size int, hash []byte, weakHash uint32, fromTemporary bool) ([]byte, error) {
-	conn, connOK := m.requestConnectionForDevice(deviceID)
+	conn, perf, connOK := m.requestConnectionForDevice(deviceID, size)
 	if !connOK {
 		return nil, fmt.Errorf("requestGlobal: no connection to device: %s", deviceID.Short())
 	}
 
+	m.mut.RLock()
+	fcfg, ok := m.folderCfgs[folder]
+	m.mut.RUnlock()
+	if ok {
+		received, err := m.folderBandwidth.waitRecv(ctx, fcfg, deviceID, conn.IsLocal(), size)
+		if err != nil {
+			return nil, err
+		}
+		defer received()
+	}
+
 	l.Debugf("%v REQ(out): %s (%s): %q / %q b=%d o=%d s=%d h=%x wh=%x ft=%t", m, deviceID.Short(), conn, folder, name, blockNo, offset, size, hash, weakHash, fromTemporary)
-	return conn.Request(ctx, &protocol.Request{Folder: folder, Name: name, BlockNo: blockNo, Offset: offset, Size: size, Hash: hash, WeakHash: weakHash, FromTemporary: fromTemporary})
+	done := perf.started(size)
+	data, err := conn.Request(ctx, &protocol.Request{Folder: folder, Name: name, BlockNo: blockNo, Offset: offset, Size: size, Hash: hash, WeakHash: weakHash, FromTemporary: fromTemporary})
+	done(len(data), err)
+	if err == nil {
+		m.transferStats.DeviceTransfer(deviceID, int64(len(data)), 0)
+		m.transferStats.FolderTransfer(folder, int64(len(data)), 0)
+	}
+	return data, err
 }
 
 // requestConnectionForDevice returns a connection to the given device, to
-// be used for sending a request. If there is only one device connection,
-// this is the one to use. If there are multiple then we avoid the first
-// ("primary") connection, which is dedicated to index data, and pick a
-// random one of the others.
-func (m *model) requestConnectionForDevice(deviceID protocol.DeviceID) (protocol.Connection, bool) {
+// be used for sending a request of the given size, and the measurements of
+// that connection. If there are multiple connections we pick the one where
+// the request is expected to complete first, going by the latency and
+// bandwidth seen so far and the requests already pending. Ties go to the
+// non-primary connections, as the primary also carries index data.
+func (m *model) requestConnectionForDevice(deviceID protocol.DeviceID, size int) (protocol.Connection, *connectionPerformance, bool) {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
 
 	connIDs, ok := m.deviceConnIDs[deviceID]
 	if !ok {
-		return nil, false
+		return nil, nil, false
 	}
 
 	// If there is an entry in deviceConns, it always contains at least one
 	// connection.
 	connID := connIDs[0]
 	if len(connIDs) > 1 {
-		// Pick a random connection of the non-primary ones
-		idx := rand.Intn(len(connIDs)-1) + 1
-		connID = connIDs[idx]
+		candidates := append(slices.Clone(connIDs[1:]), connIDs[0])
+		perfs := make([]*connectionPerformance, len(candidates))
+		for i, id := range candidates {
+			perfs[i] = m.connPerformance[id]
+		}
+		connID = candidates[fastestConnection(perfs, size)]
 	}
 
 	conn, connOK := m.connections[connID]
-	return conn, connOK
+	return conn, m.connPerformance[connID], connOK
 }
 
 func (m *model) ScanFolders() map[string]error {
@@ -2617,6 +2822,10 @@ func (m *model) generateClusterConfigRLocked(device protocol.DeviceID) (*protoco
 			DisableTempIndexes: folderCfg.DisableTempIndexes,
 		}
 
+		// Block lists with variable block sizes can't be encrypted, so we
+		// never offer content defined chunking for encrypted folders.
+		protocolFolder.ContentDefinedChunking = folderCfg.ContentDefinedChunking && folderCfg.Type != config.FolderTypeReceiveEncrypted
+
 		fs := m.folderFiles[folderCfg.ID]
 
 		// Even if we aren't paused, if we haven't started the folder yet
@@ -2665,6 +2874,32 @@ func (m *model) generateClusterConfigRLocked(device protocol.DeviceID) (*protoco
 	return message, passwords
 }
 
+// useContentDefinedChunking returns true if files in the folder should be
+// hashed with content defined chunking. That's the case if it's enabled for
+// the folder and all devices we share it with have told us they support it
+// in their latest cluster config. Until every device has connected we use
+// fixed size blocks, which everyone understands.
+func (m *model) useContentDefinedChunking(fcfg config.FolderConfiguration) bool {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return m.useContentDefinedChunkingRLocked(fcfg)
+}
+
+func (m *model) useContentDefinedChunkingRLocked(fcfg config.FolderConfiguration) bool {
+	if !fcfg.ContentDefinedChunking || fcfg.Type == config.FolderTypeReceiveEncrypted {
+		return false
+	}
+	for _, dev := range fcfg.Devices {
+		if dev.DeviceID == m.id {
+			continue
+		}
+		if dev.EncryptionPassword != "" || !m.folderChunkingDevices[fcfg.ID][dev.DeviceID] {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *model) State(folder string) (string, time.Time, error) {
 	m.mut.RLock()
 	runner, ok := m.folderRunners.Get(folder)
@@ -2702,6 +2937,12 @@ func (m *model) WatchError(folder string) error {
 }
 
 func (m *model) Override(folder string) {
+	// Overriding a folder that was paused due to a mass change rejects
+	// the change, making our local state win once the folder is resumed.
+	if m.resolveMassChange(folder, massChangeRejected) {
+		return
+	}
+
 	// Grab the runner and the file set.
 
 	m.mut.RLock()
@@ -2862,6 +3103,63 @@ func (m *model) RestoreFolderVersions(folder string, versions map[string]time.Ti
 	return restoreErrors, nil
 }
 
+func (m *model) FolderSnapshots(folder string) ([]SnapshotInfo, error) {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	m.mut.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return newSnapshotStore(folder).list()
+}
+
+func (m *model) CreateFolderSnapshot(folder string) (SnapshotInfo, error) {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	runner, _ := m.folderRunners.Get(folder)
+	m.mut.RUnlock()
+	if err != nil {
+		return SnapshotInfo{}, err
+	}
+
+	return runner.CreateSnapshot()
+}
+
+func (m *model) DiffFolderSnapshot(folder, id string) ([]SnapshotDiffEntry, error) {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	fset := m.folderFiles[folder]
+	m.mut.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	fsnap, err := newSnapshotStore(folder).load(id)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := fset.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer snap.Release()
+
+	return diffFolderSnapshot(fsnap, snap), nil
+}
+
+func (m *model) RestoreFolderSnapshot(folder, id string) (map[string]error, error) {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	runner, _ := m.folderRunners.Get(folder)
+	m.mut.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return runner.RestoreSnapshot(id)
+}
+
 func (m *model) Availability(folder string, file protocol.FileInfo, block protocol.BlockInfo) ([]Availability, error) {
 	m.mut.RLock()
 	defer m.mut.RUnlock()
@@ -2923,13 +3221,41 @@ func (m *model) fileAvailabilityRLocked(cfg config.FolderConfiguration, snap *db
 func (m *model) blockAvailabilityFromTemporaryRLocked(cfg config.FolderConfiguration, file protocol.FileInfo, block protocol.BlockInfo) []Availability {
 	var availabilities []Availability
 	for _, device := range cfg.Devices {
-		if m.deviceDownloads[device.DeviceID].Has(cfg.ID, file.Name, file.Version, int(block.Offset/int64(file.BlockSize()))) {
+		if m.deviceDownloads[device.DeviceID].Has(cfg.ID, file.Name, file.Version, file.BlockIndex(block.Offset)) {
 			availabilities = append(availabilities, Availability{ID: device.DeviceID, FromTemporary: true})
 		}
 	}
 	return availabilities
 }
 
+// hasOtherSources returns whether a connected device other than the
+// requester has the block at the offset of the global version of the file.
+func (m *model) hasOtherSources(folder string, requester protocol.DeviceID, name string, offset int64) bool {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	fset, ok := m.folderFiles[folder]
+	if !ok {
+		return false
+	}
+	snap, err := fset.Snapshot()
+	if err != nil {
+		return false
+	}
+	defer snap.Release()
+
+	gf, ok := snap.GetGlobal(name)
+	if !ok {
+		return false
+	}
+	for _, av := range m.blockAvailabilityRLocked(m.folderCfgs[folder], snap, gf, protocol.BlockInfo{Offset: offset}) {
+		if av.ID != requester {
+			return true
+		}
+	}
+	return false
+}
+
 // BringToFront bumps the given files priority in the job queue.
 func (m *model) BringToFront(folder, file string) {
 	m.mut.RLock()
@@ -3131,6 +3457,9 @@ func (m *model) CommitConfiguration(from, to config.Configuration) bool {
 	m.cleanPending(toDevices, toFolders, ignoredDevices, removedFolders)
 
 	m.globalRequestLimiter.SetCapacity(1024 * to.Options.MaxConcurrentIncomingRequestKiB())
+	m.uploadFanOut.setMaxDevices(to.Options.MaxUploadFanOut)
+	m.folderBandwidth.limits.CommitConfiguration(from, to)
+	m.transferStats.SetRetention(transferRetention(to.Options))
 	m.folderIOLimiter.SetCapacity(to.Options.MaxFolderConcurrency())
 
 	// Some options don't require restart as those components handle it fine
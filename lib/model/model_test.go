@@ -4141,3 +4141,58 @@ type modtimeTruncatingFileInfo struct {
 func (fi modtimeTruncatingFileInfo) ModTime() time.Time {
 	return fi.FileInfo.ModTime().Truncate(fi.trunc)
 }
+
+func TestContentDefinedChunkingNegotiation(t *testing.T) {
+	w, fcfg, wcfgCancel := newDefaultCfgWrapper()
+	defer wcfgCancel()
+	fcfg.ContentDefinedChunking = true
+	waiter, err := w.Modify(func(cfg *config.Configuration) {
+		cfg.SetFolder(fcfg)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiter.Wait()
+	m, fc := setupModelWithConnectionFromWrapper(t, w)
+	defer cleanupModel(m)
+
+	cc, _ := m.generateClusterConfig(device1)
+	if len(cc.Folders) != 1 || !cc.Folders[0].ContentDefinedChunking {
+		t.Error("expected content defined chunking to be advertised")
+	}
+
+	if m.useContentDefinedChunking(fcfg) {
+		t.Error("content defined chunking used before the remote advertised it")
+	}
+
+	cc = basicClusterConfig(myID, device1, fcfg.ID)
+	cc.Folders[0].ContentDefinedChunking = true
+	if err := m.ClusterConfig(fc, cc); err != nil {
+		t.Fatal(err)
+	}
+	if !m.useContentDefinedChunking(fcfg) {
+		t.Error("content defined chunking not used although all devices support it")
+	}
+
+	// Restarting the folder for a change of its devices keeps what the
+	// remaining devices told us.
+	setDevice(t, w, newDeviceConfiguration(w.DefaultDevice(), device2, "device2"))
+	withDevice2 := fcfg.Copy()
+	withDevice2.Devices = append(withDevice2.Devices, config.FolderDeviceConfiguration{DeviceID: device2})
+	setFolder(t, w, withDevice2)
+	if m.useContentDefinedChunking(withDevice2) {
+		t.Error("content defined chunking used before the added device advertised it")
+	}
+	setFolder(t, w, fcfg)
+	if !m.useContentDefinedChunking(fcfg) {
+		t.Error("content defined chunking not used after the device list changed back")
+	}
+
+	cc.Folders[0].ContentDefinedChunking = false
+	if err := m.ClusterConfig(fc, cc); err != nil {
+		t.Fatal(err)
+	}
+	if m.useContentDefinedChunking(fcfg) {
+		t.Error("content defined chunking used after the remote stopped advertising it")
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+var errInvalidPin = errors.New("invalid path to pin")
+
+// A pinList holds the paths that are synced in a folder with selective
+// sync. A pinned directory includes everything below it.
+type pinList []string
+
+func (p pinList) pinned(name string, isDir bool) bool {
+	for _, pin := range p {
+		if name == pin || strings.HasPrefix(name, pin+string(filepath.Separator)) {
+			return true
+		}
+		// The parent directories of pinned items are needed to hold them.
+		if isDir && strings.HasPrefix(pin, name+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizePin(name string) (string, error) {
+	name = strings.Trim(filepath.Clean(filepath.FromSlash(name)), string(filepath.Separator))
+	if name == "" || name == "." || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q", errInvalidPin, name)
+	}
+	return name, nil
+}
+
+func pinsKey(folder string) string {
+	return "folderPins/" + folder
+}
+
+func (m *model) loadPins(folder string) (pinList, error) {
+	bs, ok, err := db.NewMiscDataNamespace(m.db).Bytes(pinsKey(folder))
+	if err != nil || !ok {
+		return nil, err
+	}
+	var pins pinList
+	if err := json.Unmarshal(bs, &pins); err != nil {
+		return nil, err
+	}
+	return pins, nil
+}
+
+func (m *model) savePins(folder string, pins pinList) error {
+	miscDB := db.NewMiscDataNamespace(m.db)
+	if len(pins) == 0 {
+		return miscDB.Delete(pinsKey(folder))
+	}
+	bs, err := json.Marshal(pins)
+	if err != nil {
+		return err
+	}
+	return miscDB.PutBytes(pinsKey(folder), bs)
+}
+
+// folderPins returns the pins of a folder with selective sync, or nil if
+// the folder syncs everything.
+func (m *model) folderPins(folder string) (pinList, bool) {
+	fcfg, ok := m.cfg.Folder(folder)
+	if !ok || !fcfg.SelectiveSync {
+		return nil, false
+	}
+	pins, err := m.cachedPins(folder)
+	if err != nil {
+		l.Warnf("Failed to load pinned paths of folder %s: %v", fcfg.Description(), err)
+	}
+	return pins, true
+}
+
+// cachedPins returns the pins of the folder, loading them from the database
+// the first time.
+func (m *model) cachedPins(folder string) (pinList, error) {
+	m.mut.RLock()
+	pins, ok := m.folderPinLists[folder]
+	m.mut.RUnlock()
+	if ok {
+		return pins, nil
+	}
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	return m.pinsLocked(folder)
+}
+
+func (m *model) pinsLocked(folder string) (pinList, error) {
+	if pins, ok := m.folderPinLists[folder]; ok {
+		return pins, nil
+	}
+	pins, err := m.loadPins(folder)
+	if err != nil {
+		return nil, err
+	}
+	m.folderPinLists[folder] = pins
+	return pins, nil
+}
+
+// Pins returns the paths that are pinned in the folder. The pins only have
+// an effect if selective sync is enabled for the folder.
+func (m *model) Pins(folder string) ([]string, error) {
+	if _, ok := m.cfg.Folder(folder); !ok {
+		return nil, ErrFolderMissing
+	}
+	pins, err := m.cachedPins(folder)
+	if err != nil {
+		return nil, err
+	}
+	if pins == nil {
+		pins = pinList{}
+	}
+	return slices.Clone(pins), nil
+}
+
+// Pin marks the given path in the folder for syncing, and triggers a pull
+// to fetch it.
+func (m *model) Pin(folder, name string) error {
+	return m.modifyPins(folder, name, func(pins pinList, name string) pinList {
+		if slices.Contains(pins, name) {
+			return pins
+		}
+		pins = append(pins, name)
+		slices.Sort(pins)
+		return pins
+	})
+}
+
+// Unpin stops pulling changes to the given path in the folder. Files that
+// are already present are kept.
+func (m *model) Unpin(folder, name string) error {
+	return m.modifyPins(folder, name, func(pins pinList, name string) pinList {
+		return slices.DeleteFunc(pins, func(pin string) bool {
+			return pin == name
+		})
+	})
+}
+
+func (m *model) modifyPins(folder, name string, modify func(pinList, string) pinList) error {
+	if _, ok := m.cfg.Folder(folder); !ok {
+		return ErrFolderMissing
+	}
+	name, err := normalizePin(name)
+	if err != nil {
+		return err
+	}
+
+	m.mut.Lock()
+	pins, err := m.pinsLocked(folder)
+	if err == nil {
+		// The cached list is shared, so it is modified as a copy.
+		pins = modify(slices.Clone(pins), name)
+		if err = m.savePins(folder, pins); err == nil {
+			m.folderPinLists[folder] = pins
+			delete(m.folderUnpinnedNeed, folder)
+		}
+	}
+	runner, ok := m.folderRunners.Get(folder)
+	m.mut.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		runner.SchedulePull()
+	}
+	return nil
+}
+
+func (m *model) removePins(folder string) {
+	m.mut.Lock()
+	delete(m.folderPinLists, folder)
+	delete(m.folderUnpinnedNeed, folder)
+	m.mut.Unlock()
+	if err := m.savePins(folder, nil); err != nil {
+		l.Infof("Failed to remove pinned paths of removed folder %s: %v", folder, err)
+	}
+}
+
+// addUnpinned adds an item the local device needs but won't pull, as it
+// isn't pinned, to the counts.
+func addUnpinned(counts *db.Counts, f protocol.FileInfo) {
+	switch {
+	case f.IsDirectory():
+		counts.Directories++
+	case f.IsSymlink():
+		counts.Symlinks++
+	default:
+		counts.Files++
+	}
+	counts.Bytes += f.FileSize()
+}
+
+// setUnpinnedNeed records the counts of the unpinned items needed in a
+// folder with selective sync, as found by the puller going through the
+// need list.
+func (m *model) setUnpinnedNeed(folder string, counts db.Counts) {
+	m.mut.Lock()
+	m.folderUnpinnedNeed[folder] = counts
+	m.mut.Unlock()
+}
+
+// unpinnedNeedSize returns the counts of the items the local device needs
+// in a folder with selective sync, which won't be pulled as they aren't
+// pinned. These are available remotely and don't count as out of sync. The
+// counts are those recorded by the latest pull, only going through the
+// need list when there is none since the pins changed.
+func (m *model) unpinnedNeedSize(folder string, snap *db.Snapshot) (db.Counts, bool) {
+	pins, ok := m.folderPins(folder)
+	if !ok {
+		return db.Counts{}, false
+	}
+	m.mut.RLock()
+	counts, ok := m.folderUnpinnedNeed[folder]
+	m.mut.RUnlock()
+	if ok {
+		return counts, true
+	}
+
+	snap.WithNeedTruncated(protocol.LocalDeviceID, func(f protocol.FileInfo) bool {
+		if !f.IsDeleted() && !pins.pinned(f.FileName(), f.IsDirectory()) {
+			addUnpinned(&counts, f)
+		}
+		return true
+	})
+	m.setUnpinnedNeed(folder, counts)
+	return counts, true
+}
+
+// UnpinnedNeedSize returns the counts of the items the local device needs
+// in the folder but won't pull, as they aren't pinned.
+func (m *model) UnpinnedNeedSize(folder string) (db.Counts, error) {
+	snap, err := m.DBSnapshot(folder)
+	if err != nil {
+		return db.Counts{}, err
+	}
+	defer snap.Release()
+	counts, _ := m.unpinnedNeedSize(folder, snap)
+	return counts, nil
+}
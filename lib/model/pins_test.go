@@ -0,0 +1,136 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestPinListPinned(t *testing.T) {
+	pins := pinList{filepath.Join("a", "b"), "c"}
+	cases := []struct {
+		name   string
+		isDir  bool
+		pinned bool
+	}{
+		{"a", true, true},
+		{"a", false, false},
+		{filepath.Join("a", "b"), false, true},
+		{filepath.Join("a", "b", "c"), false, true},
+		{filepath.Join("a", "bc"), false, false},
+		{filepath.Join("a", "x"), true, false},
+		{"c", false, true},
+		{filepath.Join("c", "d"), true, true},
+		{"d", true, false},
+	}
+	for _, tc := range cases {
+		if res := pins.pinned(tc.name, tc.isDir); res != tc.pinned {
+			t.Errorf("pinned(%q, %v) = %v, expected %v", tc.name, tc.isDir, res, tc.pinned)
+		}
+	}
+}
+
+func TestNormalizePin(t *testing.T) {
+	for _, name := range []string{"", ".", "/", "..", "../a", "a/../.."} {
+		if _, err := normalizePin(name); !errors.Is(err, errInvalidPin) {
+			t.Errorf("expected %q to be invalid, got %v", name, err)
+		}
+	}
+	if name, err := normalizePin("/a/b/../c/"); err != nil || name != filepath.Join("a", "c") {
+		t.Errorf("unexpected normalized pin %q, %v", name, err)
+	}
+}
+
+func TestSelectiveSync(t *testing.T) {
+	w, fcfg, wCancel := newDefaultCfgWrapper()
+	defer wCancel()
+	fcfg.SelectiveSync = true
+	waiter, err := w.Modify(func(cfg *config.Configuration) {
+		cfg.SetFolder(fcfg)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiter.Wait()
+	m := setupModel(t, w)
+	m.cancel()
+	<-m.stopped
+	r, _ := m.folderRunners.Get(fcfg.ID)
+	f := r.(*sendReceiveFolder)
+	f.tempPullErrors = make(map[string]string)
+	f.ctx = context.Background()
+
+	var remote []protocol.FileInfo
+	for _, name := range []string{"pinned", filepath.Join("pinned", "sub"), "other"} {
+		remote = append(remote, protocol.FileInfo{
+			Name:    name,
+			Type:    protocol.FileInfoTypeDirectory,
+			Version: protocol.Vector{}.Update(device1.Short()),
+		})
+	}
+	f.fset.Update(device1, remote)
+
+	if err := m.Pin(f.ID, "pinned/sub"); err != nil {
+		t.Fatal(err)
+	}
+	if pins, err := m.Pins(f.ID); err != nil || !slices.Equal(pins, []string{filepath.Join("pinned", "sub")}) {
+		t.Fatal("unexpected pins", pins, err)
+	}
+
+	f.pull()
+
+	for _, name := range []string{"pinned", filepath.Join("pinned", "sub")} {
+		if _, err := f.mtimefs.Lstat(name); err != nil {
+			t.Errorf("expected pinned directory %s to be pulled: %v", name, err)
+		}
+	}
+	if _, err := f.mtimefs.Lstat("other"); err == nil {
+		t.Error("unpinned directory was pulled")
+	}
+
+	comp, err := m.folderCompletion(protocol.LocalDeviceID, f.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comp.NeedItems != 0 || comp.RemoteAvailableItems != 1 || comp.CompletionPct != 100 {
+		t.Errorf("unexpected completion %+v", comp)
+	}
+
+	// The pull recorded the unpinned items, which the summary doesn't count
+	// as needed either.
+	if counts := m.folderUnpinnedNeed[f.ID]; counts.Directories != 1 {
+		t.Errorf("expected the pull to record one unpinned directory, got %+v", counts)
+	}
+	sum, err := NewFolderSummaryService(w, m, myID, events.NoopLogger).Summary(f.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.NeedTotalItems != 0 || sum.RemoteAvailableItems != 1 {
+		t.Errorf("unexpected summary %+v", sum)
+	}
+
+	if err := m.Unpin(f.ID, "pinned/sub"); err != nil {
+		t.Fatal(err)
+	}
+	if pins, err := m.Pins(f.ID); err != nil || len(pins) != 0 {
+		t.Error("expected no pins after unpinning, got", pins, err)
+	}
+	if _, ok := m.folderUnpinnedNeed[f.ID]; ok {
+		t.Error("expected the unpinned counts to be dropped when the pins change")
+	}
+	if counts, _ := m.UnpinnedNeedSize(f.ID); counts.Directories != 1 {
+		t.Errorf("expected the unpinned directory to be counted again, got %+v", counts)
+	}
+}
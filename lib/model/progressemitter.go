@@ -17,10 +17,16 @@ import (
 	"github.com/syncthing/syncthing/lib/sync"
 )
 
+// Partially downloaded blocks are announced to other devices this often,
+// separately from the DownloadProgress event interval, so that they can
+// fetch those blocks from us early on.
+const peerProgressInterval = time.Second
+
 type ProgressEmitter struct {
 	cfg                config.Wrapper
 	registry           map[string]map[string]*sharedPullerState // folder: name: puller
 	interval           time.Duration
+	peerInterval       time.Duration
 	minBlocks          int
 	sentDownloadStates map[protocol.DeviceID]*sentDownloadState // States representing what we've sent to the other peer via DownloadProgress messages.
 	connections        map[protocol.DeviceID]protocol.Connection
@@ -29,7 +35,8 @@ type ProgressEmitter struct {
 	evLogger           events.Logger
 	mut                sync.Mutex
 
-	timer *time.Timer
+	timer     *time.Timer
+	peerTimer *time.Timer
 }
 
 type progressUpdate struct {
@@ -43,12 +50,15 @@ func (p progressUpdate) send(ctx context.Context) {
 }
 
 // NewProgressEmitter creates a new progress emitter which emits
-// DownloadProgress events every interval.
+// DownloadProgress events every interval, and sends DownloadProgress
+// messages to other devices every peer interval.
 func NewProgressEmitter(cfg config.Wrapper, evLogger events.Logger) *ProgressEmitter {
 	t := &ProgressEmitter{
 		cfg:                cfg,
 		registry:           make(map[string]map[string]*sharedPullerState),
+		peerInterval:       peerProgressInterval,
 		timer:              time.NewTimer(time.Millisecond),
+		peerTimer:          time.NewTimer(time.Millisecond),
 		sentDownloadStates: make(map[protocol.DeviceID]*sentDownloadState),
 		connections:        make(map[protocol.DeviceID]protocol.Connection),
 		foldersByConns:     make(map[protocol.DeviceID][]string),
@@ -80,7 +90,6 @@ func (t *ProgressEmitter) Serve(ctx context.Context) error {
 
 			newLastUpdated := lastUpdate
 			newCount = t.lenRegistryLocked()
-			var progressUpdates []progressUpdate
 			for _, pullers := range t.registry {
 				for _, puller := range pullers {
 					if updated := puller.Updated(); updated.After(newLastUpdated) {
@@ -93,7 +102,6 @@ func (t *ProgressEmitter) Serve(ctx context.Context) error {
 				lastUpdate = newLastUpdated
 				lastCount = newCount
 				t.sendDownloadProgressEventLocked()
-				progressUpdates = t.computeProgressUpdates()
 			} else {
 				l.Debugln("progress emitter: nothing new")
 			}
@@ -102,6 +110,13 @@ func (t *ProgressEmitter) Serve(ctx context.Context) error {
 				t.timer.Reset(t.interval)
 			}
 			t.mut.Unlock()
+		case <-t.peerTimer.C:
+			t.mut.Lock()
+			progressUpdates := t.computeProgressUpdates()
+			if !t.emptyLocked() {
+				t.peerTimer.Reset(t.peerInterval)
+			}
+			t.mut.Unlock()
 
 			// Do the sending outside of the lock.
 			// If these send block, the whole process of reporting progress to others stops, but that's probably fine.
@@ -253,6 +268,7 @@ func (t *ProgressEmitter) Register(s *sharedPullerState) {
 	l.Debugln("progress emitter: registering", s.folder, s.file.Name)
 	if t.emptyLocked() {
 		t.timer.Reset(t.interval)
+		t.peerTimer.Reset(t.peerInterval)
 	}
 	if _, ok := t.registry[s.folder]; !ok {
 		t.registry[s.folder] = make(map[string]*sharedPullerState)
@@ -18,6 +18,7 @@ import (
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/protocol"
+	protocolmocks "github.com/syncthing/syncthing/lib/protocol/mocks"
 	"github.com/syncthing/syncthing/lib/sync"
 )
 
@@ -112,6 +113,56 @@ func TestProgressEmitter(t *testing.T) {
 	expectTimeout(w, t)
 }
 
+func TestPeerProgressInterval(t *testing.T) {
+	c, cfgCancel := newConfigWrapper(config.Configuration{Version: config.CurrentVersion})
+	defer os.Remove(c.ConfigPath())
+	defer cfgCancel()
+	waiter, err := c.Modify(func(cfg *config.Configuration) {
+		cfg.Options.ProgressUpdateIntervalS = 60
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiter.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	evLogger := events.NewLogger()
+	go evLogger.Serve(ctx)
+	defer cancel()
+
+	sent := make(chan *protocol.DownloadProgress, 10)
+	conn := new(protocolmocks.Connection)
+	conn.DownloadProgressCalls(func(_ context.Context, dp *protocol.DownloadProgress) {
+		sent <- dp
+	})
+
+	p := NewProgressEmitter(c, evLogger)
+	p.peerInterval = 10 * time.Millisecond
+	p.temporaryIndexSubscribe(conn, []string{"folder"})
+	go p.Serve(ctx)
+
+	// DownloadProgress events are due once a minute, but the available
+	// block is sent to the other device long before that.
+	p.Register(&sharedPullerState{
+		folder: "folder",
+		file: protocol.FileInfo{
+			Name:   "file",
+			Blocks: make([]protocol.BlockInfo, 11),
+		},
+		mut:       sync.NewRWMutex(),
+		available: []int{1},
+	})
+
+	select {
+	case dp := <-sent:
+		if len(dp.Updates) != 1 || len(dp.Updates[0].BlockIndexes) != 1 || dp.Updates[0].BlockIndexes[0] != 1 {
+			t.Errorf("Unexpected download progress: %v", dp)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for download progress")
+	}
+}
+
 func TestSendDownloadProgressMessages(t *testing.T) {
 	c, cfgCancel := newConfigWrapper(config.Configuration{Version: config.CurrentVersion})
 	defer os.Remove(c.ConfigPath())
@@ -0,0 +1,187 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// A SearchQuery selects files from the index. Unset fields don't restrict
+// the search.
+type SearchQuery struct {
+	// The folders to search, or all of them.
+	Folders []string
+	// A glob pattern like "*.psd", matched case-insensitively against the
+	// base name of the files.
+	Glob string
+	// Matched against the whole path, with forward slashes.
+	Regexp *regexp.Regexp
+	// Size range in bytes, inclusive. A maximum of zero means no limit.
+	MinSize int64
+	MaxSize int64
+	// Modification time range, inclusive.
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+	Types          []protocol.FileInfoType
+	// Only files of which the device has the global version, or which it
+	// needs.
+	HaveDevice protocol.DeviceID
+	NeedDevice protocol.DeviceID
+	// Search the files as they are on this device, rather than the global
+	// versions.
+	Local bool
+	// Which of the matching files to return.
+	Offset int
+	Limit  int
+}
+
+type SearchResult struct {
+	Folder  string    `json:"folder"`
+	Name    string    `json:"name"`
+	Type    string    `json:"type"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	// The devices that have the global version.
+	Availability []protocol.DeviceID `json:"availability"`
+}
+
+type SearchResults struct {
+	// The number of matching files, of which Results are those between
+	// Offset and Limit.
+	Total   int             `json:"total"`
+	Results []*SearchResult `json:"results"`
+}
+
+func (m *model) Search(query SearchQuery) (*SearchResults, error) {
+	if query.Glob != "" {
+		// Check the pattern up front, as matching ignores errors.
+		query.Glob = strings.ToLower(query.Glob)
+		if _, err := path.Match(query.Glob, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mut.RLock()
+	folders := query.Folders
+	if len(folders) == 0 {
+		for folder := range m.folderFiles {
+			folders = append(folders, folder)
+		}
+		slices.Sort(folders)
+	}
+	sets := make([]*db.FileSet, len(folders))
+	for i, folder := range folders {
+		fset, ok := m.folderFiles[folder]
+		if !ok {
+			m.mut.RUnlock()
+			return nil, ErrFolderMissing
+		}
+		sets[i] = fset
+	}
+	m.mut.RUnlock()
+
+	// The database knows this device by a placeholder ID.
+	toDB := func(id protocol.DeviceID) protocol.DeviceID {
+		if id == m.id {
+			return protocol.LocalDeviceID
+		}
+		return id
+	}
+	query.HaveDevice = toDB(query.HaveDevice)
+	query.NeedDevice = toDB(query.NeedDevice)
+
+	res := &SearchResults{Results: []*SearchResult{}}
+	for i, fset := range sets {
+		if err := m.searchFolder(folders[i], fset, query, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (m *model) searchFolder(folder string, fset *db.FileSet, query SearchQuery, res *SearchResults) error {
+	snap, err := fset.Snapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+
+	fn := func(f protocol.FileInfo) bool {
+		if !query.matches(f) {
+			return true
+		}
+		onPage := res.Total >= query.Offset && (query.Limit <= 0 || len(res.Results) < query.Limit)
+		var availability []protocol.DeviceID
+		if onPage || query.HaveDevice != protocol.EmptyDeviceID {
+			availability = snap.Availability(f.Name)
+			if query.HaveDevice != protocol.EmptyDeviceID && !slices.Contains(availability, query.HaveDevice) {
+				return true
+			}
+		}
+		res.Total++
+		if !onPage {
+			return true
+		}
+
+		for i, dev := range availability {
+			if dev == protocol.LocalDeviceID {
+				availability[i] = m.id
+			}
+		}
+		res.Results = append(res.Results, &SearchResult{
+			Folder:       folder,
+			Name:         f.Name,
+			Type:         f.Type.String(),
+			Size:         f.FileSize(),
+			ModTime:      f.ModTime(),
+			Availability: availability,
+		})
+		return true
+	}
+
+	switch {
+	case query.NeedDevice != protocol.EmptyDeviceID:
+		snap.WithNeedTruncated(query.NeedDevice, fn)
+	case query.Local:
+		snap.WithHaveTruncated(protocol.LocalDeviceID, fn)
+	default:
+		snap.WithGlobalTruncated(fn)
+	}
+	return nil
+}
+
+func (q SearchQuery) matches(f protocol.FileInfo) bool {
+	if f.IsInvalid() || f.IsDeleted() {
+		return false
+	}
+	if len(q.Types) > 0 && !slices.Contains(q.Types, f.Type) {
+		return false
+	}
+	if size := f.FileSize(); size < q.MinSize || q.MaxSize > 0 && size > q.MaxSize {
+		return false
+	}
+	if modTime := f.ModTime(); modTime.Before(q.ModifiedAfter) || !q.ModifiedBefore.IsZero() && modTime.After(q.ModifiedBefore) {
+		return false
+	}
+	if q.Glob != "" {
+		if ok, _ := path.Match(q.Glob, strings.ToLower(filepath.Base(f.Name))); !ok {
+			return false
+		}
+	}
+	if q.Regexp != nil && !q.Regexp.MatchString(filepath.ToSlash(f.Name)) {
+		return false
+	}
+	return true
+}
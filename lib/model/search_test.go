@@ -0,0 +1,101 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestSearch(t *testing.T) {
+	w, fcfg, wCancel := newDefaultCfgWrapper()
+	defer wCancel()
+	m := setupModel(t, w)
+	defer cleanupModelAndRemoveDir(m, fcfg.Filesystem(nil).URI())
+
+	now := time.Now()
+	file := func(name string, typ protocol.FileInfoType, size int64, modified time.Time) protocol.FileInfo {
+		return protocol.FileInfo{
+			Name:      name,
+			Type:      typ,
+			Size:      size,
+			ModifiedS: modified.Unix(),
+			Version:   protocol.Vector{}.Update(device1.Short()),
+		}
+	}
+	m.mut.RLock()
+	fset := m.folderFiles[fcfg.ID]
+	m.mut.RUnlock()
+	fset.Update(device1, []protocol.FileInfo{
+		file("a.PSD", protocol.FileInfoTypeFile, 2<<30, now.Add(-24*time.Hour)),
+		file("b.psd", protocol.FileInfoTypeFile, 10, now.Add(-30*24*time.Hour)),
+		file(filepath.Join("c", "d.txt"), protocol.FileInfoTypeFile, 100, now),
+		file("dir", protocol.FileInfoTypeDirectory, 128, now),
+	})
+
+	search := func(query SearchQuery) (int, []string) {
+		t.Helper()
+		res, err := m.Search(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, r := range res.Results {
+			names = append(names, filepath.ToSlash(r.Name))
+		}
+		return res.Total, names
+	}
+
+	cases := []struct {
+		name  string
+		query SearchQuery
+		total int
+		names []string
+	}{
+		{"all", SearchQuery{}, 4, []string{"a.PSD", "b.psd", "c/d.txt", "dir"}},
+		{"large recent photoshop files", SearchQuery{Glob: "*.psd", MinSize: 1 << 30, ModifiedAfter: now.Add(-7 * 24 * time.Hour)}, 1, []string{"a.PSD"}},
+		{"small", SearchQuery{MaxSize: 128}, 3, []string{"b.psd", "c/d.txt", "dir"}},
+		{"old", SearchQuery{ModifiedBefore: now.Add(-time.Hour)}, 2, []string{"a.PSD", "b.psd"}},
+		{"regexp", SearchQuery{Regexp: regexp.MustCompile(`^c/`)}, 1, []string{"c/d.txt"}},
+		{"directories", SearchQuery{Types: []protocol.FileInfoType{protocol.FileInfoTypeDirectory}}, 1, []string{"dir"}},
+		{"page", SearchQuery{Offset: 1, Limit: 2}, 4, []string{"b.psd", "c/d.txt"}},
+		{"past the end", SearchQuery{Offset: 10}, 4, nil},
+		{"had by remote", SearchQuery{HaveDevice: device1, Glob: "*.txt"}, 1, []string{"c/d.txt"}},
+		{"had by us", SearchQuery{HaveDevice: myID}, 0, nil},
+		{"needed by us", SearchQuery{NeedDevice: myID, Limit: 1}, 4, []string{"a.PSD"}},
+		{"needed by remote", SearchQuery{NeedDevice: device1}, 0, nil},
+		{"local", SearchQuery{Local: true}, 0, nil},
+		{"folder", SearchQuery{Folders: []string{fcfg.ID}, Glob: "dir"}, 1, []string{"dir"}},
+	}
+	for _, tc := range cases {
+		total, names := search(tc.query)
+		if total != tc.total || !slices.Equal(names, tc.names) {
+			t.Errorf("%s: got %d %v, expected %d %v", tc.name, total, names, tc.total, tc.names)
+		}
+	}
+
+	res, err := m.Search(SearchQuery{Glob: "dir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if av := res.Results[0].Availability; !slices.Equal(av, []protocol.DeviceID{device1}) {
+		t.Error("unexpected availability", av)
+	}
+
+	if _, err := m.Search(SearchQuery{Folders: []string{"nonexistent"}}); !errors.Is(err, ErrFolderMissing) {
+		t.Error("expected missing folder error, got", err)
+	}
+	if _, err := m.Search(SearchQuery{Glob: "["}); err == nil {
+		t.Error("expected bad pattern error")
+	}
+}
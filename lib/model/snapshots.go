@@ -0,0 +1,350 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/locations"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+	"github.com/syncthing/syncthing/lib/sync"
+	"github.com/syncthing/syncthing/lib/versioner"
+)
+
+const (
+	// Snapshot IDs are their creation time with millisecond precision.
+	// Parsing them with snapshotTimeFormat also accepts the IDs of older
+	// snapshots, which have no fractional seconds.
+	snapshotIDFormat   = "20060102-150405.000"
+	snapshotTimeFormat = "20060102-150405"
+	snapshotExtension  = ".json.gz"
+)
+
+var (
+	errSnapshotMissing       = errors.New("no such snapshot")
+	errSnapshotExists        = errors.New("a snapshot with the same ID already exists")
+	errNoArchivedVersion     = errors.New("no archived version matching the snapshot")
+	errSnapshotNotRestorable = errors.New("snapshot can't be restored, there are no archived versions of some of its files")
+	errSnapshotNotVerifiable = errors.New("snapshot doesn't record the contents of the file")
+)
+
+// Held while saving a snapshot, so that two snapshots can't take the same
+// ID.
+var snapshotSaveMut = sync.NewMutex()
+
+// A FolderSnapshot records the state of the local files in a folder at a
+// point in time. It doesn't contain any file data; restoring a snapshot
+// relies on the versioner having kept the old contents of files that were
+// since changed or deleted.
+type FolderSnapshot struct {
+	SnapshotInfo
+	Folder string         `json:"folder"`
+	Files  []SnapshotFile `json:"files"`
+}
+
+type SnapshotInfo struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type SnapshotFile struct {
+	Name          string                `json:"name"`
+	Type          protocol.FileInfoType `json:"type"`
+	Size          int64                 `json:"size"`
+	ModTime       time.Time             `json:"modTime"`
+	Permissions   uint32                `json:"permissions"`
+	Version       protocol.Vector       `json:"version"`
+	BlocksHash    []byte                `json:"blocksHash,omitempty"`
+	BlockSize     int                   `json:"blockSize,omitempty"`
+	ChunkedBlocks bool                  `json:"chunkedBlocks,omitempty"`
+	SymlinkTarget []byte                `json:"symlinkTarget,omitempty"`
+}
+
+// SnapshotDiffEntry describes how a file differs between a snapshot and
+// the current state of the folder. Change is one of "added" (the file
+// exists now but not in the snapshot), "deleted" (the other way around)
+// or "modified".
+type SnapshotDiffEntry struct {
+	Name     string        `json:"name"`
+	Change   string        `json:"change"`
+	Snapshot *SnapshotFile `json:"snapshot,omitempty"`
+	Current  *SnapshotFile `json:"current,omitempty"`
+}
+
+func newSnapshotFile(f protocol.FileInfo) SnapshotFile {
+	return SnapshotFile{
+		Name:          f.Name,
+		Type:          f.Type,
+		Size:          f.Size,
+		ModTime:       f.ModTime(),
+		Permissions:   f.Permissions,
+		Version:       f.Version,
+		BlocksHash:    f.BlocksHash,
+		BlockSize:     f.BlockSize(),
+		ChunkedBlocks: f.HasVariableBlocks(),
+		SymlinkTarget: f.SymlinkTarget,
+	}
+}
+
+// isEquivalent returns true if the given file has the same contents as the
+// one recorded in the snapshot.
+func (sf SnapshotFile) isEquivalent(f protocol.FileInfo) bool {
+	if f.IsDeleted() || f.IsInvalid() || f.Type != sf.Type {
+		return false
+	}
+	switch f.Type {
+	case protocol.FileInfoTypeFile:
+		if len(sf.BlocksHash) == 0 {
+			return f.Version.Equal(sf.Version)
+		}
+		return f.Size == sf.Size && bytes.Equal(f.BlocksHash, sf.BlocksHash)
+	case protocol.FileInfoTypeSymlink:
+		return bytes.Equal(f.SymlinkTarget, sf.SymlinkTarget)
+	}
+	return true
+}
+
+// takeFolderSnapshot records the current local files of the given file set.
+func takeFolderSnapshot(folder string, fset *db.FileSet, now time.Time) (FolderSnapshot, error) {
+	snap, err := fset.Snapshot()
+	if err != nil {
+		return FolderSnapshot{}, err
+	}
+	defer snap.Release()
+
+	created := now.UTC().Truncate(time.Millisecond)
+	fsnap := FolderSnapshot{
+		SnapshotInfo: SnapshotInfo{
+			ID:      created.Format(snapshotIDFormat),
+			Created: created,
+		},
+		Folder: folder,
+	}
+	snap.WithHave(protocol.LocalDeviceID, func(f protocol.FileInfo) bool {
+		if !f.IsDeleted() && !f.IsInvalid() {
+			fsnap.Files = append(fsnap.Files, newSnapshotFile(f))
+		}
+		return true
+	})
+	return fsnap, nil
+}
+
+// diffFolderSnapshot compares the snapshot to the local files in the given
+// database snapshot.
+func diffFolderSnapshot(fsnap FolderSnapshot, snap *db.Snapshot) []SnapshotDiffEntry {
+	var diff []SnapshotDiffEntry
+	inSnapshot := make(map[string]struct{}, len(fsnap.Files))
+	for i := range fsnap.Files {
+		sf := &fsnap.Files[i]
+		inSnapshot[sf.Name] = struct{}{}
+		cf, ok := snap.Get(protocol.LocalDeviceID, sf.Name)
+		switch {
+		case !ok || cf.IsDeleted() || cf.IsInvalid():
+			diff = append(diff, SnapshotDiffEntry{Name: sf.Name, Change: "deleted", Snapshot: sf})
+		case !sf.isEquivalent(cf):
+			cur := newSnapshotFile(cf)
+			diff = append(diff, SnapshotDiffEntry{Name: sf.Name, Change: "modified", Snapshot: sf, Current: &cur})
+		}
+	}
+	snap.WithHave(protocol.LocalDeviceID, func(f protocol.FileInfo) bool {
+		if _, ok := inSnapshot[f.Name]; ok || f.IsDeleted() || f.IsInvalid() {
+			return true
+		}
+		cur := newSnapshotFile(f)
+		diff = append(diff, SnapshotDiffEntry{Name: f.Name, Change: "added", Current: &cur})
+		return true
+	})
+	sort.Slice(diff, func(a, b int) bool {
+		return diff[a].Name < diff[b].Name
+	})
+	return diff
+}
+
+// findSnapshotVersion returns the newest archived version with the contents
+// of the file recorded in the snapshot. Versions of the right size are
+// read and hashed the way the scanner hashed the file, to compare their
+// blocks hash with the recorded one.
+func findSnapshotVersion(ctx context.Context, sf SnapshotFile, versions []versioner.FileVersion, open func(versionTime time.Time) (io.ReadCloser, error)) (versioner.FileVersion, error) {
+	candidates := make([]versioner.FileVersion, 0, len(versions))
+	for _, v := range versions {
+		if v.Size == sf.Size {
+			candidates = append(candidates, v)
+		}
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].VersionTime.After(candidates[b].VersionTime)
+	})
+	if len(candidates) > 0 && sf.Size == 0 {
+		return candidates[0], nil
+	}
+	if len(sf.BlocksHash) == 0 {
+		return versioner.FileVersion{}, errSnapshotNotVerifiable
+	}
+	for _, v := range candidates {
+		fd, err := open(v.VersionTime)
+		if err != nil {
+			l.Debugf("Opening archived version %v of %s: %v", v.VersionTime, sf.Name, err)
+			continue
+		}
+		hash, err := snapshotBlocksHash(ctx, fd, sf)
+		fd.Close()
+		if err != nil {
+			if ctx.Err() != nil {
+				return versioner.FileVersion{}, ctx.Err()
+			}
+			l.Debugf("Hashing archived version %v of %s: %v", v.VersionTime, sf.Name, err)
+			continue
+		}
+		if bytes.Equal(hash, sf.BlocksHash) {
+			return v, nil
+		}
+	}
+	return versioner.FileVersion{}, errNoArchivedVersion
+}
+
+func snapshotBlocksHash(ctx context.Context, r io.Reader, sf SnapshotFile) ([]byte, error) {
+	blockSize := sf.BlockSize
+	if blockSize == 0 {
+		blockSize = protocol.BlockSize(sf.Size)
+	}
+	var blocks []protocol.BlockInfo
+	var err error
+	if sf.ChunkedBlocks {
+		blocks, err = scanner.ChunkedBlocks(ctx, r, blockSize, sf.Size, nil)
+	} else {
+		blocks, err = scanner.Blocks(ctx, r, blockSize, sf.Size, nil, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return protocol.BlocksHash(blocks), nil
+}
+
+// snapshotStore keeps the snapshots of a folder as compressed JSON files
+// in a directory named after the folder ID.
+type snapshotStore struct {
+	dir string
+}
+
+func newSnapshotStore(folder string) snapshotStore {
+	return snapshotStore{
+		dir: filepath.Join(locations.Get(locations.Snapshots), url.PathEscape(folder)),
+	}
+}
+
+func (s snapshotStore) save(fsnap FolderSnapshot) error {
+	snapshotSaveMut.Lock()
+	defer snapshotSaveMut.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, fsnap.ID+snapshotExtension)
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("%w: %s", errSnapshotExists, fsnap.ID)
+	}
+	fd, err := osutil.CreateAtomic(path)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(fd)
+	if err := json.NewEncoder(gw).Encode(fsnap); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
+
+func (s snapshotStore) load(id string) (FolderSnapshot, error) {
+	if _, err := time.Parse(snapshotTimeFormat, id); err != nil {
+		return FolderSnapshot{}, fmt.Errorf("%w: %s", errSnapshotMissing, id)
+	}
+	fd, err := os.Open(filepath.Join(s.dir, id+snapshotExtension))
+	if errors.Is(err, os.ErrNotExist) {
+		return FolderSnapshot{}, fmt.Errorf("%w: %s", errSnapshotMissing, id)
+	} else if err != nil {
+		return FolderSnapshot{}, err
+	}
+	defer fd.Close()
+	gr, err := gzip.NewReader(fd)
+	if err != nil {
+		return FolderSnapshot{}, err
+	}
+	var fsnap FolderSnapshot
+	if err := json.NewDecoder(gr).Decode(&fsnap); err != nil {
+		return FolderSnapshot{}, err
+	}
+	return fsnap, nil
+}
+
+// list returns the available snapshots, oldest first.
+func (s snapshotStore) list() ([]SnapshotInfo, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var infos []SnapshotInfo
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), snapshotExtension)
+		if !ok {
+			continue
+		}
+		created, err := time.Parse(snapshotTimeFormat, id)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, SnapshotInfo{ID: id, Created: created})
+	}
+	sort.Slice(infos, func(a, b int) bool {
+		return infos[a].Created.Before(infos[b].Created)
+	})
+	return infos, nil
+}
+
+// prune removes the oldest snapshots until at most keep remain. Zero means
+// to keep all snapshots.
+func (s snapshotStore) prune(keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	infos, err := s.list()
+	if err != nil {
+		return err
+	}
+	for len(infos) > keep {
+		if err := os.Remove(filepath.Join(s.dir, infos[0].ID+snapshotExtension)); err != nil {
+			return err
+		}
+		infos = infos[1:]
+	}
+	return nil
+}
+
+func (s snapshotStore) removeAll() error {
+	return os.RemoveAll(s.dir)
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+	"github.com/syncthing/syncthing/lib/versioner"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	ldb, err := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ldb.Close()
+	fset := newFileSet(t, "default", ldb)
+
+	var version protocol.Vector
+	file := func(name string, hash byte, deleted bool) protocol.FileInfo {
+		return protocol.FileInfo{
+			Name:       name,
+			Type:       protocol.FileInfoTypeFile,
+			Size:       1,
+			Version:    version,
+			BlocksHash: []byte{hash},
+			Deleted:    deleted,
+		}
+	}
+
+	version = version.Update(myID.Short())
+	fset.Update(protocol.LocalDeviceID, []protocol.FileInfo{
+		file("unchanged", 1, false),
+		file("modified", 2, false),
+		file("deleted", 3, false),
+		file("alreadyDeleted", 4, true),
+	})
+	fsnap, err := takeFolderSnapshot("default", fset, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fsnap.Files) != 3 {
+		t.Fatal("expected deleted files not to be part of the snapshot, got", len(fsnap.Files))
+	}
+
+	version = version.Update(myID.Short())
+	fset.Update(protocol.LocalDeviceID, []protocol.FileInfo{
+		file("modified", 5, false),
+		file("deleted", 3, true),
+		file("added", 6, false),
+	})
+	snap, err := fset.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.Release()
+
+	diff := diffFolderSnapshot(fsnap, snap)
+	expected := []struct{ name, change string }{
+		{"added", "added"},
+		{"deleted", "deleted"},
+		{"modified", "modified"},
+	}
+	if len(diff) != len(expected) {
+		t.Fatalf("expected %d differences, got %v", len(expected), diff)
+	}
+	for i, e := range expected {
+		if diff[i].Name != e.name || diff[i].Change != e.change {
+			t.Errorf("expected %s to be %s, got %s %s", e.name, e.change, diff[i].Name, diff[i].Change)
+		}
+	}
+}
+
+func TestSnapshotStore(t *testing.T) {
+	ldb, err := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ldb.Close()
+	store := snapshotStore{dir: t.TempDir()}
+
+	if infos, err := store.list(); err != nil || len(infos) != 0 {
+		t.Fatal("expected no snapshots in empty store, got", infos, err)
+	}
+
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		created := base.Add(time.Duration(i) * time.Hour)
+		fsnap := FolderSnapshot{
+			SnapshotInfo: SnapshotInfo{ID: created.Format(snapshotTimeFormat), Created: created},
+			Folder:       "default",
+			Files:        []SnapshotFile{{Name: "file", Size: int64(i)}},
+		}
+		if err := store.save(fsnap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.prune(2); err != nil {
+		t.Fatal(err)
+	}
+	infos, err := store.list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 || infos[0].ID != "20250102-040405" || infos[1].ID != "20250102-050405" {
+		t.Fatal("unexpected snapshots after pruning:", infos)
+	}
+
+	fsnap, err := store.load(infos[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fsnap.Created.Equal(infos[1].Created) || len(fsnap.Files) != 1 || fsnap.Files[0].Size != 2 {
+		t.Error("unexpected loaded snapshot:", fsnap)
+	}
+
+	if _, err := store.load("20250102-030405"); !errors.Is(err, errSnapshotMissing) {
+		t.Error("expected missing snapshot error, got", err)
+	}
+	if _, err := store.load("../../etc/passwd"); !errors.Is(err, errSnapshotMissing) {
+		t.Error("expected missing snapshot error for invalid ID, got", err)
+	}
+
+	// Snapshots taken within the same second get distinct IDs, and an ID
+	// that is taken is refused.
+	now := time.Date(2025, 1, 2, 6, 4, 5, 0, time.UTC)
+	for _, offset := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		fsnap, err := takeFolderSnapshot("default", newFileSet(t, "default", ldb), now.Add(offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.save(fsnap); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.save(fsnap); !errors.Is(err, errSnapshotExists) {
+			t.Fatal("expected duplicate snapshot to be refused, got", err)
+		}
+	}
+	infos, err = store.list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 4 || infos[2].ID != "20250102-060405.100" || infos[3].ID != "20250102-060405.200" {
+		t.Fatal("unexpected snapshots:", infos)
+	}
+}
+
+func TestFindSnapshotVersion(t *testing.T) {
+	const size = 2 * protocol.MinBlockSize
+	content := func(b byte) []byte {
+		return bytes.Repeat([]byte{b}, size)
+	}
+	blocks, err := scanner.Blocks(context.Background(), bytes.NewReader(content(1)), protocol.MinBlockSize, size, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := protocol.FileInfo{Name: "file", Type: protocol.FileInfoTypeFile, Size: size, Blocks: blocks, RawBlockSize: protocol.MinBlockSize}
+	file.BlocksHash = protocol.BlocksHash(blocks)
+	sf := newSnapshotFile(file)
+
+	// The newest version of the right size has other contents, with the
+	// same modification time.
+	mtime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	versions := []versioner.FileVersion{
+		{VersionTime: mtime.Add(time.Hour), ModTime: mtime, Size: size + 1},
+		{VersionTime: mtime.Add(2 * time.Hour), ModTime: mtime, Size: size},
+		{VersionTime: mtime.Add(3 * time.Hour), ModTime: mtime, Size: size},
+		{VersionTime: mtime.Add(4 * time.Hour), ModTime: mtime, Size: size},
+	}
+	contents := map[time.Time][]byte{
+		versions[1].VersionTime: content(1),
+		versions[2].VersionTime: content(1),
+		versions[3].VersionTime: content(2),
+	}
+	open := func(versionTime time.Time) (io.ReadCloser, error) {
+		data, ok := contents[versionTime]
+		if !ok {
+			return nil, errors.New("unexpected version opened")
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	v, err := findSnapshotVersion(context.Background(), sf, versions, open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.VersionTime.Equal(versions[2].VersionTime) {
+		t.Error("expected the newest version with the same contents, got", v)
+	}
+
+	// Once the versioner has removed the versions with the right contents,
+	// nothing matches.
+	delete(contents, versions[1].VersionTime)
+	delete(contents, versions[2].VersionTime)
+	if _, err := findSnapshotVersion(context.Background(), sf, versions[1:], open); !errors.Is(err, errNoArchivedVersion) {
+		t.Error("expected no matching version, got", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+// A device keeps its upload slot while it keeps sending requests, and
+// releases it when it has been quiet for this long.
+const uploadSlotIdleTime = 10 * time.Second
+
+// uploadFanOut limits the number of devices we serve block requests to at
+// the same time, like unchoking in BitTorrent: a few devices get the blocks
+// quickly and pass them on to the others, rather than every device getting
+// a trickle. It is safe for use from multiple goroutines.
+type uploadFanOut struct {
+	maxDevices int                             // zero or less means no limit
+	slots      map[protocol.DeviceID]time.Time // device -> latest request
+	mut        sync.Mutex
+}
+
+func newUploadFanOut(maxDevices int) *uploadFanOut {
+	return &uploadFanOut{
+		maxDevices: maxDevices,
+		slots:      make(map[protocol.DeviceID]time.Time),
+		mut:        sync.NewMutex(),
+	}
+}
+
+func (u *uploadFanOut) setMaxDevices(maxDevices int) {
+	u.mut.Lock()
+	u.maxDevices = maxDevices
+	u.mut.Unlock()
+}
+
+// allow returns whether a request from the device should be served.
+func (u *uploadFanOut) allow(device protocol.DeviceID, now time.Time) bool {
+	u.mut.Lock()
+	defer u.mut.Unlock()
+
+	if u.maxDevices <= 0 {
+		return true
+	}
+	for id, last := range u.slots {
+		if now.Sub(last) > uploadSlotIdleTime {
+			delete(u.slots, id)
+		}
+	}
+	if _, ok := u.slots[device]; !ok && len(u.slots) >= u.maxDevices {
+		return false
+	}
+	u.slots[device] = now
+	return true
+}
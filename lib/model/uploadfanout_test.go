@@ -0,0 +1,79 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestUploadFanOut(t *testing.T) {
+	now := time.Now()
+
+	u := newUploadFanOut(0)
+	if !u.allow(device1, now) || !u.allow(device2, now) {
+		t.Fatal("expected no limit")
+	}
+
+	u.setMaxDevices(1)
+	if !u.allow(device1, now) {
+		t.Error("expected the first device to get the slot")
+	}
+	if u.allow(device2, now) {
+		t.Error("expected the second device to be refused while the first is served")
+	}
+	if !u.allow(device1, now.Add(uploadSlotIdleTime)) {
+		t.Error("expected the first device to keep its slot while requesting")
+	}
+	if u.allow(device2, now.Add(uploadSlotIdleTime)) {
+		t.Error("expected the second device to still be refused")
+	}
+	later := now.Add(2*uploadSlotIdleTime + time.Second)
+	if !u.allow(device2, later) {
+		t.Error("expected the second device to get the slot after the first went quiet")
+	}
+	if u.allow(device1, later) {
+		t.Error("expected the first device to have lost its slot")
+	}
+}
+
+func TestRequestBeyondUploadFanOut(t *testing.T) {
+	w, fcfg, cancel := newDefaultCfgWrapper()
+	defer cancel()
+	setDevice(t, w, newDeviceConfiguration(w.DefaultDevice(), device2, "device2"))
+	fcfg.Devices = append(fcfg.Devices, config.FolderDeviceConfiguration{DeviceID: device2})
+	setFolder(t, w, fcfg)
+	m := setupModel(t, w)
+	defer cleanupModel(m)
+	fc1 := addFakeConn(m, device1, fcfg.ID)
+	fc2 := addFakeConn(m, device2, fcfg.ID)
+
+	writeFile(t, fcfg.Filesystem(nil), "foo", []byte("foobar"))
+	m.ScanFolder(fcfg.ID)
+	m.uploadFanOut.setMaxDevices(1)
+
+	req := &protocol.Request{Folder: fcfg.ID, Name: "foo", Size: 6}
+	if _, err := m.Request(fc1, req); err != nil {
+		t.Fatal("first device:", err)
+	}
+	if _, err := m.Request(fc2, req); err != nil {
+		t.Fatal("second device should be served while nobody else has the file:", err)
+	}
+
+	// Once the first device has the file the second one can get it there.
+	f, ok := m.testCurrentFolderFile(fcfg.ID, "foo")
+	if !ok {
+		t.Fatal("file missing")
+	}
+	must(t, m.IndexUpdate(fc1, &protocol.IndexUpdate{Folder: fcfg.ID, Files: []protocol.FileInfo{prepareFileInfoForIndex(f)}}))
+	if _, err := m.Request(fc2, req); err == nil {
+		t.Error("second device should be refused while the first has the file")
+	}
+}
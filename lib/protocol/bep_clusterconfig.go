@@ -51,14 +51,15 @@ func clusterConfigFromWire(w *bep.ClusterConfig) *ClusterConfig {
 }
 
 type Folder struct {
-	ID                 string
-	Label              string
-	ReadOnly           bool
-	IgnorePermissions  bool
-	IgnoreDelete       bool
-	DisableTempIndexes bool
-	Paused             bool
-	Devices            []Device
+	ID                     string
+	Label                  string
+	ReadOnly               bool
+	IgnorePermissions      bool
+	IgnoreDelete           bool
+	DisableTempIndexes     bool
+	Paused                 bool
+	ContentDefinedChunking bool
+	Devices                []Device
 }
 
 func (f *Folder) toWire() *bep.Folder {
@@ -67,14 +68,15 @@ func (f *Folder) toWire() *bep.Folder {
 		devices[i] = d.toWire()
 	}
 	return &bep.Folder{
-		Id:                 f.ID,
-		Label:              f.Label,
-		ReadOnly:           f.ReadOnly,
-		IgnorePermissions:  f.IgnorePermissions,
-		IgnoreDelete:       f.IgnoreDelete,
-		DisableTempIndexes: f.DisableTempIndexes,
-		Paused:             f.Paused,
-		Devices:            devices,
+		Id:                     f.ID,
+		Label:                  f.Label,
+		ReadOnly:               f.ReadOnly,
+		IgnorePermissions:      f.IgnorePermissions,
+		IgnoreDelete:           f.IgnoreDelete,
+		DisableTempIndexes:     f.DisableTempIndexes,
+		Paused:                 f.Paused,
+		ContentDefinedChunking: f.ContentDefinedChunking,
+		Devices:                devices,
 	}
 }
 
@@ -84,14 +86,15 @@ func folderFromWire(w *bep.Folder) Folder {
 		devices[i] = deviceFromWire(d)
 	}
 	return Folder{
-		ID:                 w.Id,
-		Label:              w.Label,
-		ReadOnly:           w.ReadOnly,
-		IgnorePermissions:  w.IgnorePermissions,
-		IgnoreDelete:       w.IgnoreDelete,
-		DisableTempIndexes: w.DisableTempIndexes,
-		Paused:             w.Paused,
-		Devices:            devices,
+		ID:                     w.Id,
+		Label:                  w.Label,
+		ReadOnly:               w.ReadOnly,
+		IgnorePermissions:      w.IgnorePermissions,
+		IgnoreDelete:           w.IgnoreDelete,
+		DisableTempIndexes:     w.DisableTempIndexes,
+		Paused:                 w.Paused,
+		ContentDefinedChunking: w.ContentDefinedChunking,
+		Devices:                devices,
 	}
 }
 
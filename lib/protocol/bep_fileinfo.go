@@ -11,6 +11,7 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/syncthing/syncthing/internal/gen/bep"
@@ -324,6 +325,35 @@ func (f FileInfo) BlockSize() int {
 	return int(f.RawBlockSize)
 }
 
+// BlockIndex returns the index of the block starting at the given offset,
+// or -1 if no block starts there. Files hashed with content defined
+// chunking have blocks of varying size, so the index can't always be
+// calculated from the block size.
+func (f FileInfo) BlockIndex(offset int64) int {
+	if i := int(offset / int64(f.BlockSize())); i < len(f.Blocks) && f.Blocks[i].Offset == offset {
+		return i
+	}
+	i := sort.Search(len(f.Blocks), func(i int) bool {
+		return f.Blocks[i].Offset >= offset
+	})
+	if i < len(f.Blocks) && f.Blocks[i].Offset == offset {
+		return i
+	}
+	return -1
+}
+
+// HasVariableBlocks returns true if the blocks of the file aren't all of
+// the file's block size, i.e. the file was hashed with content defined
+// chunking.
+func (f FileInfo) HasVariableBlocks() bool {
+	for i := 0; i < len(f.Blocks)-1; i++ {
+		if f.Blocks[i].Size != f.BlockSize() {
+			return true
+		}
+	}
+	return false
+}
+
 // BlockSize returns the block size to use for the given file size
 func BlockSize(fileSize int64) int {
 	var blockSize int
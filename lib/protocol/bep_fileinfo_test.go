@@ -291,3 +291,50 @@ func TestBlocksEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockIndex(t *testing.T) {
+	fixed := FileInfo{
+		RawBlockSize: MinBlockSize,
+		Blocks: []BlockInfo{
+			{Offset: 0, Size: MinBlockSize},
+			{Offset: MinBlockSize, Size: MinBlockSize},
+			{Offset: 2 * MinBlockSize, Size: 10},
+		},
+	}
+	variable := FileInfo{
+		RawBlockSize: MinBlockSize,
+		Blocks: []BlockInfo{
+			{Offset: 0, Size: 1000},
+			{Offset: 1000, Size: 3 * MinBlockSize},
+			{Offset: 1000 + 3*MinBlockSize, Size: 10},
+		},
+	}
+
+	cases := []struct {
+		file   FileInfo
+		offset int64
+		index  int
+	}{
+		{fixed, 0, 0},
+		{fixed, MinBlockSize, 1},
+		{fixed, 2 * MinBlockSize, 2},
+		{fixed, 3 * MinBlockSize, -1},
+		{fixed, 10, -1},
+		{variable, 0, 0},
+		{variable, 1000, 1},
+		{variable, 1000 + 3*MinBlockSize, 2},
+		{variable, MinBlockSize, -1},
+	}
+	for _, tc := range cases {
+		if i := tc.file.BlockIndex(tc.offset); i != tc.index {
+			t.Errorf("BlockIndex(%d) = %d, expected %d", tc.offset, i, tc.index)
+		}
+	}
+
+	if fixed.HasVariableBlocks() {
+		t.Error("fixed size blocks reported as variable")
+	}
+	if !variable.HasVariableBlocks() {
+		t.Error("variable size blocks not reported as such")
+	}
+}
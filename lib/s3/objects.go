@@ -0,0 +1,168 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// ObjectInfo is the result of a Head call.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	// Metadata holds the user defined metadata of the object, with the
+	// keys in lower case.
+	Metadata map[string]string
+}
+
+// Head returns information about the given object. A missing object
+// results in an error wrapping fs.ErrNotExist.
+func (s *Session) Head(key string) (ObjectInfo, error) {
+	svc := s3.New(s.s3sess)
+	resp, err := svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ObjectInfo{}, translateError(key, err)
+	}
+
+	info := ObjectInfo{
+		Key:      key,
+		Size:     aws.Int64Value(resp.ContentLength),
+		Metadata: make(map[string]string, len(resp.Metadata)),
+	}
+	if resp.LastModified != nil {
+		info.LastModified = *resp.LastModified
+	}
+	for k, v := range resp.Metadata {
+		info.Metadata[strings.ToLower(k)] = aws.StringValue(v)
+	}
+	return info, nil
+}
+
+// GetRange returns a reader for length bytes of the given object, starting
+// at offset. The caller must close the returned reader.
+func (s *Session) GetRange(key string, offset, length int64) (io.ReadCloser, error) {
+	svc := s3.New(s.s3sess)
+	resp, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, translateError(key, err)
+	}
+	return resp.Body, nil
+}
+
+// Put stores the contents of r as the given object, replacing any existing
+// object with the same key.
+func (s *Session) Put(r io.Reader, key string, metadata map[string]string) error {
+	uploader := s3manager.NewUploader(s.s3sess)
+	_, err := uploader.Upload(&s3manager.UploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		Body:     r,
+		Metadata: aws.StringMap(metadata),
+	})
+	return err
+}
+
+// Copy creates a server side copy of the object at src under the key dst.
+// When metadata is nil the metadata of the source object is retained,
+// otherwise it is replaced by the given map. Copying an object onto itself
+// with new metadata is the way to update metadata in place.
+func (s *Session) Copy(src, dst string, metadata map[string]string) error {
+	svc := s3.New(s.s3sess)
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(dst),
+		CopySource: aws.String((&url.URL{Path: s.bucket + "/" + src}).EscapedPath()),
+	}
+	if metadata != nil {
+		input.Metadata = aws.StringMap(metadata)
+		input.MetadataDirective = aws.String(s3.MetadataDirectiveReplace)
+	}
+	_, err := svc.CopyObject(input)
+	return translateError(src, err)
+}
+
+// Delete removes the given object. Deleting an object that does not exist
+// is not an error.
+func (s *Session) Delete(key string) error {
+	svc := s3.New(s.s3sess)
+	_, err := svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return translateError(key, err)
+}
+
+// ListPrefix calls fn for each object key starting with prefix. When
+// delimiter is non-empty, keys containing the delimiter after the prefix
+// are rolled up and reported once with isPrefix set, as common prefixes.
+// Iteration stops when fn returns false.
+func (s *Session) ListPrefix(prefix, delimiter string, fn func(key string, isPrefix bool) bool) error {
+	svc := s3.New(s.s3sess)
+
+	opts := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+	if delimiter != "" {
+		opts.Delimiter = aws.String(delimiter)
+	}
+	for {
+		resp, err := svc.ListObjectsV2(opts)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range resp.Contents {
+			if !fn(aws.StringValue(item.Key), false) {
+				return nil
+			}
+		}
+		for _, item := range resp.CommonPrefixes {
+			if !fn(aws.StringValue(item.Prefix), true) {
+				return nil
+			}
+		}
+
+		if resp.NextContinuationToken == nil || *resp.NextContinuationToken == "" {
+			break
+		}
+		opts.ContinuationToken = resp.NextContinuationToken
+	}
+
+	return nil
+}
+
+func translateError(key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var rerr awserr.RequestFailure
+	if errors.As(err, &rerr) && rerr.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", key, fs.ErrNotExist)
+	}
+	return err
+}
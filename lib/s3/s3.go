@@ -25,10 +25,26 @@ type Session struct {
 type Object = s3.Object
 
 func NewSession(endpoint, region, bucket, accessKeyID, secretKey string) (*Session, error) {
-	sess, err := session.NewSession(&aws.Config{
+	return newSession(&aws.Config{
 		Region:      aws.String(region),
 		Endpoint:    aws.String(endpoint),
 		Credentials: credentials.NewStaticCredentials(accessKeyID, secretKey, ""),
+	}, bucket)
+}
+
+// NewPathStyleSession is like NewSession, but addresses the bucket in the
+// request path instead of the host name, as most self hosted S3 compatible
+// services expect. The credentials come from the default AWS credential
+// chain (environment variables, shared credentials file, etc.), using the
+// given profile of the shared files when not empty.
+func NewPathStyleSession(endpoint, region, bucket, profile string) (*Session, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config: aws.Config{
+			Region:           aws.String(region),
+			Endpoint:         aws.String(endpoint),
+			S3ForcePathStyle: aws.Bool(true),
+		},
+		Profile: profile,
 	})
 	if err != nil {
 		return nil, err
@@ -39,6 +55,17 @@ func NewSession(endpoint, region, bucket, accessKeyID, secretKey string) (*Sessi
 	}, nil
 }
 
+func newSession(cfg *aws.Config, bucket string) (*Session, error) {
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		bucket: bucket,
+		s3sess: sess,
+	}, nil
+}
+
 func (s *Session) Upload(r io.Reader, key string) error {
 	uploader := s3manager.NewUploader(s.s3sess)
 	_, err := uploader.Upload(&s3manager.UploadInput{
@@ -9,6 +9,7 @@ package scanner
 import (
 	"context"
 	"errors"
+	"io"
 
 	"github.com/syncthing/syncthing/lib/fs"
 	"github.com/syncthing/syncthing/lib/protocol"
@@ -17,6 +18,20 @@ import (
 
 // HashFile hashes the files and returns a list of blocks representing the file.
 func HashFile(ctx context.Context, folderID string, fs fs.Filesystem, path string, blockSize int, counter Counter, useWeakHashes bool) ([]protocol.BlockInfo, error) {
+	return hashFile(ctx, folderID, fs, path, counter, func(r io.Reader, size int64) ([]protocol.BlockInfo, error) {
+		return Blocks(ctx, r, blockSize, size, counter, useWeakHashes)
+	})
+}
+
+// HashFileChunked is like HashFile, but uses content defined chunking with
+// the given average block size.
+func HashFileChunked(ctx context.Context, folderID string, fs fs.Filesystem, path string, avgBlockSize int, counter Counter) ([]protocol.BlockInfo, error) {
+	return hashFile(ctx, folderID, fs, path, counter, func(r io.Reader, size int64) ([]protocol.BlockInfo, error) {
+		return ChunkedBlocks(ctx, r, avgBlockSize, size, counter)
+	})
+}
+
+func hashFile(ctx context.Context, folderID string, fs fs.Filesystem, path string, counter Counter, hashBlocks func(io.Reader, int64) ([]protocol.BlockInfo, error)) ([]protocol.BlockInfo, error) {
 	fd, err := fs.Open(path)
 	if err != nil {
 		l.Debugln("open:", err)
@@ -36,7 +51,7 @@ func HashFile(ctx context.Context, folderID string, fs fs.Filesystem, path strin
 
 	// Hash the file. This may take a while for large files.
 
-	blocks, err := Blocks(ctx, fd, blockSize, size, counter, useWeakHashes)
+	blocks, err := hashBlocks(fd, size)
 	if err != nil {
 		l.Debugln("blocks:", err)
 		return nil, err
@@ -71,9 +86,10 @@ type parallelHasher struct {
 	counter  Counter
 	done     chan<- struct{}
 	wg       sync.WaitGroup
+	chunked  bool
 }
 
-func newParallelHasher(ctx context.Context, folderID string, fs fs.Filesystem, workers int, outbox chan<- ScanResult, inbox <-chan protocol.FileInfo, counter Counter, done chan<- struct{}) {
+func newParallelHasher(ctx context.Context, folderID string, fs fs.Filesystem, workers int, outbox chan<- ScanResult, inbox <-chan protocol.FileInfo, counter Counter, done chan<- struct{}, chunked bool) {
 	ph := &parallelHasher{
 		folderID: folderID,
 		fs:       fs,
@@ -82,6 +98,7 @@ func newParallelHasher(ctx context.Context, folderID string, fs fs.Filesystem, w
 		counter:  counter,
 		done:     done,
 		wg:       sync.NewWaitGroup(),
+		chunked:  chunked,
 	}
 
 	ph.wg.Add(workers)
@@ -108,7 +125,13 @@ func (ph *parallelHasher) hashFiles(ctx context.Context) {
 				panic("Bug. Asked to hash a directory or a deleted file.")
 			}
 
-			blocks, err := HashFile(ctx, ph.folderID, ph.fs, f.Name, f.BlockSize(), ph.counter, true)
+			var blocks []protocol.BlockInfo
+			var err error
+			if ph.chunked {
+				blocks, err = HashFileChunked(ctx, ph.folderID, ph.fs, f.Name, f.BlockSize(), ph.counter)
+			} else {
+				blocks, err = HashFile(ctx, ph.folderID, ph.fs, f.Name, f.BlockSize(), ph.counter, true)
+			}
 			if err != nil {
 				handleError(ctx, "hashing", f.Name, err, ph.outbox)
 				continue
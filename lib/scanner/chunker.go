@@ -0,0 +1,148 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/bits"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// gearTable maps each byte value to a pseudo random number for the rolling
+// gear hash. The table must be identical on all devices, as it determines
+// where blocks start; changing it invalidates the block lists of all files
+// hashed with content defined chunking.
+var gearTable = func() [256]uint64 {
+	var table [256]uint64
+	// splitmix64 with a fixed seed
+	state := uint64(0x5379_6e63_7468_696e) // "Syncthin"
+	for i := range table {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// A chunker finds content defined block boundaries using a gear hash over
+// the last 64 bytes, with the normalized chunking of FastCDC: cut points
+// are made less likely before the average size and more likely after it,
+// which keeps the block sizes close to the average.
+type chunker struct {
+	minSize, avgSize, maxSize int
+	maskSmall, maskLarge      uint64
+}
+
+func newChunker(avgSize int) chunker {
+	b := bits.Len(uint(avgSize)) - 1
+	return chunker{
+		minSize:   avgSize / 4,
+		avgSize:   avgSize,
+		maxSize:   min(avgSize*4, protocol.MaxBlockSize),
+		maskSmall: ^uint64(0) << (64 - (b + 2)),
+		maskLarge: ^uint64(0) << (64 - (b - 2)),
+	}
+}
+
+// cut returns the length of the block at the start of data, which must
+// either reach the end of the input or be at least maxSize long.
+func (c chunker) cut(data []byte) int {
+	if len(data) <= c.minSize {
+		return len(data)
+	}
+	n := min(len(data), c.maxSize)
+	normal := min(n, c.avgSize)
+	var fp uint64
+	i := c.minSize
+	for ; i < normal; i++ {
+		fp = (fp << 1) + gearTable[data[i]]
+		if fp&c.maskSmall == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + gearTable[data[i]]
+		if fp&c.maskLarge == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
+
+// ChunkedBlocks returns the blockwise hash of the reader, where the block
+// boundaries are chosen by the content instead of at fixed offsets.
+// Inserting or removing data thus only changes the blocks around the
+// edit, while the following blocks keep their hashes. The block sizes vary
+// between a quarter and four times the given average size, capped at
+// protocol.MaxBlockSize. Weak hashes are not calculated, as they are only
+// useful for finding fixed size blocks at shifted offsets.
+func ChunkedBlocks(ctx context.Context, r io.Reader, avgSize int, sizehint int64, counter Counter) ([]protocol.BlockInfo, error) {
+	if counter == nil {
+		counter = &noopCounter{}
+	}
+	if sizehint >= 0 {
+		r = io.LimitReader(r, sizehint)
+	}
+
+	c := newChunker(avgSize)
+	hf := sha256.New()
+	buf := make([]byte, c.maxSize)
+
+	var blocks []protocol.BlockInfo
+	var offset int64
+	filled := 0
+	eof := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		if !eof {
+			n, err := io.ReadFull(r, buf[filled:])
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				eof = true
+			} else if err != nil {
+				return nil, err
+			}
+			filled += n
+		}
+		if filled == 0 {
+			break
+		}
+
+		size := c.cut(buf[:filled])
+		hf.Write(buf[:size])
+		blocks = append(blocks, protocol.BlockInfo{
+			Size:   size,
+			Offset: offset,
+			Hash:   hf.Sum(nil),
+		})
+		hf.Reset()
+		counter.Update(int64(size))
+		offset += int64(size)
+		filled = copy(buf, buf[size:filled])
+	}
+
+	if len(blocks) == 0 {
+		// Empty file
+		blocks = append(blocks, protocol.BlockInfo{
+			Offset: 0,
+			Size:   0,
+			Hash:   SHA256OfNothing,
+		})
+	}
+
+	return blocks, nil
+}
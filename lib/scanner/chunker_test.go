@@ -0,0 +1,81 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestChunkedBlocks(t *testing.T) {
+	const avgSize = protocol.MinBlockSize
+	data := make([]byte, 8<<20)
+	mrand.New(mrand.NewSource(42)).Read(data)
+
+	blocks, err := ChunkedBlocks(context.Background(), bytes.NewReader(data), avgSize, int64(len(data)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newChunker(avgSize)
+	var offset int64
+	for i, b := range blocks {
+		if b.Offset != offset {
+			t.Fatalf("block %d at offset %d, expected %d", i, b.Offset, offset)
+		}
+		if b.Size > c.maxSize || (b.Size < c.minSize && i != len(blocks)-1) {
+			t.Errorf("block %d has size %d outside of [%d, %d]", i, b.Size, c.minSize, c.maxSize)
+		}
+		hash := sha256.Sum256(data[b.Offset : b.Offset+int64(b.Size)])
+		if !bytes.Equal(hash[:], b.Hash) {
+			t.Errorf("block %d has incorrect hash", i)
+		}
+		offset += int64(b.Size)
+	}
+	if offset != int64(len(data)) {
+		t.Fatalf("blocks cover %d bytes, expected %d", offset, len(data))
+	}
+	if avg := len(data) / len(blocks); avg < avgSize/2 || avg > avgSize*2 {
+		t.Errorf("average block size %d is far from %d", avg, avgSize)
+	}
+
+	// Insert a few bytes in the middle. Only the blocks around the edit
+	// should change.
+	edited := append(append(append([]byte{}, data[:len(data)/2]...), "inserted"...), data[len(data)/2:]...)
+	editedBlocks, err := ChunkedBlocks(context.Background(), bytes.NewReader(edited), avgSize, int64(len(edited)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes := make(map[string]struct{}, len(blocks))
+	for _, b := range blocks {
+		hashes[string(b.Hash)] = struct{}{}
+	}
+	changed := 0
+	for _, b := range editedBlocks {
+		if _, ok := hashes[string(b.Hash)]; !ok {
+			changed++
+		}
+	}
+	if changed == 0 || changed > 2 {
+		t.Errorf("expected one or two changed blocks after an insertion, got %d of %d", changed, len(editedBlocks))
+	}
+}
+
+func TestChunkedBlocksEmpty(t *testing.T) {
+	blocks, err := ChunkedBlocks(context.Background(), bytes.NewReader(nil), protocol.MinBlockSize, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 || blocks[0].Size != 0 || !bytes.Equal(blocks[0].Hash, SHA256OfNothing) {
+		t.Error("unexpected blocks for empty input:", blocks)
+	}
+}
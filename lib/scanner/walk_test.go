@@ -493,6 +493,37 @@ func TestWalkReceiveOnly(t *testing.T) {
 	}
 }
 
+func TestWalkRechunk(t *testing.T) {
+	sf := fs.NewWalkFilesystem(&singleFileFS{
+		name:     "testfile.dat",
+		filesize: 1024,
+	})
+
+	current := make(fakeCurrentFiler)
+	files := walkDir(sf, ".", current, nil, 0)
+	if len(files) != 1 {
+		t.Fatal("Should have scanned one file")
+	}
+
+	// Unchanged files hashed with fixed size blocks are left alone, those
+	// with variable blocks are rehashed when content defined chunking is
+	// no longer in use.
+	cur := files[0]
+	current[cur.Name] = cur
+	if files := walkDir(sf, ".", current, nil, 0); len(files) != 0 {
+		t.Fatal("Should not have scanned anything")
+	}
+	cur.Blocks = []protocol.BlockInfo{{Size: 100}, {Offset: 100, Size: 924}}
+	current[cur.Name] = cur
+	files = walkDir(sf, ".", current, nil, 0)
+	if len(files) != 1 {
+		t.Fatal("Should have rehashed the file with variable blocks")
+	}
+	if files[0].HasVariableBlocks() {
+		t.Error("Should have hashed the file with fixed size blocks")
+	}
+}
+
 func TestScanOwnershipPOSIX(t *testing.T) {
 	// This test works on all operating systems because the FakeFS is always POSIXy.
 
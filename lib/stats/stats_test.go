@@ -4,12 +4,10 @@
 // License, v. 2.0. If a copy of the MPL was not distributed with this file,
 // You can obtain one at https://mozilla.org/MPL/2.0/.
 
-// The existence of this file means we get 0% test coverage rather than no
-// test coverage at all. Remove when implementing an actual test.
-
 package stats
 
 import (
+	"slices"
 	"testing"
 	"time"
 
@@ -41,3 +39,62 @@ func TestDeviceStat(t *testing.T) {
 		t.Error("Bad last duration:", d)
 	}
 }
+
+func TestTransferHistory(t *testing.T) {
+	db := backend.OpenLevelDBMemory()
+	defer db.Close()
+
+	r := NewTransferRecorder(db, TransferRetention{})
+	now := time.Date(2025, 3, 31, 22, 30, 0, 0, time.UTC)
+
+	r.DeviceTransfer(protocol.LocalDeviceID, 100, 10)
+	r.FolderTransfer("default", 100, 10)
+	if err := r.flush(now); err != nil {
+		t.Fatal(err)
+	}
+	r.DeviceTransfer(protocol.LocalDeviceID, 200, 20)
+	if err := r.flush(now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	r.DeviceTransfer(protocol.LocalDeviceID, 400, 40)
+	if err := r.flush(now.Add(2 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.prune(now.Add(2*time.Hour), TransferRetention{Hours: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	hour := func(h int) time.Time { return time.Date(2025, 3, 31, h, 0, 0, 0, time.UTC) }
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	cases := []struct {
+		period   TransferPeriod
+		expected []TransferStatistics
+	}{
+		// The first hour is beyond the retention.
+		{TransferPeriodHour, []TransferStatistics{{hour(23), 200, 20}, {day(4, 1), 400, 40}}},
+		{TransferPeriodDay, []TransferStatistics{{day(3, 31), 300, 30}, {day(4, 1), 400, 40}}},
+		{TransferPeriodMonth, []TransferStatistics{{day(3, 1), 300, 30}, {day(4, 1), 400, 40}}},
+	}
+	for _, tc := range cases {
+		hist, err := r.History(tc.period, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hist.Devices[protocol.LocalDeviceID]; !slices.Equal(got, tc.expected) {
+			t.Errorf("%s: got %v, expected %v", tc.period, got, tc.expected)
+		}
+	}
+
+	hist, err := r.History(TransferPeriodDay, day(3, 31), day(4, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TransferStatistics{{day(3, 31), 100, 10}}
+	if got := hist.Folders["default"]; !slices.Equal(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+
+	if _, err := r.History("week", time.Time{}, time.Time{}); err == nil {
+		t.Error("expected an error for an unknown period")
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package stats
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+// How often transfers counted in memory are written to the database.
+const transferFlushInterval = time.Minute
+
+// TransferPeriod is the granularity of the transfer history.
+type TransferPeriod string
+
+const (
+	TransferPeriodHour  TransferPeriod = "hour"
+	TransferPeriodDay   TransferPeriod = "day"
+	TransferPeriodMonth TransferPeriod = "month"
+)
+
+var TransferPeriods = []TransferPeriod{TransferPeriodHour, TransferPeriodDay, TransferPeriodMonth}
+
+// Bytes identifying the period and the kind of subject in database keys.
+var transferPeriodBytes = map[TransferPeriod]byte{
+	TransferPeriodHour:  'h',
+	TransferPeriodDay:   'd',
+	TransferPeriodMonth: 'm',
+}
+
+const (
+	transferKindDevice byte = 'd'
+	transferKindFolder byte = 'f'
+)
+
+// TransferStatistics are the bytes of file data transferred during the
+// period starting at Start, in UTC.
+type TransferStatistics struct {
+	Start    time.Time `json:"start"`
+	InBytes  int64     `json:"inBytes"`
+	OutBytes int64     `json:"outBytes"`
+}
+
+// TransferHistory is the transfer history per device and per folder, each
+// sorted by start.
+type TransferHistory struct {
+	Devices map[protocol.DeviceID][]TransferStatistics `json:"devices"`
+	Folders map[string][]TransferStatistics            `json:"folders"`
+}
+
+// TransferRetention is the number of hours, days and months of history to
+// keep. Zero keeps that history forever.
+type TransferRetention struct {
+	Hours  int
+	Days   int
+	Months int
+}
+
+type transferSubject struct {
+	kind byte
+	id   string
+}
+
+type transferCount struct {
+	in, out int64
+}
+
+// TransferRecorder keeps the history of the bytes of file data transferred
+// per device and per folder, by hour, day and month. Transfers are counted
+// in memory and written to the database every minute, and when the
+// recorder stops. It is safe for use from multiple goroutines.
+type TransferRecorder struct {
+	db        backend.Backend
+	retention TransferRetention
+	pending   map[transferSubject]transferCount
+	mut       sync.Mutex // protects retention and pending
+	flushMut  sync.Mutex // serializes writes to the database
+}
+
+func NewTransferRecorder(dba backend.Backend, retention TransferRetention) *TransferRecorder {
+	return &TransferRecorder{
+		db:        dba,
+		retention: retention,
+		pending:   make(map[transferSubject]transferCount),
+		mut:       sync.NewMutex(),
+		flushMut:  sync.NewMutex(),
+	}
+}
+
+func (r *TransferRecorder) String() string {
+	return fmt.Sprintf("TransferRecorder@%p", r)
+}
+
+func (r *TransferRecorder) SetRetention(retention TransferRetention) {
+	r.mut.Lock()
+	r.retention = retention
+	r.mut.Unlock()
+}
+
+// DeviceTransfer records bytes received from and sent to a device.
+func (r *TransferRecorder) DeviceTransfer(device protocol.DeviceID, in, out int64) {
+	r.add(transferSubject{transferKindDevice, device.String()}, in, out)
+}
+
+// FolderTransfer records bytes received and sent for a folder.
+func (r *TransferRecorder) FolderTransfer(folder string, in, out int64) {
+	r.add(transferSubject{transferKindFolder, folder}, in, out)
+}
+
+func (r *TransferRecorder) add(subject transferSubject, in, out int64) {
+	r.mut.Lock()
+	c := r.pending[subject]
+	c.in += in
+	c.out += out
+	r.pending[subject] = c
+	r.mut.Unlock()
+}
+
+func (r *TransferRecorder) Serve(ctx context.Context) error {
+	ticker := time.NewTicker(transferFlushInterval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			if err := r.flush(now); err != nil {
+				l.Warnln("Storing transfer statistics:", err)
+				continue
+			}
+			if now.Sub(lastPrune) < time.Hour {
+				continue
+			}
+			r.mut.Lock()
+			retention := r.retention
+			r.mut.Unlock()
+			if err := r.prune(now, retention); err != nil {
+				l.Warnln("Pruning transfer statistics:", err)
+				continue
+			}
+			lastPrune = now
+		case <-ctx.Done():
+			if err := r.flush(time.Now()); err != nil {
+				l.Warnln("Storing transfer statistics:", err)
+			}
+			return nil
+		}
+	}
+}
+
+// History returns the transfers per period between since and until, either
+// of which may be zero for no limit.
+func (r *TransferRecorder) History(period TransferPeriod, since, until time.Time) (TransferHistory, error) {
+	pb, ok := transferPeriodBytes[period]
+	if !ok {
+		return TransferHistory{}, fmt.Errorf("unknown transfer period %q", period)
+	}
+	if err := r.flush(time.Now()); err != nil {
+		return TransferHistory{}, err
+	}
+
+	it, err := r.db.NewPrefixIterator([]byte{db.KeyTypeTransferStatistic, pb})
+	if err != nil {
+		return TransferHistory{}, err
+	}
+	defer it.Release()
+
+	// Keys sort by start before ID, so each subject's history comes out in
+	// order.
+	hist := TransferHistory{
+		Devices: make(map[protocol.DeviceID][]TransferStatistics),
+		Folders: make(map[string][]TransferStatistics),
+	}
+	for it.Next() {
+		subject, start, ok := parseTransferKey(it.Key())
+		if !ok {
+			continue
+		}
+		if !since.IsZero() && start.Before(since) || !until.IsZero() && !start.Before(until) {
+			continue
+		}
+		c := parseTransferCount(it.Value())
+		stat := TransferStatistics{Start: start, InBytes: c.in, OutBytes: c.out}
+		switch subject.kind {
+		case transferKindDevice:
+			device, err := protocol.DeviceIDFromString(subject.id)
+			if err != nil {
+				continue
+			}
+			hist.Devices[device] = append(hist.Devices[device], stat)
+		case transferKindFolder:
+			hist.Folders[subject.id] = append(hist.Folders[subject.id], stat)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return TransferHistory{}, err
+	}
+
+	return hist, nil
+}
+
+// flush adds the transfers counted in memory to the buckets containing now.
+func (r *TransferRecorder) flush(now time.Time) error {
+	r.flushMut.Lock()
+	defer r.flushMut.Unlock()
+
+	r.mut.Lock()
+	pending := r.pending
+	r.pending = make(map[transferSubject]transferCount)
+	r.mut.Unlock()
+
+	if err := r.write(pending, now); err != nil {
+		// Put the transfers back, to be retried next time.
+		for subject, c := range pending {
+			r.add(subject, c.in, c.out)
+		}
+		return err
+	}
+	return nil
+}
+
+// write adds the transfers to the buckets containing now, in a single
+// transaction.
+func (r *TransferRecorder) write(pending map[transferSubject]transferCount, now time.Time) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	tx, err := r.db.NewWriteTransaction()
+	if err != nil {
+		return err
+	}
+	defer tx.Release()
+
+	for subject, c := range pending {
+		for _, period := range TransferPeriods {
+			key := transferKey(period, subject, transferPeriodStart(period, now))
+			val, err := tx.Get(key)
+			if err != nil && !backend.IsNotFound(err) {
+				return err
+			}
+			cur := parseTransferCount(val)
+			cur.in += c.in
+			cur.out += c.out
+			if err := tx.Put(key, cur.marshal()); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// prune removes the buckets that have fallen out of the retention.
+func (r *TransferRecorder) prune(now time.Time, retention TransferRetention) error {
+	keep := map[TransferPeriod]int{
+		TransferPeriodHour:  retention.Hours,
+		TransferPeriodDay:   retention.Days,
+		TransferPeriodMonth: retention.Months,
+	}
+	for _, period := range TransferPeriods {
+		if keep[period] <= 0 {
+			continue
+		}
+		cutoff := transferPeriodStart(period, now)
+		switch period {
+		case TransferPeriodHour:
+			cutoff = cutoff.Add(-time.Duration(keep[period]-1) * time.Hour)
+		case TransferPeriodDay:
+			cutoff = cutoff.AddDate(0, 0, -(keep[period] - 1))
+		case TransferPeriodMonth:
+			cutoff = cutoff.AddDate(0, -(keep[period] - 1), 0)
+		}
+
+		it, err := r.db.NewPrefixIterator([]byte{db.KeyTypeTransferStatistic, transferPeriodBytes[period]})
+		if err != nil {
+			return err
+		}
+		var old [][]byte
+		for it.Next() {
+			if _, start, ok := parseTransferKey(it.Key()); ok && start.Before(cutoff) {
+				old = append(old, slices.Clone(it.Key()))
+			}
+		}
+		err = it.Error()
+		it.Release()
+		if err != nil {
+			return err
+		}
+		for _, key := range old {
+			if err := r.db.Delete(key); err != nil {
+				return err
+			}
+		}
+		l.Debugf("%v pruned %d %s buckets before %v", r, len(old), period, cutoff)
+	}
+	return nil
+}
+
+// transferPeriodStart returns the start of the period containing t, in UTC.
+func transferPeriodStart(period TransferPeriod, t time.Time) time.Time {
+	t = t.UTC()
+	switch period {
+	case TransferPeriodHour:
+		return t.Truncate(time.Hour)
+	case TransferPeriodDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func transferKey(period TransferPeriod, subject transferSubject, start time.Time) []byte {
+	key := make([]byte, 11+len(subject.id))
+	key[0] = db.KeyTypeTransferStatistic
+	key[1] = transferPeriodBytes[period]
+	key[2] = subject.kind
+	binary.BigEndian.PutUint64(key[3:], uint64(start.Unix()))
+	copy(key[11:], subject.id)
+	return key
+}
+
+func parseTransferKey(key []byte) (transferSubject, time.Time, bool) {
+	if len(key) < 11 {
+		return transferSubject{}, time.Time{}, false
+	}
+	start := time.Unix(int64(binary.BigEndian.Uint64(key[3:])), 0).UTC()
+	return transferSubject{kind: key[2], id: string(key[11:])}, start, true
+}
+
+func parseTransferCount(val []byte) transferCount {
+	if len(val) != 16 {
+		return transferCount{}
+	}
+	return transferCount{
+		in:  int64(binary.BigEndian.Uint64(val)),
+		out: int64(binary.BigEndian.Uint64(val[8:])),
+	}
+}
+
+func (c transferCount) marshal() []byte {
+	val := make([]byte, 16)
+	binary.BigEndian.PutUint64(val, uint64(c.in))
+	binary.BigEndian.PutUint64(val[8:], uint64(c.out))
+	return val
+}
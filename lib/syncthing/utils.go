@@ -153,3 +153,22 @@ func copyFile(src, dst string) error {
 func OpenDBBackend(path string, tuning config.Tuning) (backend.Backend, error) {
 	return backend.Open(path, backend.Tuning(tuning))
 }
+
+// OpenSQLiteDBBackend opens the SQLite database at path. If it doesn't
+// exist yet but there is a LevelDB database at legacyPath, the contents of
+// that are migrated first and the LevelDB database is renamed out of the
+// way, so that it isn't picked up again when switching back.
+func OpenSQLiteDBBackend(path, legacyPath string) (backend.Backend, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if _, err := os.Stat(legacyPath); err == nil {
+			l.Infoln("Migrating LevelDB database to SQLite; this may take a while")
+			if err := backend.MigrateLevelDBToSQLite(legacyPath, path); err != nil {
+				return nil, fmt.Errorf("migrating database: %w", err)
+			}
+			if err := os.Rename(legacyPath, legacyPath+".migrated"); err != nil {
+				l.Warnln("Failed to rename migrated LevelDB database:", err)
+			}
+		}
+	}
+	return backend.OpenSQLite(path)
+}
@@ -0,0 +1,484 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package versioner
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+func init() {
+	// Register the constructor for this type of versioner
+	factories["dedup"] = newDedup
+}
+
+const (
+	dedupBlocksDir    = "blocks"
+	dedupManifestsDir = "manifests"
+)
+
+var errCorruptBlock = errors.New("archived block is missing or corrupt")
+
+// The dedup versioner stores archived versions as lists of blocks, in a
+// manifest per version, and the blocks themselves in a store addressed by
+// their SHA-256 hash. Blocks are hashed the same way as the scanner does
+// for the folder, so the blocks that are unchanged between versions of a
+// file are only stored once. Blocks that are no longer referenced by any
+// manifest are removed when cleaning.
+type dedup struct {
+	keep         int
+	cleanoutDays int
+	chunked      bool
+	folderFs     fs.Filesystem
+	versionsFs   fs.Filesystem
+	// Held while modifying the block store, so that cleaning doesn't
+	// remove blocks that are being referenced by a new version.
+	mut sync.Mutex
+}
+
+type dedupManifest struct {
+	ModTime     time.Time    `json:"modTime"`
+	Size        int64        `json:"size"`
+	Permissions uint32       `json:"permissions"`
+	Blocks      []dedupBlock `json:"blocks"`
+}
+
+type dedupBlock struct {
+	Size int    `json:"size"`
+	Hash []byte `json:"hash"`
+}
+
+func newDedup(cfg config.FolderConfiguration) Versioner {
+	keep, err := strconv.Atoi(cfg.Versioning.Params["keep"])
+	cleanoutDays, _ := strconv.Atoi(cfg.Versioning.Params["cleanoutDays"])
+	// On error we default to 0, "do not clean out the versioned items"
+
+	if err != nil {
+		keep = 5 // A reasonable default
+	}
+
+	v := &dedup{
+		keep:         keep,
+		cleanoutDays: cleanoutDays,
+		chunked:      cfg.ContentDefinedChunking,
+		folderFs:     cfg.Filesystem(nil),
+		versionsFs:   versionerFsFromFolderCfg(cfg),
+		mut:          sync.NewMutex(),
+	}
+
+	l.Debugf("instantiated %#v", v)
+	return v
+}
+
+func (v *dedup) String() string {
+	return fmt.Sprintf("dedup@%p", v)
+}
+
+// Archive moves the named file away to a version archive. If this function
+// returns nil, the named file does not exist any more (has been archived).
+func (v *dedup) Archive(filePath string) error {
+	filePath = osutil.NativeFilename(filePath)
+	info, err := v.folderFs.Lstat(filePath)
+	if fs.IsNotExist(err) {
+		l.Debugln("not archiving nonexistent file", filePath)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if info.IsSymlink() {
+		panic("bug: attempting to version a symlink")
+	}
+
+	if err := v.archive(filePath, info); err != nil {
+		return err
+	}
+	if err := v.folderFs.Remove(filePath); err != nil {
+		return err
+	}
+
+	cleanVersions(v.versionsFs, findAllVersions(v.versionsFs, filepath.Join(dedupManifestsDir, filePath)), v.toRemove)
+	return nil
+}
+
+func (v *dedup) archive(filePath string, info fs.FileInfo) error {
+	fd, err := v.folderFs.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	var blocks []protocol.BlockInfo
+	if v.chunked {
+		blocks, err = scanner.ChunkedBlocks(context.Background(), fd, protocol.BlockSize(info.Size()), info.Size(), nil)
+	} else {
+		blocks, err = scanner.Blocks(context.Background(), fd, protocol.BlockSize(info.Size()), info.Size(), nil, false)
+	}
+	if err != nil {
+		return err
+	}
+
+	manifest := dedupManifest{
+		ModTime:     info.ModTime(),
+		Size:        info.Size(),
+		Permissions: uint32(info.Mode() & fs.ModePerm),
+		Blocks:      make([]dedupBlock, len(blocks)),
+	}
+
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if err := v.ensureVersionsDir(); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 0, protocol.MaxBlockSize)
+	for i, block := range blocks {
+		manifest.Blocks[i] = dedupBlock{Size: block.Size, Hash: block.Hash}
+
+		name := dedupBlockPath(block.Hash)
+		if _, err := v.versionsFs.Lstat(name); err == nil {
+			// Already stored as part of another version
+			continue
+		}
+		buf = buf[:block.Size]
+		if _, err := fd.ReadAt(buf, block.Offset); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if !scanner.Validate(buf, block.Hash, 0) {
+			return fmt.Errorf("%s: file changed while archiving", filePath)
+		}
+		if err := v.versionsFs.MkdirAll(filepath.Dir(name), 0o755); err != nil && !fs.IsExist(err) {
+			return err
+		}
+		if err := writeFileAtomic(v.versionsFs, name, buf); err != nil {
+			return err
+		}
+	}
+
+	bs, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(dedupManifestsDir, TagFilename(filePath, time.Now().Format(TimeFormat)))
+	if err := v.versionsFs.MkdirAll(filepath.Dir(name), 0o755); err != nil && !fs.IsExist(err) {
+		return err
+	}
+	l.Debugln("archiving", filePath, "as", name, "with", len(blocks), "blocks")
+	return writeFileAtomic(v.versionsFs, name, bs)
+}
+
+func (v *dedup) ensureVersionsDir() error {
+	_, err := v.versionsFs.Stat(".")
+	if err == nil {
+		return nil
+	}
+	if !fs.IsNotExist(err) {
+		return err
+	}
+	l.Debugln("creating versions dir")
+	if err := v.versionsFs.MkdirAll(".", 0o755); err != nil {
+		return err
+	}
+	_ = v.versionsFs.Hide(".")
+	return nil
+}
+
+func (v *dedup) GetVersions() (map[string][]FileVersion, error) {
+	files := make(map[string][]FileVersion)
+	if _, err := v.versionsFs.Lstat(dedupManifestsDir); fs.IsNotExist(err) {
+		return files, nil
+	}
+
+	err := v.versionsFs.Walk(dedupManifestsDir, func(path string, f fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() || !f.IsRegular() || fs.IsTemporary(path) {
+			return nil
+		}
+
+		name, tag := UntagFilename(strings.TrimPrefix(path, dedupManifestsDir+string(filepath.Separator)))
+		versionTime, err := time.ParseInLocation(TimeFormat, tag, time.Local)
+		if name == "" || err != nil {
+			return nil
+		}
+		manifest, err := v.readManifest(path)
+		if err != nil {
+			l.Debugf("Versioner: reading manifest %q: %v", path, err)
+			return nil
+		}
+
+		name = osutil.NormalizedFilename(name)
+		files[name] = append(files[name], FileVersion{
+			VersionTime: versionTime,
+			ModTime:     manifest.ModTime.Truncate(time.Second),
+			Size:        manifest.Size,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (v *dedup) Restore(filePath string, versionTime time.Time) error {
+	filePath = osutil.NativeFilename(filePath)
+	tag := versionTime.In(time.Local).Truncate(time.Second).Format(TimeFormat)
+	manifestPath := filepath.Join(dedupManifestsDir, TagFilename(filePath, tag))
+	manifest, err := v.readManifest(manifestPath)
+	if fs.IsNotExist(err) {
+		return errNotFound
+	} else if err != nil {
+		return err
+	}
+
+	// If the something already exists where we are restoring to, archive existing file for versioning
+	// remove if it's a symlink, or fail if it's a directory
+	if info, err := v.folderFs.Lstat(filePath); err == nil {
+		switch {
+		case info.IsDir():
+			return ErrDirectory
+		case info.IsSymlink():
+			// Remove existing symlinks (as we don't want to archive them)
+			if err := v.folderFs.Remove(filePath); err != nil {
+				return fmt.Errorf("removing existing symlink: %w", err)
+			}
+		case info.IsRegular():
+			if err := v.Archive(filePath); err != nil {
+				return fmt.Errorf("archiving existing file: %w", err)
+			}
+		default:
+			panic("bug: unknown item type")
+		}
+	} else if !fs.IsNotExist(err) {
+		return err
+	}
+
+	_ = v.folderFs.MkdirAll(filepath.Dir(filePath), 0o755)
+	tempName := fs.TempName(filePath)
+	if err := v.assemble(tempName, manifest); err != nil {
+		_ = v.folderFs.Remove(tempName)
+		return err
+	}
+	if err := v.folderFs.Rename(tempName, filePath); err != nil {
+		_ = v.folderFs.Remove(tempName)
+		return err
+	}
+
+	// The version has been restored, like it is moved out of the archive
+	// by the other versioners. Its blocks are removed when cleaning.
+	return v.versionsFs.Remove(manifestPath)
+}
+
+func (v *dedup) OpenVersion(filePath string, versionTime time.Time) (io.ReadCloser, error) {
+	filePath = osutil.NativeFilename(filePath)
+	tag := versionTime.In(time.Local).Truncate(time.Second).Format(TimeFormat)
+	manifest, err := v.readManifest(filepath.Join(dedupManifestsDir, TagFilename(filePath, tag)))
+	if fs.IsNotExist(err) {
+		return nil, errNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &dedupReader{v: v, blocks: manifest.Blocks}, nil
+}
+
+// dedupReader reads the blocks of a manifest in turn.
+type dedupReader struct {
+	v      *dedup
+	blocks []dedupBlock
+	buf    []byte
+}
+
+func (r *dedupReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if len(r.blocks) == 0 {
+			return 0, io.EOF
+		}
+		data, err := r.v.readBlock(r.blocks[0])
+		if err != nil {
+			return 0, err
+		}
+		r.buf, r.blocks = data, r.blocks[1:]
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (*dedupReader) Close() error {
+	return nil
+}
+
+// assemble writes the blocks of the manifest to the named file in the
+// folder.
+func (v *dedup) assemble(name string, manifest dedupManifest) error {
+	fd, err := v.folderFs.Create(name)
+	if err != nil {
+		return err
+	}
+	for _, block := range manifest.Blocks {
+		data, err := v.readBlock(block)
+		if err != nil {
+			fd.Close()
+			return err
+		}
+		if _, err := fd.Write(data); err != nil {
+			fd.Close()
+			return err
+		}
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	if manifest.Permissions != 0 {
+		_ = v.folderFs.Chmod(name, fs.FileMode(manifest.Permissions))
+	}
+	return v.folderFs.Chtimes(name, manifest.ModTime, manifest.ModTime)
+}
+
+func (v *dedup) readBlock(block dedupBlock) ([]byte, error) {
+	fd, err := v.versionsFs.Open(dedupBlockPath(block.Hash))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %x: %v", errCorruptBlock, block.Hash, err)
+	}
+	defer fd.Close()
+	data := make([]byte, block.Size)
+	if _, err := io.ReadFull(fd, data); err != nil || !scanner.Validate(data, block.Hash, 0) {
+		return nil, fmt.Errorf("%w: %x", errCorruptBlock, block.Hash)
+	}
+	return data, nil
+}
+
+func (v *dedup) readManifest(name string) (dedupManifest, error) {
+	var manifest dedupManifest
+	fd, err := v.versionsFs.Open(name)
+	if err != nil {
+		return manifest, err
+	}
+	defer fd.Close()
+	err = json.NewDecoder(fd).Decode(&manifest)
+	return manifest, err
+}
+
+func (v *dedup) Clean(ctx context.Context) error {
+	if err := clean(ctx, v.versionsFs, v.toRemove); err != nil {
+		return err
+	}
+	return v.collectGarbage(ctx)
+}
+
+func (v *dedup) toRemove(versions []string, now time.Time) []string {
+	return simple{keep: v.keep, cleanoutDays: v.cleanoutDays}.toRemove(versions, now)
+}
+
+// collectGarbage removes the blocks that aren't referenced by any version.
+func (v *dedup) collectGarbage(ctx context.Context) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if _, err := v.versionsFs.Lstat(dedupBlocksDir); fs.IsNotExist(err) {
+		return nil
+	}
+
+	used := make(map[string]struct{})
+	if _, err := v.versionsFs.Lstat(dedupManifestsDir); err == nil {
+		err := v.versionsFs.Walk(dedupManifestsDir, func(path string, f fs.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			if !f.IsRegular() || fs.IsTemporary(path) {
+				return nil
+			}
+			manifest, err := v.readManifest(path)
+			if err != nil {
+				// Keep everything rather than risk losing data that
+				// might still be needed.
+				return fmt.Errorf("reading manifest %q: %w", path, err)
+			}
+			for _, block := range manifest.Blocks {
+				used[hex.EncodeToString(block.Hash)] = struct{}{}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	dirTracker := make(emptyDirTracker)
+	err := v.versionsFs.Walk(dedupBlocksDir, func(path string, f fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if f.IsDir() {
+			dirTracker.addDir(path)
+			return nil
+		}
+		if _, ok := used[filepath.Base(path)]; ok {
+			dirTracker.addFile(path)
+			return nil
+		}
+		l.Debugln("Versioner: removing unused block", path)
+		return v.versionsFs.Remove(path)
+	})
+	if err != nil {
+		return err
+	}
+	dirTracker.deleteEmptyDirs(v.versionsFs)
+	return nil
+}
+
+func dedupBlockPath(hash []byte) string {
+	h := hex.EncodeToString(hash)
+	return filepath.Join(dedupBlocksDir, h[:2], h)
+}
+
+// writeFileAtomic writes the data to a temporary file which is then renamed
+// into place, so that there are never partially written files by the name.
+func writeFileAtomic(filesystem fs.Filesystem, name string, data []byte) error {
+	tempName := fs.TempName(name)
+	fd, err := filesystem.Create(tempName)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(data); err != nil {
+		fd.Close()
+		_ = filesystem.Remove(tempName)
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		_ = filesystem.Remove(tempName)
+		return err
+	}
+	return filesystem.Rename(tempName, name)
+}
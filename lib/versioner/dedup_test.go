@@ -0,0 +1,146 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package versioner
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestDedupArchiveRestore(t *testing.T) {
+	cfg := config.FolderConfiguration{
+		FilesystemType: config.FilesystemTypeBasic,
+		Path:           t.TempDir(),
+		Versioning: config.VersioningConfiguration{
+			Type:   "dedup",
+			FSType: config.FilesystemTypeBasic,
+			FSPath: t.TempDir(),
+			Params: map[string]string{"keep": "5"},
+		},
+	}
+	folderFs := cfg.Filesystem(nil)
+	versionsFs := fs.NewFilesystem(fs.FilesystemTypeBasic, cfg.Versioning.FSPath)
+	v := newDedup(cfg).(*dedup)
+
+	original := make([]byte, 4*protocol.MinBlockSize)
+	rand.Read(original)
+	writeFile(t, folderFs, "file", string(original))
+
+	if err := v.Archive("file"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := folderFs.Lstat("file"); !fs.IsNotExist(err) {
+		t.Fatal("expected file to be archived, got", err)
+	}
+	if n := countDedupBlocks(t, versionsFs); n != 4 {
+		t.Fatalf("expected 4 stored blocks, got %d", n)
+	}
+
+	// Move the first version back in time, so the next one doesn't get the
+	// same tag.
+	versionTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	versions, err := v.GetVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagged := func(tm time.Time) string {
+		return filepath.Join(dedupManifestsDir, TagFilename("file", tm.Format(TimeFormat)))
+	}
+	if err := versionsFs.Rename(tagged(versions["file"][0].VersionTime), tagged(versionTime)); err != nil {
+		t.Fatal(err)
+	}
+
+	modified := append([]byte(nil), original...)
+	modified[2*protocol.MinBlockSize] ^= 0xff
+	writeFile(t, folderFs, "file", string(modified))
+	if err := v.Archive("file"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countDedupBlocks(t, versionsFs); n != 5 {
+		t.Fatalf("expected unchanged blocks to be shared, got %d stored blocks", n)
+	}
+
+	versions, err = v.GetVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions["file"]) != 2 {
+		t.Fatal("expected two versions, got", versions)
+	}
+	for _, fv := range versions["file"] {
+		if fv.Size != int64(len(original)) {
+			t.Error("unexpected version size", fv.Size)
+		}
+	}
+
+	fd, err := v.OpenVersion("file", versionTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(fd)
+	fd.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != string(original) {
+		t.Error("opened version differs from the original")
+	}
+
+	if err := v.Restore("file", versionTime); err != nil {
+		t.Fatal(err)
+	}
+	if content := readFile(t, folderFs, "file"); content != string(original) {
+		t.Error("restored content differs from the original")
+	}
+
+	// The restored version is gone, so its block isn't needed anymore.
+	if err := v.Clean(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := countDedupBlocks(t, versionsFs); n != 4 {
+		t.Errorf("expected 4 stored blocks after cleaning, got %d", n)
+	}
+	versions, err = v.GetVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions["file"]) != 1 {
+		t.Fatal("expected one version after restoring, got", versions)
+	}
+	if err := v.Restore("file", versions["file"][0].VersionTime); err != nil {
+		t.Fatal(err)
+	}
+	if content := readFile(t, folderFs, "file"); content != string(modified) {
+		t.Error("restored content differs from the modified file")
+	}
+}
+
+func countDedupBlocks(t *testing.T, versionsFs fs.Filesystem) int {
+	t.Helper()
+	n := 0
+	err := versionsFs.Walk(dedupBlocksDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsRegular() {
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
@@ -8,6 +8,7 @@ package versioner
 
 import (
 	"context"
+	"io"
 	"sort"
 	"strconv"
 	"time"
@@ -67,6 +68,10 @@ func (v simple) GetVersions() (map[string][]FileVersion, error) {
 	return retrieveVersions(v.versionsFs)
 }
 
+func (v simple) OpenVersion(filePath string, versionTime time.Time) (io.ReadCloser, error) {
+	return openVersion(v.versionsFs, filePath, versionTime, TagFilename)
+}
+
 func (v simple) Restore(filepath string, versionTime time.Time) error {
 	return restoreFile(v.copyRangeMethod, v.versionsFs, v.folderFs, filepath, versionTime, TagFilename)
 }
@@ -9,6 +9,7 @@ package versioner
 import (
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strconv"
 	"time"
@@ -129,6 +130,10 @@ func (v *staggered) GetVersions() (map[string][]FileVersion, error) {
 	return retrieveVersions(v.versionsFs)
 }
 
+func (v *staggered) OpenVersion(filePath string, versionTime time.Time) (io.ReadCloser, error) {
+	return openVersion(v.versionsFs, filePath, versionTime, TagFilename)
+}
+
 func (v *staggered) Restore(filepath string, versionTime time.Time) error {
 	return restoreFile(v.copyRangeMethod, v.versionsFs, v.folderFs, filepath, versionTime, TagFilename)
 }
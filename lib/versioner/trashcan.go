@@ -9,6 +9,7 @@ package versioner
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"time"
 
@@ -107,6 +108,10 @@ func (t *trashcan) GetVersions() (map[string][]FileVersion, error) {
 	return retrieveVersions(t.versionsFs)
 }
 
+func (t *trashcan) OpenVersion(filePath string, versionTime time.Time) (io.ReadCloser, error) {
+	return openVersion(t.versionsFs, filePath, versionTime, TagFilename)
+}
+
 func (t *trashcan) Restore(filepath string, versionTime time.Time) error {
 	// If we have an untagged file A and want to restore it on top of existing file A, we can't first archive the
 	// existing A as we'd overwrite the old A version, therefore when we archive existing file, we archive it with a
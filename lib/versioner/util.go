@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -258,6 +259,21 @@ func restoreFile(method fs.CopyRangeMethod, src, dst fs.Filesystem, filePath str
 	return err
 }
 
+// openVersion opens the archived version of the file, found the same way
+// as by restoreFile.
+func openVersion(src fs.Filesystem, filePath string, versionTime time.Time, tagger fileTagger) (io.ReadCloser, error) {
+	filePath = osutil.NativeFilename(filePath)
+	tag := versionTime.In(time.Local).Truncate(time.Second).Format(TimeFormat)
+	taggedFilePath := tagger(filePath, tag)
+	if info, err := src.Lstat(taggedFilePath); err == nil && info.IsRegular() {
+		return src.Open(taggedFilePath)
+	}
+	if info, err := src.Lstat(filePath); err == nil && info.IsRegular() && info.ModTime().Truncate(time.Second).Equal(versionTime) {
+		return src.Open(filePath)
+	}
+	return nil, errNotFound
+}
+
 func versionerFsFromFolderCfg(cfg config.FolderConfiguration) (versionsFs fs.Filesystem) {
 	folderFs := cfg.Filesystem(nil)
 	if cfg.Versioning.FSPath == "" {
@@ -12,6 +12,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/syncthing/syncthing/lib/config"
@@ -24,6 +25,11 @@ type Versioner interface {
 	Clean(context.Context) error
 }
 
+// A VersionOpener can read the contents of archived versions.
+type VersionOpener interface {
+	OpenVersion(filePath string, versionTime time.Time) (io.ReadCloser, error)
+}
+
 type FileVersion struct {
 	VersionTime time.Time `json:"versionTime"`
 	ModTime     time.Time `json:"modTime"`
@@ -81,3 +87,12 @@ func (v *versionerWithErrorContext) Restore(filePath string, versionTime time.Ti
 func (v *versionerWithErrorContext) Clean(ctx context.Context) error {
 	return v.wrapError(v.Versioner.Clean(ctx), "clean")
 }
+
+func (v *versionerWithErrorContext) OpenVersion(filePath string, versionTime time.Time) (io.ReadCloser, error) {
+	opener, ok := v.Versioner.(VersionOpener)
+	if !ok {
+		return nil, v.wrapError(ErrRestorationNotSupported, "open version")
+	}
+	fd, err := opener.OpenVersion(filePath, versionTime)
+	return fd, v.wrapError(err, "open version")
+}
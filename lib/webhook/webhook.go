@@ -0,0 +1,487 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+// Package webhook sends events to the webhooks in the configuration as HTTP
+// POST requests. Events are queued in the database until delivered, so
+// that they survive restarts and the receiver being unavailable for a
+// while.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/thejerf/suture/v4"
+
+	"github.com/syncthing/syncthing/lib/build"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/svcutil"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+const (
+	// Events beyond this many waiting to be delivered are dropped.
+	maxQueued = 10000
+	// A delivery is given up after this many failed attempts, with the
+	// time between them doubling from minBackoff up to maxBackoff.
+	maxAttempts    = 10
+	minBackoff     = time.Second
+	maxBackoff     = time.Hour
+	requestTimeout = 30 * time.Second
+)
+
+// A delivery is an event waiting to be sent to a webhook.
+type delivery struct {
+	URL         string          `json:"url"`
+	Type        string          `json:"type"`
+	Body        json.RawMessage `json:"body"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+}
+
+// The Dispatcher queues the events for the webhooks interested in them, and
+// delivers them in order to each webhook. The queues are kept in memory,
+// with the database holding a copy to continue from after a restart.
+type Dispatcher struct {
+	*suture.Supervisor
+
+	cfg      config.Wrapper
+	evLogger events.Logger
+	db       backend.Backend
+	client   *http.Client
+
+	mut     sync.Mutex
+	queues  map[string]*hookQueue // webhook URL -> its queue
+	nextSeq uint64
+	queued  int  // deliveries in all queues
+	full    bool // warned about the queue being full
+}
+
+// A hookQueue holds the deliveries waiting for a webhook, oldest first.
+// Each has its own delivery loop, so that a slow or failing webhook
+// doesn't hold up the others. The queue and its loop go away with the
+// webhook.
+type hookQueue struct {
+	url        string
+	deliveries []queuedDelivery // protected by the Dispatcher's mut
+	wakeup     chan struct{}
+	token      suture.ServiceToken
+}
+
+func New(cfg config.Wrapper, evLogger events.Logger, db backend.Backend) *Dispatcher {
+	d := &Dispatcher{
+		Supervisor: suture.New("webhook.Dispatcher", svcutil.SpecWithDebugLogger(l)),
+		cfg:        cfg,
+		evLogger:   evLogger,
+		db:         db,
+		client:     &http.Client{Timeout: requestTimeout},
+		mut:        sync.NewMutex(),
+		queues:     make(map[string]*hookQueue),
+	}
+
+	// Continue with the deliveries left over from before.
+	pending, err := d.pending()
+	if err != nil {
+		l.Warnln("Reading webhook queue:", err)
+	}
+	d.mut.Lock()
+	for _, qd := range pending {
+		q := d.queueLocked(qd.URL)
+		q.deliveries = append(q.deliveries, qd)
+	}
+	d.queued = len(pending)
+	if len(pending) > 0 {
+		d.nextSeq = pending[len(pending)-1].seq + 1
+	}
+	d.mut.Unlock()
+
+	d.Add(svcutil.AsService(d.listen, fmt.Sprintf("%s/listen", d)))
+	return d
+}
+
+func (d *Dispatcher) String() string {
+	return fmt.Sprintf("webhook.Dispatcher@%p", d)
+}
+
+// listen queues the events for the webhooks interested in them.
+func (d *Dispatcher) listen(ctx context.Context) error {
+	d.cfg.Subscribe(d)
+	defer d.cfg.Unsubscribe(d)
+
+	sub := d.evLogger.Subscribe(events.AllEvents)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev, ok := <-sub.C():
+			if !ok {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			d.handle(ev)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// CommitConfiguration drops the queues of the webhooks that were removed.
+func (d *Dispatcher) CommitConfiguration(_, to config.Configuration) bool {
+	urls := make(map[string]struct{}, len(to.Options.Webhooks))
+	for _, hook := range to.Options.Webhooks {
+		urls[hook.URL] = struct{}{}
+	}
+	d.mut.Lock()
+	var removed []*hookQueue
+	for url, q := range d.queues {
+		if _, ok := urls[url]; !ok {
+			removed = append(removed, q)
+		}
+	}
+	d.mut.Unlock()
+
+	for _, q := range removed {
+		d.removeQueue(q)
+	}
+	return true
+}
+
+func (d *Dispatcher) handle(ev events.Event) {
+	hooks := d.cfg.Options().Webhooks
+	if len(hooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		l.Debugln("Marshalling event:", err)
+		return
+	}
+	folder, device := eventSubject(ev.Type, body)
+
+	queued := make(map[string]struct{})
+	for _, hook := range hooks {
+		if ev.Type&hook.EventMask() == 0 || !hook.Matches(folder, device) {
+			continue
+		}
+		if _, ok := queued[hook.URL]; ok {
+			continue
+		}
+		queued[hook.URL] = struct{}{}
+		if err := d.enqueue(delivery{URL: hook.URL, Type: ev.Type.String(), Body: body}); err != nil {
+			l.Warnln("Queueing event for webhook:", err)
+		}
+	}
+}
+
+// eventSubject returns the folder and device the event is about, if any.
+func eventSubject(typ events.EventType, body []byte) (string, protocol.DeviceID) {
+	var ev struct {
+		Data struct {
+			Folder string `json:"folder"`
+			Device string `json:"device"`
+			ID     string `json:"id"`
+		} `json:"data"`
+	}
+	// Events without an object as data aren't about anything in particular.
+	_ = json.Unmarshal(body, &ev)
+
+	folder, device := ev.Data.Folder, ev.Data.Device
+	switch typ {
+	case events.DeviceConnected, events.DeviceDisconnected:
+		device = ev.Data.ID
+	case events.FolderPaused, events.FolderResumed:
+		folder = ev.Data.ID
+	}
+	deviceID, _ := protocol.DeviceIDFromString(device)
+	return folder, deviceID
+}
+
+func (d *Dispatcher) enqueue(del delivery) error {
+	bs, err := json.Marshal(del)
+	if err != nil {
+		return err
+	}
+
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	if d.queued >= maxQueued {
+		if !d.full {
+			l.Warnf("More than %d events waiting for webhooks; dropping new ones until they are delivered", maxQueued)
+			d.full = true
+		}
+		return nil
+	}
+	if err := d.db.Put(deliveryKey(d.nextSeq), bs); err != nil {
+		return err
+	}
+	q := d.queueLocked(del.URL)
+	q.deliveries = append(q.deliveries, queuedDelivery{delivery: del, seq: d.nextSeq})
+	d.nextSeq++
+	d.queued++
+
+	select {
+	case q.wakeup <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// queueLocked returns the queue of the webhook, starting its delivery loop
+// if it is new.
+func (d *Dispatcher) queueLocked(url string) *hookQueue {
+	if q, ok := d.queues[url]; ok {
+		return q
+	}
+	q := &hookQueue{url: url, wakeup: make(chan struct{}, 1)}
+	d.queues[url] = q
+	q.token = d.Add(svcutil.AsService(func(ctx context.Context) error {
+		return d.deliver(ctx, q)
+	}, fmt.Sprintf("%s/deliver/%s", d, url)))
+	return q
+}
+
+// deliver sends the events queued for a webhook until the context is
+// cancelled.
+func (d *Dispatcher) deliver(ctx context.Context, q *hookQueue) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-q.wakeup:
+			timer.Stop()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wait := d.deliverDue(ctx, q)
+		timer.Reset(wait)
+	}
+}
+
+type queuedDelivery struct {
+	delivery
+	seq uint64
+}
+
+// deliverDue sends the deliveries of the queue that are due, in order, and
+// returns how long to wait for the next one to be due.
+func (d *Dispatcher) deliverDue(ctx context.Context, q *hookQueue) time.Duration {
+	for {
+		if ctx.Err() != nil {
+			return 0
+		}
+
+		d.mut.Lock()
+		if len(q.deliveries) == 0 {
+			d.mut.Unlock()
+			return maxBackoff
+		}
+		qd := q.deliveries[0]
+		d.mut.Unlock()
+
+		hook, ok := d.webhook(q.url)
+		if !ok {
+			// The webhook has been removed.
+			d.removeQueue(q)
+			return maxBackoff
+		}
+		if wait := time.Until(qd.NextAttempt); wait > 0 {
+			return wait
+		}
+
+		err := d.post(ctx, hook, qd)
+		if err == nil {
+			d.remove(q)
+			continue
+		}
+		if ctx.Err() != nil {
+			return 0
+		}
+
+		qd.Attempts++
+		var permanent *permanentError
+		if errors.As(err, &permanent) || qd.Attempts >= maxAttempts {
+			l.Warnf("Giving up delivering %s event to webhook %s after %d attempts: %v", qd.Type, qd.URL, qd.Attempts, err)
+			d.remove(q)
+			continue
+		}
+		l.Debugf("Delivering %s event to webhook %s (attempt %d): %v", qd.Type, qd.URL, qd.Attempts, err)
+		wait := backoff(qd.Attempts)
+		qd.NextAttempt = time.Now().Add(wait)
+		d.mut.Lock()
+		q.deliveries[0] = qd
+		d.mut.Unlock()
+		if bs, err := json.Marshal(qd.delivery); err == nil {
+			if err := d.db.Put(deliveryKey(qd.seq), bs); err != nil {
+				l.Warnln("Updating webhook queue:", err)
+			}
+		}
+		return wait
+	}
+}
+
+// webhook returns the first webhook in the configuration with the URL.
+func (d *Dispatcher) webhook(url string) (config.Webhook, bool) {
+	for _, hook := range d.cfg.Options().Webhooks {
+		if hook.URL == url {
+			return hook, true
+		}
+	}
+	return config.Webhook{}, false
+}
+
+func backoff(attempts int) time.Duration {
+	wait := minBackoff << (attempts - 1)
+	if wait <= 0 || wait > maxBackoff {
+		return maxBackoff
+	}
+	return wait
+}
+
+// A permanentError is a failure that retrying won't fix.
+type permanentError struct {
+	status string
+}
+
+func (e *permanentError) Error() string {
+	return e.status
+}
+
+func (d *Dispatcher) post(ctx context.Context, hook config.Webhook, qd queuedDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(qd.Body))
+	if err != nil {
+		return &permanentError{err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "syncthing/"+build.Version)
+	req.Header.Set("X-Syncthing-Event", qd.Type)
+	req.Header.Set("X-Syncthing-Delivery", strconv.FormatUint(qd.seq, 10))
+	if hook.Secret != "" {
+		req.Header.Set("X-Syncthing-Signature", Signature(hook.Secret, qd.Body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return &permanentError{resp.Status}
+	default:
+		return errors.New(resp.Status)
+	}
+}
+
+// Signature returns the value of the X-Syncthing-Signature header for the
+// given request body.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// pending returns the deliveries queued in the database, oldest first,
+// dropping those that can't be read.
+func (d *Dispatcher) pending() ([]queuedDelivery, error) {
+	it, err := d.db.NewPrefixIterator([]byte{db.KeyTypeWebhookDelivery})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	var pending []queuedDelivery
+	var unreadable []uint64
+	for it.Next() {
+		key := it.Key()
+		if len(key) != 9 {
+			continue
+		}
+		qd := queuedDelivery{seq: binary.BigEndian.Uint64(key[1:])}
+		if err := json.Unmarshal(it.Value(), &qd.delivery); err != nil {
+			l.Debugln("Dropping unreadable webhook delivery:", err)
+			unreadable = append(unreadable, qd.seq)
+			continue
+		}
+		pending = append(pending, qd)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	it.Release()
+
+	for _, seq := range unreadable {
+		d.db.Delete(deliveryKey(seq))
+	}
+	return pending, nil
+}
+
+// remove drops the oldest delivery of the queue.
+func (d *Dispatcher) remove(q *hookQueue) {
+	d.mut.Lock()
+	qd := q.deliveries[0]
+	q.deliveries = q.deliveries[1:]
+	d.removedLocked(1)
+	d.mut.Unlock()
+
+	if err := d.db.Delete(deliveryKey(qd.seq)); err != nil {
+		l.Warnln("Updating webhook queue:", err)
+	}
+}
+
+// removeQueue drops the queue of a removed webhook with all its
+// deliveries, and stops its delivery loop.
+func (d *Dispatcher) removeQueue(q *hookQueue) {
+	d.mut.Lock()
+	if d.queues[q.url] == q {
+		delete(d.queues, q.url)
+	}
+	dropped := q.deliveries
+	q.deliveries = nil
+	d.removedLocked(len(dropped))
+	d.mut.Unlock()
+
+	for _, qd := range dropped {
+		if err := d.db.Delete(deliveryKey(qd.seq)); err != nil {
+			l.Warnln("Updating webhook queue:", err)
+		}
+	}
+	// Fails only when we're not running, with no loop to stop.
+	_ = d.Remove(q.token)
+}
+
+func (d *Dispatcher) removedLocked(n int) {
+	d.queued -= n
+	if d.queued < maxQueued {
+		d.full = false
+	}
+}
+
+func deliveryKey(seq uint64) []byte {
+	key := make([]byte, 9)
+	key[0] = db.KeyTypeWebhookDelivery
+	binary.BigEndian.PutUint64(key[1:], seq)
+	return key
+}
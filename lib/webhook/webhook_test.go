@@ -0,0 +1,292 @@
+// Copyright (C) 2025 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/config/mocks"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+type receiver struct {
+	*httptest.Server
+
+	mut      sync.Mutex
+	statuses []int // to respond with, then 200
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mut.Lock()
+		defer r.mut.Unlock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		if len(r.statuses) > 0 {
+			w.WriteHeader(r.statuses[0])
+			r.statuses = r.statuses[1:]
+		}
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func newTestDispatcher(db backend.Backend, hooks ...config.Webhook) *Dispatcher {
+	cfg := &mocks.Wrapper{}
+	cfg.OptionsReturns(config.OptionsConfiguration{Webhooks: hooks})
+	return New(cfg, events.NoopLogger, db)
+}
+
+// deliverDue delivers what is due for each webhook in turn, returning the
+// shortest wait for the next delivery.
+func deliverDue(d *Dispatcher) time.Duration {
+	d.mut.Lock()
+	queues := make([]*hookQueue, 0, len(d.queues))
+	for _, q := range d.queues {
+		queues = append(queues, q)
+	}
+	d.mut.Unlock()
+
+	next := maxBackoff
+	for _, q := range queues {
+		next = min(next, d.deliverDue(context.Background(), q))
+	}
+	return next
+}
+
+func TestFilterAndSign(t *testing.T) {
+	t.Parallel()
+
+	recv := newReceiver(t)
+	d := newTestDispatcher(backend.OpenMemory(), config.Webhook{
+		URL:     recv.URL,
+		Secret:  "secret",
+		Events:  []string{"FolderPaused", "ItemFinished"},
+		Folders: []string{"a"},
+		Devices: []protocol.DeviceID{protocol.LocalDeviceID},
+	})
+
+	d.handle(events.Event{Type: events.FolderPaused, Data: map[string]string{"id": "b"}})
+	d.handle(events.Event{Type: events.DeviceConnected, Data: map[string]string{"id": protocol.LocalDeviceID.String()}})
+	d.handle(events.Event{Type: events.ItemFinished, Data: map[string]string{"folder": "a", "device": protocol.EmptyDeviceID.String()}})
+	d.handle(events.Event{GlobalID: 42, Type: events.FolderPaused, Data: map[string]string{"id": "a"}})
+
+	if wait := deliverDue(d); wait != maxBackoff {
+		t.Error("expected nothing more to do, got", wait)
+	}
+	if len(recv.requests) != 2 {
+		t.Fatal("expected two events to be delivered, got", len(recv.requests))
+	}
+
+	req, body := recv.requests[1], recv.bodies[1]
+	if req.Header.Get("X-Syncthing-Event") != "FolderPaused" {
+		t.Error("unexpected event type", req.Header.Get("X-Syncthing-Event"))
+	}
+	if sig := req.Header.Get("X-Syncthing-Signature"); sig != Signature("secret", body) {
+		t.Error("unexpected signature", sig)
+	}
+	var ev events.Event
+	if err := json.Unmarshal(body, &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.GlobalID != 42 || ev.Type != events.FolderPaused {
+		t.Error("unexpected event", ev)
+	}
+	if d.queued != 0 {
+		t.Error("expected the queue to be empty, got", d.queued)
+	}
+}
+
+func TestRetryAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	recv := newReceiver(t, http.StatusServiceUnavailable)
+	hook := config.Webhook{URL: recv.URL}
+	db := backend.OpenMemory()
+	d := newTestDispatcher(db, hook)
+
+	d.handle(events.Event{GlobalID: 1, Type: events.StartupComplete})
+	d.handle(events.Event{GlobalID: 2, Type: events.ItemFinished})
+	wait := deliverDue(d)
+	if wait <= 0 || wait > minBackoff {
+		t.Fatal("expected to wait for the retry, got", wait)
+	}
+	if len(recv.requests) != 1 {
+		t.Fatal("expected a single attempt while the first event is failing, got", len(recv.requests))
+	}
+
+	// The events are still there after a restart.
+	d = newTestDispatcher(db, hook)
+	if d.queued != 2 || d.nextSeq != 2 {
+		t.Fatalf("expected two queued events, got %d (next %d)", d.queued, d.nextSeq)
+	}
+
+	time.Sleep(wait)
+	deliverDue(d)
+	if len(recv.requests) != 3 {
+		t.Fatal("expected both events to be delivered, got", len(recv.requests)-1)
+	}
+	for i, globalID := range []int{1, 1, 2} {
+		var ev events.Event
+		if err := json.Unmarshal(recv.bodies[i], &ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.GlobalID != globalID {
+			t.Errorf("request %d: expected event %d, got %d", i, globalID, ev.GlobalID)
+		}
+	}
+	if recv.requests[0].Header.Get("X-Syncthing-Signature") != "" {
+		t.Error("expected no signature without a secret")
+	}
+}
+
+func TestPermanentFailure(t *testing.T) {
+	t.Parallel()
+
+	recv := newReceiver(t, http.StatusNotFound)
+	d := newTestDispatcher(backend.OpenMemory(), config.Webhook{URL: recv.URL})
+
+	d.handle(events.Event{Type: events.StartupComplete})
+	d.handle(events.Event{Type: events.ItemFinished})
+	deliverDue(d)
+	if len(recv.requests) != 2 {
+		t.Fatal("expected the first event to be dropped and the next delivered, got", len(recv.requests))
+	}
+	if d.queued != 0 {
+		t.Error("expected the queue to be empty, got", d.queued)
+	}
+}
+
+func TestRemovedWebhook(t *testing.T) {
+	t.Parallel()
+
+	db := backend.OpenMemory()
+	d := newTestDispatcher(db, config.Webhook{URL: "http://127.0.0.1:1/"})
+	d.handle(events.Event{Type: events.StartupComplete})
+
+	d = newTestDispatcher(db)
+	deliverDue(d)
+	if pending, _ := d.pending(); len(pending) != 0 {
+		t.Error("expected the events of removed webhooks to be dropped, got", pending)
+	}
+	if d.queued != 0 {
+		t.Error("expected the queue to be empty, got", d.queued)
+	}
+}
+
+func TestRemovedWebhookLoop(t *testing.T) {
+	t.Parallel()
+
+	recv := newReceiver(t, http.StatusServiceUnavailable)
+	d := newTestDispatcher(backend.OpenMemory(), config.Webhook{URL: recv.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Serve(ctx)
+	d.handle(events.Event{Type: events.StartupComplete})
+
+	waitServices := func(n int) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for len(d.Services()) != n {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d services, got %v", n, d.Services())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	// Listening, and delivering to the webhook
+	waitServices(2)
+
+	// Removing the webhook stops its delivery loop and forgets its queue.
+	d.CommitConfiguration(config.Configuration{}, config.Configuration{})
+	waitServices(1)
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	if len(d.queues) != 0 || d.queued != 0 {
+		t.Errorf("expected no queues to remain, got %v (%d queued)", d.queues, d.queued)
+	}
+}
+
+func TestUnreadableDelivery(t *testing.T) {
+	t.Parallel()
+
+	db := backend.OpenMemory()
+	d := newTestDispatcher(db, config.Webhook{URL: "http://127.0.0.1:1/"})
+	d.handle(events.Event{Type: events.StartupComplete})
+	if err := db.Put(deliveryKey(d.nextSeq), []byte("not json")); err != nil {
+		t.Fatal(err)
+	}
+
+	d = newTestDispatcher(db, config.Webhook{URL: "http://127.0.0.1:1/"})
+	if d.queued != 1 || d.nextSeq != 1 {
+		t.Errorf("expected the unreadable delivery not to count, got %d queued (next %d)", d.queued, d.nextSeq)
+	}
+}
+
+func TestSlowWebhook(t *testing.T) {
+	t.Parallel()
+
+	// A webhook that doesn't answer doesn't hold up the others.
+	block := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-block
+	}))
+	defer slow.Close()
+	defer close(block)
+	recv := newReceiver(t)
+	d := newTestDispatcher(backend.OpenMemory(), config.Webhook{URL: slow.URL}, config.Webhook{URL: recv.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Serve(ctx)
+	d.handle(events.Event{Type: events.StartupComplete})
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		recv.mut.Lock()
+		n := len(recv.requests)
+		recv.mut.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("event wasn't delivered while another webhook is stuck")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int]time.Duration{
+		1:   time.Second,
+		2:   2 * time.Second,
+		5:   16 * time.Second,
+		20:  maxBackoff,
+		100: maxBackoff,
+	}
+	for attempts, expected := range cases {
+		if wait := backoff(attempts); wait != expected {
+			t.Errorf("backoff(%d) = %v, expected %v", attempts, wait, expected)
+		}
+	}
+}